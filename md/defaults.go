@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rothskeller/photo-tools/md/fields"
+	"github.com/rothskeller/photo-tools/md/operations"
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+/* DEFAULTS FILE FORMAT
+
+Default field values for the media files in a directory are stored in a file
+named .md-defaults in that directory.  Each line of the file contains a field
+name, a colon, and the default value for that field, e.g.:
+
+    copyright: © 2022 Steven Roth
+    license: https://creativecommons.org/licenses/by-nc/4.0/
+
+Blank lines and lines starting with a pound sign (#) are ignored.  Whenever a
+write operation changes a media file, each field named in the defaults file
+that has no value in the media file is given the default value.
+*/
+
+const defaultsFilename = ".md-defaults"
+
+// fieldDefault is a single default value from a defaults file.
+type fieldDefault struct {
+	field fields.Field
+	value interface{}
+}
+
+// defaultsCache caches the contents of the defaults files, keyed by directory
+// name.
+var defaultsCache = map[string][]fieldDefault{}
+
+// applyDefaults sets the default values of any empty fields in the files that
+// were changed by the operation, as given by the defaults files in their
+// directories.  Files that the operation didn't change are left alone.
+func applyDefaults(files []operations.MediaFile) (err error) {
+	for _, file := range files {
+		var defaults []fieldDefault
+
+		if !file.Changed {
+			continue
+		}
+		if defaults, err = readDefaults(filepath.Dir(file.Path)); err != nil {
+			return err
+		}
+		for _, def := range defaults {
+			if len(def.field.GetValues(file.Provider)) != 0 {
+				continue
+			}
+			if err = def.field.SetValues(file.Provider, []interface{}{def.value}); err != nil && err != metadata.ErrNotSupported {
+				return fmt.Errorf("%s: set default %s: %s", file.Path, def.field.PluralName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// readDefaults reads the defaults file in the specified directory, if any.
+func readDefaults(dirname string) (defaults []fieldDefault, err error) {
+	var (
+		fh    *os.File
+		scan  *bufio.Scanner
+		fname string
+		ok    bool
+	)
+	if defaults, ok = defaultsCache[dirname]; ok {
+		return defaults, nil
+	}
+	fname = filepath.Join(dirname, defaultsFilename)
+	if fh, err = os.Open(fname); os.IsNotExist(err) {
+		defaultsCache[dirname] = nil
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	scan = bufio.NewScanner(fh)
+	for lnum := 1; scan.Scan(); lnum++ {
+		var (
+			field fields.Field
+			value interface{}
+		)
+		line := strings.TrimSpace(scan.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		name, valstr, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected \"field: value\"", fname, lnum)
+		}
+		if field = fields.ParseField(strings.TrimSpace(name)); field == nil {
+			return nil, fmt.Errorf("%s:%d: no such field %q", fname, lnum, strings.TrimSpace(name))
+		}
+		if value, err = field.ParseValue(strings.TrimSpace(valstr)); err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", fname, lnum, err)
+		}
+		defaults = append(defaults, fieldDefault{field, value})
+	}
+	if err = scan.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %s", fname, err)
+	}
+	defaultsCache[dirname] = defaults
+	return defaults, nil
+}
@@ -0,0 +1,19 @@
+package fields
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// CameraField is the field handler for the camera field, which identifies the
+// camera that captured the media.  It is read-only.
+var CameraField Field = &readonlyStringField{
+	stringField{
+		baseField{
+			name:       "camera",
+			pluralName: "camera",
+			label:      "Camera",
+			shortLabel: "Cm",
+		},
+	},
+	metadata.Provider.Camera, metadata.Provider.CameraTags,
+}
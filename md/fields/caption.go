@@ -56,3 +56,15 @@ func (f *captionField) SetValues(p metadata.Provider, v []interface{}) error {
 		return errors.New("caption cannot have multiple values")
 	}
 }
+
+// Languages returns the languages for which the field has a value other than
+// the default value.
+func (f *captionField) Languages(p metadata.Provider) []string {
+	return altLanguages(p.CaptionAlt())
+}
+
+// WithLanguage returns a field handler for the specified language alternative
+// of the field.
+func (f *captionField) WithLanguage(lang string) Field {
+	return newLangField(f, lang, metadata.Provider.CaptionAlt, metadata.Provider.SetCaptionAlt)
+}
@@ -0,0 +1,59 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type copyrightField struct {
+	stringField
+}
+
+// CopyrightField is the field handler for the Copyright field, i.e., the
+// copyright notice for the media.
+var CopyrightField Field = &copyrightField{
+	stringField{
+		baseField{
+			name:       "copyright",
+			pluralName: "copyright",
+			label:      "Copyright",
+			shortLabel: "CP",
+			expected:   true,
+		},
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *copyrightField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.Copyright(); value != "" {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *copyrightField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.CopyrightTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = stringSliceToInterfaceSlice(values[i])
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *copyrightField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return p.SetCopyright("")
+	case 1:
+		return p.SetCopyright(v[0].(string))
+	default:
+		return errors.New("copyright cannot have multiple values")
+	}
+}
@@ -0,0 +1,58 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type creditField struct {
+	stringField
+}
+
+// CreditField is the field handler for the Credit field, i.e., the credit line
+// to be used when the media is published.
+var CreditField Field = &creditField{
+	stringField{
+		baseField{
+			name:       "credit",
+			pluralName: "credit",
+			label:      "Credit",
+			shortLabel: "CD",
+		},
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *creditField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.Credit(); value != "" {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *creditField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.CreditTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = stringSliceToInterfaceSlice(values[i])
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *creditField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return p.SetCredit("")
+	case 1:
+		return p.SetCredit(v[0].(string))
+	default:
+		return errors.New("credit cannot have multiple values")
+	}
+}
@@ -0,0 +1,71 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type dimensionsField struct {
+	baseField
+}
+
+// DimensionsField is the field handler for the dimensions field, which contains
+// the pixel dimensions of the image.  It is read-only.
+var DimensionsField Field = &dimensionsField{
+	baseField{
+		name:       "dimensions",
+		pluralName: "dimensions",
+		label:      "Dimensions",
+		shortLabel: "DI",
+	},
+}
+
+// ParseValue parses a string and returns a value for the field.  It returns an
+// error if the string is invalid.
+func (f *dimensionsField) ParseValue(s string) (interface{}, error) {
+	var d metadata.Dimensions
+	if err := d.Parse(s); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// RenderValue takes a value for the field and renders it in string form for
+// display.
+func (f *dimensionsField) RenderValue(v interface{}) string { return v.(metadata.Dimensions).String() }
+
+// EmptyValue returns whether a value for the field is empty.
+func (f *dimensionsField) EmptyValue(v interface{}) bool { return v.(metadata.Dimensions).Empty() }
+
+// EqualValue compares two values for equality.
+func (f *dimensionsField) EqualValue(a interface{}, b interface{}) bool {
+	return a.(metadata.Dimensions) == b.(metadata.Dimensions)
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *dimensionsField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.Dimensions(); !value.Empty() {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *dimensionsField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.DimensionsTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = []interface{}{values[i]}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *dimensionsField) SetValues(p metadata.Provider, v []interface{}) error {
+	return errors.New("dimensions is read-only")
+}
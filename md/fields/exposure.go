@@ -0,0 +1,87 @@
+package fields
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type exposureField struct {
+	floatField
+}
+
+// ExposureField is the field handler for the exposure field, which contains
+// the exposure time of the media, in seconds.  It is read-only.
+var ExposureField Field = &exposureField{
+	floatField{
+		baseField{
+			name:       "exposure",
+			pluralName: "exposure",
+			label:      "Exposure",
+			shortLabel: "EX",
+		},
+		"", "s",
+	},
+}
+
+// ParseValue parses a string and returns a value for the field.  It returns an
+// error if the string is invalid.  In addition to plain numbers of seconds, it
+// accepts fractions of a second, such as "1/250".
+func (f *exposureField) ParseValue(s string) (interface{}, error) {
+	s = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "s")
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err1 := strconv.ParseFloat(strings.TrimSpace(num), 64)
+		d, err2 := strconv.ParseFloat(strings.TrimSpace(den), 64)
+		if err1 == nil && err2 == nil && n > 0 && d > 0 {
+			return n / d, nil
+		}
+		return nil, errors.New("exposure must be a positive number of seconds or a fraction")
+	}
+	return f.floatField.ParseValue(s)
+}
+
+// RenderValue takes a value for the field and renders it in string form for
+// display.  Exposure times shorter than a third of a second are rendered as
+// fractions, the way cameras display them.
+func (f *exposureField) RenderValue(v interface{}) string {
+	if t := v.(float64); t > 0 && t < 0.3 {
+		return "1/" + strconv.Itoa(int(math.Round(1/t)))
+	}
+	return f.floatField.RenderValue(v)
+}
+
+// EqualValue compares two values for equality.  Values are equal if they render
+// the same way.
+func (f *exposureField) EqualValue(a interface{}, b interface{}) bool {
+	return f.RenderValue(a) == f.RenderValue(b)
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *exposureField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.ExposureTime(); value != 0 {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *exposureField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.ExposureTimeTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = []interface{}{values[i]}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *exposureField) SetValues(p metadata.Provider, v []interface{}) error {
+	return errors.New("exposure is read-only")
+}
@@ -6,27 +6,55 @@ import (
 
 // facesField is the field handler for face regions in the media.
 type facesField struct {
-	stringField
+	baseField
 }
 
 // FacesField is the field handler for face regions in the media.
 var FacesField Field = &facesField{
-	stringField{
-		baseField{
-			name:        "face",
-			pluralName:  "faces",
-			label:       "Face",
-			shortLabel:  " F",
-			multivalued: true,
-		},
+	baseField{
+		name:        "face",
+		pluralName:  "faces",
+		label:       "Face",
+		shortLabel:  " F",
+		multivalued: true,
 	},
 }
 
+// ParseValue parses a string and returns a value for the field.  It returns an
+// error if the string is invalid.
+func (f *facesField) ParseValue(s string) (interface{}, error) {
+	var face metadata.FaceRegion
+	if err := face.Parse(s); err != nil {
+		return nil, err
+	}
+	return face, nil
+}
+
+// RenderValue takes a value for the field and renders it in string form for
+// display.
+func (f *facesField) RenderValue(v interface{}) string {
+	return v.(metadata.FaceRegion).String()
+}
+
+// EmptyValue returns whether a value for the field is empty.
+func (f *facesField) EmptyValue(v interface{}) bool { return v.(metadata.FaceRegion).Empty() }
+
+// EqualValue compares two values for equality.  A face region without an area
+// is considered equal to any region with the same name; this allows face
+// regions to be removed by name.
+func (f *facesField) EqualValue(a interface{}, b interface{}) bool {
+	fa, fb := a.(metadata.FaceRegion), b.(metadata.FaceRegion)
+	if !fa.HasArea() || !fb.HasArea() {
+		return fa.Name == fb.Name
+	}
+	return fa.Equivalent(fb)
+}
+
 // GetValues returns all of the values of the field.  (For single-valued fields,
 // the return slice will have at most one entry.)  Empty values should not be
 // included.
 func (f *facesField) GetValues(p metadata.Provider) []interface{} {
-	return stringSliceToInterfaceSlice(p.Faces())
+	return faceSliceToInterfaceSlice(p.Faces())
 }
 
 // GetTags returns the names of all of the metadata tags that correspond to the
@@ -36,16 +64,66 @@ func (f *facesField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
 	tags, values := p.FacesTags()
 	ivals := make([][]interface{}, len(values))
 	for i := range values {
-		ivals[i] = stringSliceToInterfaceSlice(values[i])
+		ivals[i] = faceSliceToInterfaceSlice(values[i])
 	}
 	return tags, ivals
 }
 
-// SetValues sets all of the values of the field.
+// SetValues sets all of the values of the field.  The people named in the face
+// regions are added to the people field if they aren't already in it.
 func (f *facesField) SetValues(p metadata.Provider, v []interface{}) error {
-	values := make([]string, len(v))
+	values := make([]metadata.FaceRegion, len(v))
 	for i := range v {
-		values[i] = v[i].(string)
+		values[i] = v[i].(metadata.FaceRegion)
+	}
+	if err := p.SetFaces(values); err != nil {
+		return err
+	}
+	return addFacePeople(p, values)
+}
+
+// addFacePeople adds the people named in the face regions to the people field,
+// in every tag that holds it, if they aren't already there.
+func addFacePeople(p metadata.Provider, faces []metadata.FaceRegion) error {
+	var (
+		people  = p.People()
+		missing = false
+	)
+	_, tagValues := p.PeopleTags()
+	for _, face := range faces {
+		if face.Name == "" {
+			continue
+		}
+		if !containsString(people, face.Name) {
+			people = append(people, face.Name)
+			missing = true
+		}
+		for _, tv := range tagValues {
+			if !containsString(tv, face.Name) {
+				missing = true
+			}
+		}
+	}
+	if !missing {
+		return nil
+	}
+	return p.SetPeople(people)
+}
+
+// containsString returns whether the list contains the string.
+func containsString(list []string, s string) bool {
+	for _, ls := range list {
+		if ls == s {
+			return true
+		}
+	}
+	return false
+}
+
+func faceSliceToInterfaceSlice(faces []metadata.FaceRegion) (is []interface{}) {
+	is = make([]interface{}, len(faces))
+	for i, face := range faces {
+		is[i] = face
 	}
-	return p.SetFaces(values)
+	return is
 }
@@ -2,6 +2,8 @@
 package fields
 
 import (
+	"strings"
+
 	"github.com/rothskeller/photo-tools/metadata"
 )
 
@@ -80,33 +82,77 @@ func (f *baseField) Expected() bool { return f.expected }
 
 // ParseField parses a string to see if it is a recognized field name or
 // abbreviation.  If so, it returns the corresponding field handler.  Otherwise
-// it returns nil.  This function does not handle "all".
+// it returns nil.  This function does not handle "all".  A field name may be
+// followed by an at sign and a language tag (e.g., "title@de") to select a
+// language alternative of a field that has them.
 func ParseField(arg string) Field {
+	if name, lang, ok := strings.Cut(arg, "@"); ok {
+		if field, ok := ParseField(name).(LanguageField); ok && lang != "" {
+			return field.WithLanguage(lang)
+		}
+		return nil
+	}
 	switch arg {
 	case "artist", "a", "ar", "art", "arti", "artis":
 		return ArtistField
+	case "camera", "cam", "came", "camer":
+		return CameraField
 	case "caption", "c", "ca", "cap", "capt", "capti", "captio":
 		return CaptionField
+	case "copyright", "co", "cop", "copy", "copyr", "copyri", "copyrig", "copyrigh":
+		return CopyrightField
+	case "credit", "cr", "cre", "cred", "credi":
+		return CreditField
 	case "datetime", "d", "da", "dat", "date", "datet", "dateti", "datetim", "dt", "time", "tim":
 		return DateTimeField
+	case "dimensions", "di", "dim", "dime", "dimen", "dimens", "dimensi", "dimensio", "dimension", "size", "si", "siz":
+		return DimensionsField
+	case "exposure", "e", "ex", "exp", "expo", "expos", "exposu", "exposur", "shutter", "sh", "shu", "shut", "shutt", "shutte":
+		return ExposureField
 	case "faces", "f", "fa", "fac", "face":
 		return FacesField
+	case "fnumber", "fn", "fnu", "fnum", "fnumb", "fnumbe", "aperture", "ap", "ape", "aper", "apert", "apertu", "apertur":
+		return FNumberField
+	case "focal", "fo", "foc", "foca", "focallength":
+		return FocalLengthField
 	case "gps", "gp":
 		return GPSField
+	case "gpstime", "gpst", "gpsti", "gpstim", "gpsdatetime":
+		return GPSDateTimeField
 	case "groups", "gr", "gro", "grou", "group":
 		return GroupsField
+	case "iso", "i", "is":
+		return ISOField
 	case "keywords", "k", "ke", "key", "keyw", "keywo", "keywor", "keyword", "kw":
 		return KeywordsField
+	case "label", "la", "lab", "labe":
+		return LabelField
+	case "lens", "le", "len":
+		return LensField
+	case "license", "li", "lic", "lice", "licen", "licens":
+		return LicenseField
 	case "location", "l", "lo", "loc", "loca", "locat", "locati", "locatio":
 		return LocationField
+	case "orientation", "or", "ori", "orie", "orien", "orient", "orienta", "orientat", "orientati", "orientatio":
+		return OrientationField
 	case "person", "pe", "per", "pers", "perso", "people", "peo", "peop", "peopl":
 		return PeopleField
 	case "places", "pl", "pla", "plac", "place":
 		return PlacesField
+	case "rating", "r", "ra", "rat", "rati", "ratin":
+		return RatingField
+	case "serial", "ser", "seri", "seria":
+		return SerialField
+	case "shown", "sho", "show":
+		return ShownField
+	case "source", "so", "sou", "sour", "sourc":
+		return SourceField
 	case "title", "tit", "titl":
 		return TitleField
 	case "topics", "to", "top", "topi", "topic":
 		return TopicsField
+	case "usage", "us", "usa", "usag", "usageterms", "terms":
+		return UsageField
 	}
 	return nil
 }
@@ -0,0 +1,46 @@
+package fields
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// floatField provides methods that all floating point fields have in common.
+// The values are rendered with one decimal place, between the field's prefix
+// and suffix (e.g., "f/" or "mm").
+type floatField struct {
+	baseField
+	prefix string
+	suffix string
+}
+
+// ParseValue parses a string and returns a value for the field.  It returns an
+// error if the string is invalid.
+func (f *floatField) ParseValue(s string) (interface{}, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, f.prefix), f.suffix))
+	if v, err := strconv.ParseFloat(s, 64); err == nil && v > 0 {
+		return v, nil
+	}
+	return nil, errors.New(f.name + " must be a positive number")
+}
+
+// RenderValue takes a value for the field and renders it in string form for
+// display.
+func (f *floatField) RenderValue(v interface{}) string {
+	if v.(float64) == 0 {
+		return ""
+	}
+	return f.prefix + strconv.FormatFloat(math.Round(v.(float64)*10)/10, 'f', -1, 64) + f.suffix
+}
+
+// EmptyValue returns whether a value for the field is empty.
+func (f *floatField) EmptyValue(v interface{}) bool { return v.(float64) == 0 }
+
+// EqualValue compares two values for equality.  Values are equal if they render
+// the same way.
+func (f *floatField) EqualValue(a interface{}, b interface{}) bool {
+	return f.RenderValue(a) == f.RenderValue(b)
+}
@@ -0,0 +1,53 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type fNumberField struct {
+	floatField
+}
+
+// FNumberField is the field handler for the fnumber field, which contains the
+// f-number of the aperture with which the media were captured.  It is
+// read-only.
+var FNumberField Field = &fNumberField{
+	floatField{
+		baseField{
+			name:       "fnumber",
+			pluralName: "fnumber",
+			label:      "F-Number",
+			shortLabel: "FN",
+		},
+		"f/", "",
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *fNumberField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.FNumber(); value != 0 {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *fNumberField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.FNumberTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = []interface{}{values[i]}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *fNumberField) SetValues(p metadata.Provider, v []interface{}) error {
+	return errors.New("fnumber is read-only")
+}
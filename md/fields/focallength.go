@@ -0,0 +1,53 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type focalLengthField struct {
+	floatField
+}
+
+// FocalLengthField is the field handler for the focal field, which contains the
+// focal length of the lens with which the media were captured, in millimeters.
+// It is read-only.
+var FocalLengthField Field = &focalLengthField{
+	floatField{
+		baseField{
+			name:       "focal",
+			pluralName: "focal",
+			label:      "Focal Length",
+			shortLabel: "FL",
+		},
+		"", "mm",
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *focalLengthField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.FocalLength(); value != 0 {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *focalLengthField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.FocalLengthTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = []interface{}{values[i]}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *focalLengthField) SetValues(p metadata.Provider, v []interface{}) error {
+	return errors.New("focal is read-only")
+}
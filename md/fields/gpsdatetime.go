@@ -0,0 +1,51 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type gpsdatetimeField struct {
+	datetimeField
+}
+
+// GPSDateTimeField is the field handler for the gpstime field, which contains
+// the UTC date and time reported by the GPS receiver.  It is read-only.
+var GPSDateTimeField Field = &gpsdatetimeField{
+	datetimeField{
+		baseField{
+			name:       "gpstime",
+			pluralName: "gpstime",
+			label:      "GPS Date/Time",
+			shortLabel: "GT",
+		},
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *gpsdatetimeField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.GPSDateTime(); !value.Empty() {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *gpsdatetimeField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.GPSDateTimeTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = []interface{}{values[i]}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *gpsdatetimeField) SetValues(p metadata.Provider, v []interface{}) error {
+	return errors.New("gpstime is read-only")
+}
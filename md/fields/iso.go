@@ -0,0 +1,74 @@
+package fields
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type isoField struct {
+	baseField
+}
+
+// ISOField is the field handler for the iso field, which contains the ISO speed
+// rating with which the media were captured.  It is read-only.
+var ISOField Field = &isoField{
+	baseField{
+		name:       "iso",
+		pluralName: "iso",
+		label:      "ISO",
+		shortLabel: "IS",
+	},
+}
+
+// ParseValue parses a string and returns a value for the field.  It returns an
+// error if the string is invalid.
+func (f *isoField) ParseValue(s string) (interface{}, error) {
+	if v, err := strconv.Atoi(s); err == nil && v > 0 {
+		return v, nil
+	}
+	return nil, errors.New("iso must be a positive integer")
+}
+
+// RenderValue takes a value for the field and renders it in string form for
+// display.
+func (f *isoField) RenderValue(v interface{}) string {
+	if v.(int) == 0 {
+		return ""
+	}
+	return strconv.Itoa(v.(int))
+}
+
+// EmptyValue returns whether a value for the field is empty.
+func (f *isoField) EmptyValue(v interface{}) bool { return v.(int) == 0 }
+
+// EqualValue compares two values for equality.
+func (f *isoField) EqualValue(a interface{}, b interface{}) bool { return a.(int) == b.(int) }
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *isoField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.ISO(); value != 0 {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *isoField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.ISOTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = []interface{}{values[i]}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *isoField) SetValues(p metadata.Provider, v []interface{}) error {
+	return errors.New("iso is read-only")
+}
@@ -0,0 +1,58 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type labelField struct {
+	stringField
+}
+
+// LabelField is the field handler for the label field, which contains the
+// color label (e.g. "Red") assigned to the media.
+var LabelField Field = &labelField{
+	stringField{
+		baseField{
+			name:       "label",
+			pluralName: "label",
+			label:      "Label",
+			shortLabel: "Lb",
+		},
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *labelField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.Label(); value != "" {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *labelField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.LabelTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = []interface{}{values[i]}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *labelField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return p.SetLabel("")
+	case 1:
+		return p.SetLabel(v[0].(string))
+	default:
+		return errors.New("label cannot have multiple values")
+	}
+}
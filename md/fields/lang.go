@@ -0,0 +1,91 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// LanguageField is the interface honored by fields that have language
+// alternatives.  The field itself handles the default language; the field
+// returned by WithLanguage handles a specific language.
+type LanguageField interface {
+	Field
+	// Languages returns the languages for which the field has a value
+	// other than the default value.
+	Languages(p metadata.Provider) []string
+	// WithLanguage returns a field handler for the specified language
+	// alternative of the field.
+	WithLanguage(lang string) Field
+}
+
+// langField is the field handler for a single language alternative of a field
+// that has them.
+type langField struct {
+	stringField
+	lang string
+	get  func(metadata.Provider) metadata.AltString
+	set  func(metadata.Provider, metadata.AltString) error
+}
+
+// newLangField returns the handler for the specified language alternative of
+// the base field, which uses the specified functions to get and set the
+// language alternatives.
+func newLangField(
+	base Field, lang string,
+	get func(metadata.Provider) metadata.AltString, set func(metadata.Provider, metadata.AltString) error,
+) Field {
+	return &langField{
+		stringField{
+			baseField{
+				name:       base.Name() + "@" + lang,
+				pluralName: base.PluralName() + "@" + lang,
+				label:      base.Label() + "@" + lang,
+				shortLabel: base.ShortLabel(),
+			},
+		},
+		lang, get, set,
+	}
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *langField) GetValues(p metadata.Provider) []interface{} {
+	if value := f.get(p).Get(f.lang); value != "" {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).  Language alternatives are not tracked per
+// tag, so this returns nothing.
+func (f *langField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	return nil, nil
+}
+
+// SetValues sets all of the values of the field.  The values of the other
+// languages are not changed.
+func (f *langField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return f.set(p, f.get(p).Set(f.lang, ""))
+	case 1:
+		return f.set(p, f.get(p).Set(f.lang, v[0].(string)))
+	default:
+		return errors.New(f.name + " cannot have multiple values")
+	}
+}
+
+// altLanguages returns the languages in the AltString whose values differ from
+// the default.
+func altLanguages(as metadata.AltString) (langs []string) {
+	for i, alt := range as {
+		if i != 0 && alt.Lang != "x-default" && alt.Value != as.Default() {
+			langs = append(langs, alt.Lang)
+		}
+	}
+	return langs
+}
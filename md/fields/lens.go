@@ -0,0 +1,19 @@
+package fields
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// LensField is the field handler for the lens field, which identifies the lens
+// with which the media were captured.  It is read-only.
+var LensField Field = &readonlyStringField{
+	stringField{
+		baseField{
+			name:       "lens",
+			pluralName: "lens",
+			label:      "Lens",
+			shortLabel: "Ln",
+		},
+	},
+	metadata.Provider.Lens, metadata.Provider.LensTags,
+}
@@ -0,0 +1,59 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type licenseField struct {
+	stringField
+}
+
+// LicenseField is the field handler for the License field, i.e., the URL of
+// the license under which the media may be used (often a Creative Commons
+// license).
+var LicenseField Field = &licenseField{
+	stringField{
+		baseField{
+			name:       "license",
+			pluralName: "license",
+			label:      "License",
+			shortLabel: "LI",
+		},
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *licenseField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.License(); value != "" {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *licenseField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.LicenseTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = stringSliceToInterfaceSlice(values[i])
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *licenseField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return p.SetLicense("")
+	case 1:
+		return p.SetLicense(v[0].(string))
+	default:
+		return errors.New("license cannot have multiple values")
+	}
+}
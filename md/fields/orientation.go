@@ -0,0 +1,88 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type orientationField struct {
+	baseField
+}
+
+// OrientationField is the field handler for the orientation field, which
+// indicates how the image data must be rotated or flipped for display.
+var OrientationField Field = &orientationField{
+	baseField{
+		name:       "orientation",
+		pluralName: "orientation",
+		label:      "Orientation",
+		shortLabel: "OR",
+	},
+}
+
+// ParseValue parses a string and returns a value for the field.  It returns an
+// error if the string is invalid.
+func (f *orientationField) ParseValue(s string) (interface{}, error) {
+	var o metadata.Orientation
+	if err := o.Parse(s); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// RenderValue takes a value for the field and renders it in string form for
+// display.
+func (f *orientationField) RenderValue(v interface{}) string {
+	if v.(metadata.Orientation) == 0 {
+		return ""
+	}
+	return v.(metadata.Orientation).String()
+}
+
+// EmptyValue returns whether a value for the field is empty.
+func (f *orientationField) EmptyValue(v interface{}) bool {
+	o := v.(metadata.Orientation)
+	return o == 0 || o == metadata.Rotate0
+}
+
+// EqualValue compares two values for equality.
+func (f *orientationField) EqualValue(a interface{}, b interface{}) bool {
+	return f.EmptyValue(a) && f.EmptyValue(b) || a.(metadata.Orientation) == b.(metadata.Orientation)
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *orientationField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.Orientation(); !f.EmptyValue(value) {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *orientationField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.OrientationTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		for _, v := range values[i] {
+			ilist[i] = append(ilist[i], v)
+		}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *orientationField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return p.SetOrientation(metadata.Rotate0)
+	case 1:
+		return p.SetOrientation(v[0].(metadata.Orientation))
+	default:
+		return errors.New("orientation cannot have multiple values")
+	}
+}
@@ -35,7 +35,7 @@ func (f *peopleField) GetValuesNoFaces(p metadata.Provider) []interface{} {
 	var faces = p.Faces()
 	var facemap = make(map[string]bool, len(faces))
 	for _, face := range faces {
-		facemap[face] = true
+		facemap[face.Name] = true
 	}
 	var ifcs = make([]interface{}, 0, len(people))
 	for _, person := range people {
@@ -0,0 +1,98 @@
+package fields
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type ratingField struct {
+	baseField
+}
+
+// RatingField is the field handler for the rating field, which contains a
+// star rating for the media from 1 to 5, or -1 if the media were rejected.
+var RatingField Field = &ratingField{
+	baseField{
+		name:       "rating",
+		pluralName: "rating",
+		label:      "Rating",
+		shortLabel: " R",
+	},
+}
+
+// ParseValue parses a string and returns a value for the field.  It returns an
+// error if the string is invalid.
+func (f *ratingField) ParseValue(s string) (interface{}, error) {
+	if s == "reject" || s == "rejected" {
+		return -1, nil
+	}
+	if v, err := strconv.Atoi(s); err == nil && v >= -1 && v <= 5 {
+		return v, nil
+	}
+	return nil, errors.New("rating must be 1 to 5, or -1 or \"reject\" for rejected media")
+}
+
+// RenderValue takes a value for the field and renders it in string form for
+// display.
+func (f *ratingField) RenderValue(v interface{}) string {
+	switch v := v.(int); {
+	case v < 0:
+		return "rejected"
+	case v == 0:
+		return ""
+	default:
+		return strconv.Itoa(v)
+	}
+}
+
+// EmptyValue returns whether a value for the field is empty.
+func (f *ratingField) EmptyValue(v interface{}) bool { return v.(int) == 0 }
+
+// EqualValue compares two values for equality.
+func (f *ratingField) EqualValue(a interface{}, b interface{}) bool { return a.(int) == b.(int) }
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *ratingField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.Rating(); value != 0 {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *ratingField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	var (
+		ilist    [][]interface{}
+		tlist    []string
+		rejected = p.Rating() < 0
+	)
+	tags, values := p.RatingTags()
+	for i := range values {
+		// The Windows tags can't represent rejected media, so when the
+		// media are rejected, we don't list those that are empty.
+		if rejected && values[i] == 0 {
+			continue
+		}
+		tlist = append(tlist, tags[i])
+		ilist = append(ilist, []interface{}{values[i]})
+	}
+	return tlist, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *ratingField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return p.SetRating(0)
+	case 1:
+		return p.SetRating(v[0].(int))
+	default:
+		return errors.New("rating cannot have multiple values")
+	}
+}
@@ -0,0 +1,19 @@
+package fields
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// SerialField is the field handler for the serial field, which contains the
+// serial number of the camera body.  It is read-only.
+var SerialField Field = &readonlyStringField{
+	stringField{
+		baseField{
+			name:       "serial",
+			pluralName: "serial",
+			label:      "Serial #",
+			shortLabel: "SN",
+		},
+	},
+	metadata.Provider.Serial, metadata.Provider.SerialTags,
+}
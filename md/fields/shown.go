@@ -0,0 +1,60 @@
+package fields
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type shownField struct {
+	locationField
+}
+
+// ShownField is the field handler for the shown field, which gives textual
+// descriptions of the locations depicted in the media (as opposed to the
+// location field, which is where the media were captured).
+var ShownField Field = &shownField{
+	locationField{
+		baseField{
+			name:        "shown",
+			pluralName:  "shown",
+			label:       "Shown",
+			shortLabel:  "SH",
+			multivalued: true,
+		},
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *shownField) GetValues(p metadata.Provider) []interface{} {
+	var values = p.LocationsShown()
+	var ivals = make([]interface{}, len(values))
+	for i := range values {
+		ivals[i] = values[i]
+	}
+	return ivals
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *shownField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.LocationsShownTags()
+	var ivals = make([][]interface{}, len(values))
+	for i := range values {
+		ivals[i] = make([]interface{}, len(values[i]))
+		for j := range values[i] {
+			ivals[i][j] = values[i][j]
+		}
+	}
+	return tags, ivals
+}
+
+// SetValues sets all of the values of the field.
+func (f *shownField) SetValues(p metadata.Provider, v []interface{}) error {
+	values := make([]metadata.Location, len(v))
+	for i := range v {
+		values[i] = v[i].(metadata.Location)
+	}
+	return p.SetLocationsShown(values)
+}
@@ -0,0 +1,58 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type sourceField struct {
+	stringField
+}
+
+// SourceField is the field handler for the Source field, i.e., the original
+// owner of the copyright of the media.
+var SourceField Field = &sourceField{
+	stringField{
+		baseField{
+			name:       "source",
+			pluralName: "source",
+			label:      "Source",
+			shortLabel: "SO",
+		},
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *sourceField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.Source(); value != "" {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *sourceField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.SourceTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = stringSliceToInterfaceSlice(values[i])
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *sourceField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return p.SetSource("")
+	case 1:
+		return p.SetSource(v[0].(string))
+	default:
+		return errors.New("source cannot have multiple values")
+	}
+}
@@ -1,5 +1,11 @@
 package fields
 
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
 // stringField provides methods that all string fields have in common.
 type stringField struct {
 	baseField
@@ -19,6 +25,41 @@ func (f *stringField) EmptyValue(v interface{}) bool { return v.(string) == "" }
 // EqualValue compares two values for equality.
 func (f *stringField) EqualValue(a interface{}, b interface{}) bool { return a.(string) == b.(string) }
 
+// readonlyStringField is a read-only, single-valued string field, whose value
+// and tags are retrieved with the provider methods in get and tags.
+type readonlyStringField struct {
+	stringField
+	get  func(metadata.Provider) string
+	tags func(metadata.Provider) ([]string, []string)
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *readonlyStringField) GetValues(p metadata.Provider) []interface{} {
+	if value := f.get(p); value != "" {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *readonlyStringField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := f.tags(p)
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = []interface{}{values[i]}
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *readonlyStringField) SetValues(p metadata.Provider, v []interface{}) error {
+	return errors.New(f.name + " is read-only")
+}
+
 // stringSliceToInterfaceSlice is a utility function used by string-valued
 // fields.
 func stringSliceToInterfaceSlice(ss []string) (is []interface{}) {
@@ -56,3 +56,15 @@ func (f *titleField) SetValues(p metadata.Provider, v []interface{}) error {
 		return errors.New("title cannot have multiple values")
 	}
 }
+
+// Languages returns the languages for which the field has a value other than
+// the default value.
+func (f *titleField) Languages(p metadata.Provider) []string {
+	return altLanguages(p.TitleAlt())
+}
+
+// WithLanguage returns a field handler for the specified language alternative
+// of the field.
+func (f *titleField) WithLanguage(lang string) Field {
+	return newLangField(f, lang, metadata.Provider.TitleAlt, metadata.Provider.SetTitleAlt)
+}
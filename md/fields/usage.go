@@ -0,0 +1,58 @@
+package fields
+
+import (
+	"errors"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+type usageField struct {
+	stringField
+}
+
+// UsageField is the field handler for the Usage Terms field, i.e., the terms
+// under which the media may be used.
+var UsageField Field = &usageField{
+	stringField{
+		baseField{
+			name:       "usage",
+			pluralName: "usage",
+			label:      "Usage Terms",
+			shortLabel: "UT",
+		},
+	},
+}
+
+// GetValues returns all of the values of the field.  (For single-valued fields,
+// the return slice will have at most one entry.)  Empty values should not be
+// included.
+func (f *usageField) GetValues(p metadata.Provider) []interface{} {
+	if value := p.UsageTerms(); value != "" {
+		return []interface{}{value}
+	}
+	return nil
+}
+
+// GetTags returns the names of all of the metadata tags that correspond to the
+// field in its first return slice, and a parallel slice of the values of those
+// tags (which may be zero values).
+func (f *usageField) GetTags(p metadata.Provider) ([]string, [][]interface{}) {
+	tags, values := p.UsageTermsTags()
+	ilist := make([][]interface{}, len(values))
+	for i := range values {
+		ilist[i] = stringSliceToInterfaceSlice(values[i])
+	}
+	return tags, ilist
+}
+
+// SetValues sets all of the values of the field.
+func (f *usageField) SetValues(p metadata.Provider, v []interface{}) error {
+	switch len(v) {
+	case 0:
+		return p.SetUsageTerms("")
+	case 1:
+		return p.SetUsageTerms(v[0].(string))
+	default:
+		return errors.New("usage cannot have multiple values")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/rothskeller/photo-tools/md/fields"
+	"github.com/rothskeller/photo-tools/md/operations"
+)
+
+// filterFiles returns those files in which the specified field has a value
+// matching the specified filter string.  A value matches if it is equal to the
+// parsed filter string, or if its rendered form contains the filter string,
+// ignoring case.  The files that don't match are closed.
+func filterFiles(files []operations.MediaFile, field fields.Field, filter string) (matched []operations.MediaFile) {
+	var value, _ = field.ParseValue(filter)
+
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	for _, file := range files {
+		var match bool
+		for _, v := range field.GetValues(file.Provider) {
+			if value != nil && field.EqualValue(v, value) {
+				match = true
+				break
+			}
+			if containsFilter(strings.ToLower(field.RenderValue(v)), filter) {
+				match = true
+				break
+			}
+		}
+		if match {
+			matched = append(matched, file)
+		} else {
+			file.Media.Close()
+		}
+	}
+	return matched
+}
+
+// containsFilter returns whether s contains sub.  A number at the start or end
+// of sub must match a whole number in s, so that "2" does not match "2.8" and
+// "100" does not match "1000".
+func containsFilter(s, sub string) bool {
+	if sub == "" {
+		return false
+	}
+	for start := 0; start <= len(s)-len(sub); {
+		idx := strings.Index(s[start:], sub)
+		if idx < 0 {
+			return false
+		}
+		idx += start
+		end := idx + len(sub)
+		if (!isNumberByte(sub[0]) || idx == 0 || !isNumberByte(s[idx-1])) &&
+			(!isNumberByte(sub[len(sub)-1]) || end == len(s) || !isNumberByte(s[end])) {
+			return true
+		}
+		start = idx + 1
+	}
+	return false
+}
+
+// isNumberByte returns whether b can be part of a number.
+func isNumberByte(b byte) bool { return b == '.' || (b >= '0' && b <= '9') }
+
+// saveFilteredSubset marks the filtered files as the targeted subset of the
+// remembered file set.
+func saveFilteredSubset(matched []operations.MediaFile) {
+	var selmap = make(map[string]bool)
+
+	for _, file := range matched {
+		selmap[file.Path] = true
+	}
+	files := readMDFile()
+	for i := range files {
+		if name := strings.TrimLeft(files[i], "#"); selmap[name] {
+			files[i] = name
+		} else {
+			files[i] = "#" + name
+		}
+	}
+	writeMDFile(files)
+}
@@ -3,11 +3,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 
+	"github.com/rothskeller/photo-tools/md/fields"
 	"github.com/rothskeller/photo-tools/md/operations"
 	"github.com/rothskeller/photo-tools/metadata/filefmts"
 )
@@ -22,6 +26,8 @@ func main() {
 		disallowWrites  bool
 		isWriteOp       bool
 		saveSet         bool
+		filterField     fields.Field
+		filterValue     string
 		err             error
 	)
 	// First, check for files given on the command line.
@@ -52,6 +58,19 @@ func main() {
 		case "select", "sel", "sele", "selec":
 			args = args[1:]
 			fnames, err = selectSubset()
+		case "filter", "fi", "fil", "filt", "filte":
+			if len(args) < 3 {
+				usage()
+			}
+			if filterField = fields.ParseField(args[1]); filterField == nil {
+				fmt.Fprintf(os.Stderr, "ERROR: filter: no such field %q\n", args[1])
+				os.Exit(2)
+			}
+			filterValue = args[2]
+			args = args[3:]
+			if fnames = getTargetedFiles(); len(fnames) == 0 {
+				err = errors.New("no remembered file set")
+			}
 		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
@@ -77,37 +96,42 @@ func main() {
 		sort.Strings(fnames)
 		ignoreNoHandler, disallowWrites, saveSet = true, true, true
 	}
-	// Get a handler and read the metadata for each identified file.
+	// Get a handler and read the metadata for each identified file,
+	// together with its XMP sidecar files.
 	for _, fname := range fnames {
-		var (
-			fh      *os.File
-			handler filefmts.FileFormat
-		)
-		if fh, err = os.Open(fname); err != nil {
-			fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
-			continue
-		}
-		if handler, err = filefmts.HandlerFor(fh); err != nil {
+		var mf *filefmts.MediaFile
+
+		if mf, err = filefmts.OpenMediaFile(fname, filefmts.ImageFirst, filefmts.WriteAll); err != nil {
 			fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
-			fh.Close()
 			sawError = true
 			continue
 		}
-		if handler == nil {
+		if mf == nil {
 			if !ignoreNoHandler {
 				fmt.Fprintf(os.Stderr, "ERROR: %s: not a supported file type\n", fname)
 				sawError = true
 			}
-			fh.Close()
 			continue
 		}
 		files = append(files, operations.MediaFile{
 			Path:     fname,
-			File:     fh,
-			Handler:  handler,
-			Provider: handler.Provider(),
+			Media:    mf,
+			Handler:  mf.Handler(),
+			Provider: mf.Provider(),
 		})
 	}
+	// Sidecar files are handled along with their media files, so they
+	// shouldn't also be handled on their own.
+	files = removeSidecars(files)
+	// If we're filtering, narrow the files to those that match, and make
+	// them the targeted subset.
+	if filterField != nil && len(files) != 0 {
+		if files = filterFiles(files, filterField, filterValue); len(files) == 0 {
+			fmt.Fprintln(os.Stderr, "ERROR: no files match the filter")
+			os.Exit(1)
+		}
+		saveFilteredSubset(files)
+	}
 	// If no successfully read files, exit.
 	if len(files) == 0 {
 		if !sawError {
@@ -135,13 +159,31 @@ func main() {
 			"copy", "co", "cop", "cp",
 			"remove", "rem", "remo", "remov", "rm",
 			"reset", "res", "rese",
+			"rotate", "ro", "rot", "rota", "rotat",
 			"set", "se",
 			"write", "w", "wr", "wri", "writ":
+			isWriteOp = true
+		case "clock", "clo", "cloc",
+			"geotag", "g", "ge", "geo", "geot", "geota",
+			"gphotos", "gp", "gph", "gpho", "gphot", "gphoto",
+			"takeout", "tak", "take", "takeo", "takeou",
+			"timezone", "ti", "tim", "time", "timez", "timezo", "timezon", "tz":
+			// A dry run doesn't change the files.
+			isWriteOp = true
+			for _, arg := range args[1:] {
+				if len(arg) > 0 && strings.HasPrefix("dryrun", arg) {
+					isWriteOp = false
+				}
+			}
+		case "thumb", "th", "thu", "thum":
+			// Extracting a thumbnail doesn't change the file.
+			isWriteOp = len(args) < 2 || !strings.HasPrefix("extract", args[1])
+		}
+		if isWriteOp {
 			if disallowWrites {
 				fmt.Fprintf(os.Stderr, "ERROR: %q operation not allowed when defaulting to all files in directory\n", args[0])
 				os.Exit(2)
 			}
-			isWriteOp = true
 		}
 		switch args[0] {
 		case "add", "ad":
@@ -152,20 +194,34 @@ func main() {
 			err = operations.Choose(args[1:], files)
 		case "clear", "cl", "cle", "clea", "clr":
 			err = operations.Clear(args[1:], files)
+		case "clock", "clo", "cloc":
+			err = operations.Clock(args[1:], files)
 		case "copy", "co", "cop", "cp":
 			err = operations.Copy(args[1:], files)
+		case "geotag", "g", "ge", "geo", "geot", "geota":
+			err = operations.Geotag(args[1:], files)
+		case "gphotos", "gp", "gph", "gpho", "gphot", "gphoto":
+			err = operations.GPhotos(args[1:], files)
 		case "read", "rea", "rd":
 			err = operations.Read(args[1:], files)
 		case "remove", "rem", "remo", "remov", "rm":
 			err = operations.Remove(args[1:], files)
 		case "reset", "res", "rese":
 			err = operations.Reset(args[1:], files)
+		case "rotate", "ro", "rot", "rota", "rotat":
+			err = operations.Rotate(args[1:], files)
 		case "set", "se":
 			err = operations.Set(args[1:], files)
 		case "show", "sh":
 			err = operations.Show(args[1:], files)
 		case "tags", "t", "ta", "tag":
 			err = operations.Tags(args[1:], files)
+		case "takeout", "tak", "take", "takeo", "takeou":
+			err = operations.Takeout(args[1:], files)
+		case "thumb", "th", "thu", "thum":
+			err = operations.Thumb(args[1:], files)
+		case "timezone", "ti", "tim", "time", "timez", "timezo", "timezon", "tz":
+			err = operations.TimeZone(args[1:], files)
 		case "write", "w", "wr", "wri", "writ":
 			err = operations.Write(args[1:], files)
 		default:
@@ -173,16 +229,19 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	if err == nil && isWriteOp {
+		err = applyDefaults(files)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
 		os.Exit(1)
 	}
 	for _, file := range files {
-		if file.Handler.Dirty() {
+		if file.Media.Dirty() {
 			if !isWriteOp {
 				panic("file is dirty after a read operation")
 			}
-			if err = filefmts.Save(file.Handler, file.Path); err != nil {
+			if err = file.Media.Save(); err != nil {
 				fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
 				sawError = true
 			}
@@ -193,14 +252,41 @@ func main() {
 	}
 }
 
+// removeSidecars removes from the list any files that are XMP sidecar files of
+// other files in the list.
+func removeSidecars(files []operations.MediaFile) []operations.MediaFile {
+	var (
+		sidecars = make(map[string]bool)
+		j        int
+	)
+	for _, file := range files {
+		for _, sc := range file.Media.Sidecars() {
+			sidecars[filepath.Clean(sc)] = true
+		}
+	}
+	for _, file := range files {
+		if sidecars[filepath.Clean(file.Path)] {
+			file.Media.Close()
+			continue
+		}
+		files[j] = file
+		j++
+	}
+	return files[:j]
+}
+
 func usage() {
 	fmt.Fprint(os.Stderr, `
 usage: md [file...] [operation]
        md [file-selection] [operation]
-Selections: all batch next prev select
-Operations: add check choose clear copy read remove reset set show tags write
-Fields: artist caption datetime faces gps groups keywords location people
-        places title topics
+Selections: all batch next prev select, filter FIELD VALUE
+Operations: add check choose clear clock copy geotag gphotos read remove reset
+            rotate set show tags takeout thumb timezone write
+Fields: artist caption copyright credit datetime faces gps groups keywords
+        label license location orientation people places rating shown source
+        title topics usage
+Read-only fields: camera dimensions exposure fnumber focal gpstime iso lens
+                  serial
 See MANUAL.md for more details.
 `)
 	os.Exit(2)
@@ -0,0 +1,56 @@
+package operations
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/md/fields"
+	"github.com/rothskeller/photo-tools/metadata/filefmts"
+)
+
+// openFile opens a media file the way md does, failing the test if that fails.
+func openFile(t *testing.T, path string) MediaFile {
+	mf, err := filefmts.OpenMediaFile(path, filefmts.ImageFirst, filefmts.WriteAll)
+	if err != nil || mf == nil {
+		t.Fatalf("OpenMediaFile: %v, %v", mf, err)
+	}
+	t.Cleanup(func() { mf.Close() })
+	return MediaFile{Path: path, Media: mf, Handler: mf.Handler(), Provider: mf.Provider()}
+}
+
+func TestAddFaceAddsPerson(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "IMG_0001.jpg")
+		buf  bytes.Buffer
+	)
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 32, 16)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := []MediaFile{openFile(t, path)}
+	if err := Add([]string{"person", "Bob Jones"}, files); err != nil {
+		t.Fatal(err)
+	}
+	if err := Add([]string{"face", "Jane Doe @ 0.1, 0.1, 0.2, 0.2"}, files); err != nil {
+		t.Fatal(err)
+	}
+	if err := files[0].Media.Save(); err != nil {
+		t.Fatal(err)
+	}
+	file := openFile(t, path)
+	if got := file.Provider.People(); len(got) != 2 || got[0] != "Bob Jones" || got[1] != "Jane Doe" {
+		t.Errorf("people: got %q", got)
+	}
+	if got := checkField(file.Provider, fields.PeopleField, true); got != " 2" {
+		t.Errorf("check people: got %q", got)
+	}
+	if got := checkField(file.Provider, fields.FacesField, true); got != " 1" {
+		t.Errorf("check faces: got %q", got)
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/rothskeller/photo-tools/md/fields"
 	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+	"github.com/rothskeller/photo-tools/metadata/providers/readonly"
 )
 
 var checkFields = []fields.Field{
@@ -23,6 +25,14 @@ var checkFields = []fields.Field{
 	fields.CaptionField,
 	fields.KeywordsField,
 	fields.LocationField,
+	fields.ShownField,
+	fields.RatingField,
+	fields.LabelField,
+	fields.CopyrightField,
+	fields.CreditField,
+	fields.SourceField,
+	fields.UsageField,
+	fields.LicenseField,
 }
 
 // Check displays a table giving the tagging correctness of each field.
@@ -37,13 +47,17 @@ func Check(args []string, files []MediaFile) (err error) {
 	for _, field := range checkFields {
 		fmt.Fprintf(out, "\t%s", field.ShortLabel())
 	}
-	fmt.Fprintln(out)
+	fmt.Fprintln(out, "\tCS")
 	for _, file := range files {
 		fmt.Fprint(out, file.Path)
 		for _, field := range checkFields {
 			fmt.Fprintf(out, "\t%s", checkField(file.Provider, field, true))
 		}
-		fmt.Fprintln(out)
+		if legacyCharset(file.Provider) != "" {
+			fmt.Fprintln(out, "\t[]")
+		} else {
+			fmt.Fprintln(out, "\t  ")
+		}
 	}
 	out.Flush()
 	return nil
@@ -68,6 +82,9 @@ func checkField(p metadata.Provider, field fields.Field, info bool) string {
 	if incorrect {
 		return "[]"
 	}
+	if !congruentToPlaces(p, field, canon) {
+		return "??"
+	}
 	if emptyValues(field, canon) {
 		if field.Expected() {
 			return "--"
@@ -83,6 +100,31 @@ func checkField(p metadata.Provider, field fields.Field, info bool) string {
 	return " ✓"
 }
 
+// congruentToPlaces returns whether the values of the field, if it is one of
+// the location fields, are all congruent to place values.  It returns true for
+// other fields.
+func congruentToPlaces(p metadata.Provider, field fields.Field, values []interface{}) bool {
+	if field != fields.LocationField && field != fields.ShownField {
+		return true
+	}
+	places := p.Places()
+VALUES:
+	for _, v := range values {
+		loc := v.(metadata.Location)
+		// A location with only a country code has nothing to match.
+		if loc.CongruentTo(nil) {
+			continue
+		}
+		for _, place := range places {
+			if loc.CongruentTo(place) {
+				continue VALUES
+			}
+		}
+		return false
+	}
+	return true
+}
+
 func equalValues(field fields.Field, as, bs []interface{}) bool {
 	// First, make sure every non-empty element of as is present in bs.
 	for _, a := range as {
@@ -129,3 +171,22 @@ func emptyValues(field fields.Field, vs []interface{}) bool {
 		return false
 	}
 }
+
+// legacyCharset returns the name of the legacy character set in which the
+// file's IPTC metadata were read (and from which they were transcoded to
+// UTF-8), if any.
+func legacyCharset(p metadata.Provider) string {
+	switch p := p.(type) {
+	case multi.Provider:
+		for _, sp := range p {
+			if cs := legacyCharset(sp); cs != "" {
+				return cs
+			}
+		}
+	case readonly.Provider:
+		return legacyCharset(p.Provider)
+	case interface{ LegacyCharset() string }:
+		return p.LegacyCharset()
+	}
+	return ""
+}
@@ -0,0 +1,236 @@
+package operations
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/timezone"
+)
+
+// minDriftSpan is the minimum time between two samples for them to be used in
+// estimating clock drift.  Samples closer together than that would give wild
+// estimates, since the clock is only recorded to the second.
+const minDriftSpan = time.Hour
+
+// A clockSample is the camera clock error measured on one media file that has
+// both a camera date/time and a GPS date/time.
+type clockSample struct {
+	wall time.Time // camera clock reading
+	err  float64   // camera clock error, in seconds
+}
+
+// A clockBody is the set of media from one camera body.
+type clockBody struct {
+	name    string
+	samples []clockSample
+	others  []int // indexes of media lacking GPS date/time
+	offset  float64
+	drift   float64 // seconds per day
+	start   time.Time
+}
+
+// A clockChange is a proposed correction to the date/time of one media file.
+type clockChange struct {
+	file int
+	dt   metadata.DateTime
+}
+
+// Clock estimates the offset and drift of the clock of each camera body, by
+// comparing the date/time of the media against the GPS date/time of those
+// media that have one.  It then proposes corrections to the date/time of the
+// media from the same body that lack a GPS date/time, and (unless it's a dry
+// run) asks for confirmation before making them.
+func Clock(args []string, files []MediaFile) (err error) {
+	var (
+		bodies  []*clockBody
+		bymap   = make(map[string]*clockBody)
+		changes []clockChange
+		dryRun  bool
+		tw      *tabwriter.Writer
+	)
+	for _, arg := range args {
+		switch arg {
+		case "dryrun", "d", "dr", "dry", "dryr", "dryru":
+			dryRun = true
+		default:
+			return fmt.Errorf("clock: unrecognized argument %q", arg)
+		}
+	}
+	for i, file := range files {
+		dt := file.Provider.DateTime()
+		if dt.Empty() {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: clock: no date/time\n", file.Path)
+			continue
+		}
+		name := strings.TrimSpace(file.Provider.Camera() + " " + file.Provider.Serial())
+		if name == "" {
+			name = "(unknown camera)"
+		}
+		body := bymap[name]
+		if body == nil {
+			body = &clockBody{name: name}
+			bymap[name] = body
+			bodies = append(bodies, body)
+		}
+		gdt := file.Provider.GPSDateTime()
+		if gdt.Empty() {
+			body.others = append(body.others, i)
+			continue
+		}
+		if cerr, ok := clockError(dt, gdt, file.Provider.GPS()); ok {
+			body.samples = append(body.samples, clockSample{wall: wallClock(dt), err: cerr})
+		} else if file.Provider.GPS().Empty() {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: clock: can't determine time zone without GPS coordinates\n", file.Path)
+		} else {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: clock: time zone unknown at GPS coordinates\n", file.Path)
+		}
+	}
+	tw = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "BODY\tSAMPLES\tOFFSET\tDRIFT")
+	for _, body := range bodies {
+		if len(body.samples) == 0 {
+			fmt.Fprintf(tw, "%s\t0\t\t\n", body.name)
+			continue
+		}
+		body.fit()
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%+.1fs/day\n", body.name, len(body.samples),
+			formatClockOffset(body.offset), body.drift)
+	}
+	tw.Flush()
+	for _, body := range bodies {
+		if len(body.samples) == 0 {
+			continue
+		}
+		for _, idx := range body.others {
+			dt := files[idx].Provider.DateTime()
+			shift := time.Duration(-body.errorAt(wallClock(dt)) * float64(time.Second)).Round(time.Second)
+			if shift == 0 {
+				continue
+			}
+			changes = append(changes, clockChange{file: idx, dt: dt.Add(shift)})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	fmt.Println()
+	tw = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tDATE/TIME\tCORRECTED")
+	for _, c := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", files[c.file].Path, files[c.file].Provider.DateTime(), c.dt)
+	}
+	tw.Flush()
+	if dryRun {
+		return nil
+	}
+	fmt.Printf("Correct %d date/times? [y/N] ", len(changes))
+	scan := bufio.NewScanner(os.Stdin)
+	if !scan.Scan() {
+		if err = scan.Err(); err == nil {
+			err = errors.New("clock: no confirmation")
+		}
+		return err
+	}
+	if answer := strings.ToLower(strings.TrimSpace(scan.Text())); answer != "y" && answer != "yes" {
+		return nil
+	}
+	for _, c := range changes {
+		if err = files[c.file].Provider.SetDateTime(c.dt); err != nil {
+			return fmt.Errorf("%s: clock: %s", files[c.file].Path, err)
+		}
+		files[c.file].Changed = true
+	}
+	return nil
+}
+
+// clockError returns the error of the camera clock, in seconds, given the
+// camera and GPS date/times of a media file.  If the camera date/time has no
+// time zone, the camera clock is assumed to have been set to local time at the
+// GPS coordinates; it returns false if there are no GPS coordinates.
+func clockError(dt, gdt metadata.DateTime, gps metadata.GPSCoords) (float64, bool) {
+	if dt.HasZone() {
+		return dt.AsTime().Sub(gdt.AsTime()).Seconds(), true
+	}
+	if gps.Empty() {
+		return 0, false
+	}
+	loc, err := timezone.Location(gps.Latitude(), gps.Longitude())
+	if err != nil {
+		return 0, false
+	}
+	_, offset := gdt.AsTime().In(loc).Zone()
+	return wallClock(dt).Sub(gdt.AsTime()).Seconds() - float64(offset), true
+}
+
+// wallClock returns the date and time read from the camera clock, ignoring
+// any time zone, expressed as a UTC time.
+func wallClock(dt metadata.DateTime) time.Time {
+	t := dt.AsTime()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// fit estimates the offset and drift of the camera clock from the samples,
+// using the Theil-Sen estimator so that a few bad samples (e.g., from a GPS
+// without a proper fix) don't throw off the result.  The offset is the error
+// at the time of the first sample.
+func (b *clockBody) fit() {
+	var slopes, intercepts []float64
+
+	b.start = b.samples[0].wall
+	for _, s := range b.samples {
+		if s.wall.Before(b.start) {
+			b.start = s.wall
+		}
+	}
+	for i, si := range b.samples {
+		for _, sj := range b.samples[i+1:] {
+			span := sj.wall.Sub(si.wall)
+			if span < 0 {
+				span = -span
+			}
+			if span < minDriftSpan {
+				continue
+			}
+			days := sj.wall.Sub(si.wall).Hours() / 24
+			slopes = append(slopes, (sj.err-si.err)/days)
+		}
+	}
+	if len(slopes) != 0 {
+		b.drift = median(slopes)
+	}
+	for _, s := range b.samples {
+		intercepts = append(intercepts, s.err-b.drift*s.wall.Sub(b.start).Hours()/24)
+	}
+	b.offset = median(intercepts)
+}
+
+// errorAt returns the estimated error of the camera clock, in seconds, when it
+// read the specified time.
+func (b *clockBody) errorAt(wall time.Time) float64 {
+	return b.offset + b.drift*wall.Sub(b.start).Hours()/24
+}
+
+// median returns the median of a list of numbers.  It reorders the list.
+func median(list []float64) float64 {
+	sort.Float64s(list)
+	if len(list)%2 == 1 {
+		return list[len(list)/2]
+	}
+	return (list[len(list)/2-1] + list[len(list)/2]) / 2
+}
+
+// formatClockOffset renders a clock error, in seconds, for display.
+func formatClockOffset(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Second)
+	if d > 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
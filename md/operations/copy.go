@@ -29,6 +29,7 @@ func Copy(args []string, files []MediaFile) (err error) {
 			fields.GroupsField,
 			fields.KeywordsField,
 			fields.LocationField,
+			fields.ShownField,
 			fields.PeopleField,
 			fields.PlacesField,
 			fields.TitleField,
@@ -0,0 +1,102 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/track"
+)
+
+// defaultMaxGap is the default for the maximum time between a media file and
+// the track points used to find its position.
+const defaultMaxGap = 10 * time.Minute
+
+// Geotag sets the GPS coordinates of the media from the positions recorded in
+// one or more GPS track logs at the times the media were captured.
+func Geotag(args []string, files []MediaFile) (err error) {
+	var (
+		tracks []track.Track
+		offset time.Duration
+		maxGap = defaultMaxGap
+		dryRun bool
+		tw     *tabwriter.Writer
+	)
+	for len(args) != 0 {
+		switch args[0] {
+		case "offset", "of", "off", "offs", "offse":
+			if len(args) < 2 {
+				return errors.New("geotag: missing offset")
+			}
+			if offset, err = time.ParseDuration(args[1]); err != nil {
+				return fmt.Errorf("geotag: invalid offset %q", args[1])
+			}
+			args = args[2:]
+		case "maxgap", "ma", "max", "maxg", "maxga":
+			if len(args) < 2 {
+				return errors.New("geotag: missing maxgap")
+			}
+			if maxGap, err = time.ParseDuration(args[1]); err != nil || maxGap < 0 {
+				return fmt.Errorf("geotag: invalid maxgap %q", args[1])
+			}
+			args = args[2:]
+		case "dryrun", "d", "dr", "dry", "dryr", "dryru":
+			dryRun = true
+			args = args[1:]
+		default:
+			var tr track.Track
+
+			if tr, err = track.ReadFile(args[0]); err != nil {
+				return fmt.Errorf("geotag: %s: %s", args[0], err)
+			}
+			tracks = append(tracks, tr)
+			args = args[1:]
+		}
+	}
+	if len(tracks) == 0 {
+		return errors.New("geotag: missing track log")
+	}
+	tr := track.Merge(tracks...)
+	if dryRun {
+		tw = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "FILE\tDATE/TIME\tGPS")
+	}
+	for i, file := range files {
+		var (
+			gps    metadata.GPSCoords
+			result string
+		)
+		dt := file.Provider.DateTime()
+		if dt.Empty() {
+			result = "no date/time"
+		} else if p, ok := tr.Position(dt.AsTime().Add(-offset), maxGap); !ok {
+			result = fmt.Sprintf("no track point within %s", maxGap)
+		} else {
+			gps.SetLatitude(p.Latitude)
+			gps.SetLongitude(p.Longitude)
+			if p.HasAltitude {
+				gps.SetAltitude(p.Altitude)
+			}
+			result = gps.String()
+		}
+		if dryRun {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", file.Path, dt, result)
+			continue
+		}
+		if gps.Empty() {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: geotag: %s\n", file.Path, result)
+			continue
+		}
+		if err = file.Provider.SetGPS(gps); err != nil {
+			return fmt.Errorf("%s: geotag: %s", file.Path, err)
+		}
+		files[i].Changed = true
+	}
+	if dryRun {
+		tw.Flush()
+	}
+	return nil
+}
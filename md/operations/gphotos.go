@@ -0,0 +1,210 @@
+package operations
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/gphotos"
+)
+
+// A gphotosChange is a change to one field of one media file, proposed by its
+// Google Photos JSON sidecar.
+type gphotosChange struct {
+	file     int
+	field    string
+	value    string
+	embedded string
+	action   string // "set", "add", or "conflict"
+	apply    func(metadata.Provider) error
+}
+
+// GPhotos imports the title, caption, date/time, GPS coordinates, and people
+// from the Google Photos JSON sidecars of the media.  It displays the changes
+// and conflicts with the values already in the media, and then (unless it's a
+// dry run) asks for confirmation before making the changes.
+func GPhotos(args []string, files []MediaFile) (err error) {
+	var (
+		changes []gphotosChange
+		dryRun  bool
+		count   int
+		tw      = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	)
+	for _, arg := range args {
+		switch arg {
+		case "dryrun", "d", "dr", "dry", "dryr", "dryru":
+			dryRun = true
+		default:
+			return fmt.Errorf("gphotos: unrecognized argument %q", arg)
+		}
+	}
+	fmt.Fprintln(tw, "FILE\tFIELD\tSIDECAR\tEMBEDDED\tACTION")
+	for i, file := range files {
+		var (
+			name    string
+			sidecar *gphotos.Sidecar
+		)
+		if name, err = gphotos.Find(file.Path); err != nil {
+			tw.Flush()
+			return fmt.Errorf("%s: gphotos: %s", file.Path, err)
+		}
+		if name == "" {
+			fmt.Fprintf(tw, "%s\t\t(no sidecar)\t\t\n", file.Path)
+			continue
+		}
+		if sidecar, err = gphotos.ReadFile(name); err != nil {
+			tw.Flush()
+			return fmt.Errorf("%s: gphotos: %s", name, err)
+		}
+		fchanges := gphotosChanges(i, file, sidecar)
+		if len(fchanges) == 0 {
+			fmt.Fprintf(tw, "%s\t\t(no changes)\t\t\n", file.Path)
+		}
+		for _, c := range fchanges {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", file.Path, c.field, escapeString(c.value), escapeString(c.embedded), c.action)
+			if c.action != "conflict" {
+				count++
+			}
+		}
+		changes = append(changes, fchanges...)
+	}
+	tw.Flush()
+	if dryRun || count == 0 {
+		return nil
+	}
+	fmt.Printf("Apply %d changes (conflicts are left alone)? [y/N] ", count)
+	scan := bufio.NewScanner(os.Stdin)
+	if !scan.Scan() {
+		if err = scan.Err(); err == nil {
+			err = errors.New("gphotos: no confirmation")
+		}
+		return err
+	}
+	if answer := strings.ToLower(strings.TrimSpace(scan.Text())); answer != "y" && answer != "yes" {
+		return nil
+	}
+	for _, c := range changes {
+		if c.action == "conflict" {
+			continue
+		}
+		if err = c.apply(files[c.file].Provider); err != nil {
+			return fmt.Errorf("%s: gphotos %s: %s", files[c.file].Path, c.field, err)
+		}
+		files[c.file].Changed = true
+	}
+	return nil
+}
+
+// gphotosChanges returns the changes to a media file proposed by its Google
+// Photos JSON sidecar.
+func gphotosChanges(idx int, file MediaFile, sidecar *gphotos.Sidecar) (changes []gphotosChange) {
+	var p = file.Provider
+
+	// Google Photos sets the title to the original file name unless the
+	// user changes it, so only a different title is worth importing.
+	if title := sidecar.Title; title != "" && title != filepath.Base(file.Path) &&
+		!strings.EqualFold(filepath.Ext(title), filepath.Ext(file.Path)) {
+		changes = gphotosString(changes, idx, "title", title, p.Title(), func(p metadata.Provider) error {
+			return p.SetTitle(title)
+		})
+	}
+	if caption := sidecar.Description; caption != "" {
+		changes = gphotosString(changes, idx, "caption", caption, p.Caption(), func(p metadata.Provider) error {
+			return p.SetCaption(caption)
+		})
+	}
+	if dt := sidecar.DateTime(); !dt.Empty() {
+		c := gphotosChange{file: idx, field: "datetime", value: dt.String(), embedded: p.DateTime().String()}
+		switch {
+		case p.DateTime().Empty():
+			c.action = "set"
+			c.apply = func(p metadata.Provider) error { return p.SetDateTime(dt) }
+		case !sameDateTime(p.DateTime(), sidecar.TakenTime):
+			c.action = "conflict"
+		}
+		if c.action != "" {
+			changes = append(changes, c)
+		}
+	}
+	if gps := sidecar.GPS; !gps.Empty() {
+		c := gphotosChange{file: idx, field: "gps", value: gps.String(), embedded: p.GPS().String()}
+		switch {
+		case p.GPS().Empty():
+			c.action = "set"
+			c.apply = func(p metadata.Provider) error { return p.SetGPS(gps) }
+		case !nearlySameGPS(gps, p.GPS()):
+			c.action = "conflict"
+		}
+		if c.action != "" {
+			changes = append(changes, c)
+		}
+	}
+	if len(sidecar.People) != 0 {
+		var (
+			people = p.People()
+			seen   = make(map[string]bool)
+			added  []string
+		)
+		for _, person := range people {
+			seen[person] = true
+		}
+		for _, person := range sidecar.People {
+			if !seen[person] {
+				seen[person] = true
+				added = append(added, person)
+			}
+		}
+		if len(added) != 0 {
+			all := append(append([]string{}, people...), added...)
+			changes = append(changes, gphotosChange{
+				file: idx, field: "people", value: strings.Join(added, "; "), embedded: strings.Join(people, "; "),
+				action: "add", apply: func(p metadata.Provider) error { return p.SetPeople(all) },
+			})
+		}
+	}
+	return changes
+}
+
+// gphotosString adds the change, if any, to a string field.
+func gphotosString(changes []gphotosChange, idx int, field, value, embedded string, apply func(metadata.Provider) error) []gphotosChange {
+	switch {
+	case embedded == "":
+		return append(changes, gphotosChange{file: idx, field: field, value: value, action: "set", apply: apply})
+	case strings.TrimSpace(embedded) != value:
+		return append(changes, gphotosChange{file: idx, field: field, value: value, embedded: embedded, action: "conflict"})
+	}
+	return changes
+}
+
+// sameDateTime returns whether an embedded date/time agrees with the time a
+// Google Photos sidecar says the photo was taken.  Google Photos gives that
+// time in UTC, so an embedded date/time without a time zone agrees if they
+// differ by a whole number of quarter hours, up to 14 hours, which a time zone
+// would explain.
+func sameDateTime(embedded metadata.DateTime, taken time.Time) bool {
+	var (
+		s = embedded.String()
+		t = embedded.AsTime().Truncate(time.Second)
+	)
+	if strings.HasSuffix(s, "Z") || s[len(s)-6] == '+' || s[len(s)-6] == '-' {
+		return t.Equal(taken)
+	}
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	diff := wall.Sub(taken)
+	return diff%(15*time.Minute) == 0 && diff <= 14*time.Hour && diff >= -14*time.Hour
+}
+
+// nearlySameGPS returns whether two sets of GPS coordinates are within about a
+// meter of each other, ignoring altitude.  Google Photos rounds coordinates, so
+// an exact comparison would report spurious conflicts.
+func nearlySameGPS(a, b metadata.GPSCoords) bool {
+	const epsilon = 0.00001
+	dlat, dlong := a.Latitude()-b.Latitude(), a.Longitude()-b.Longitude()
+	return dlat < epsilon && dlat > -epsilon && dlong < epsilon && dlong > -epsilon
+}
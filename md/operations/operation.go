@@ -3,7 +3,6 @@
 package operations
 
 import (
-	"os"
 	"strings"
 
 	"github.com/rothskeller/photo-tools/metadata"
@@ -11,10 +10,12 @@ import (
 )
 
 // MediaFile identifies, and provides the handler for, one media file named on
-// the command line.
+// the command line.  Media is the media file together with its XMP sidecar
+// files, Handler is the handler for the media file itself, and Provider
+// merges the metadata of the media file and its sidecars.
 type MediaFile struct {
 	Path     string
-	File     *os.File
+	Media    *filefmts.MediaFile
 	Handler  filefmts.FileFormat
 	Provider metadata.Provider
 	Changed  bool
@@ -5,13 +5,21 @@ import (
 
 	"github.com/rothskeller/photo-tools/md/fields"
 	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
 )
 
 // Reset resets the values of one or more fields to their primary value(s), thus
-// clearing up any inconsistencies or tagging errors.
+// clearing up any inconsistencies or tagging errors.  Windows XP* tags are
+// reset along with the others, unless "noxp" is given, in which case they are
+// removed.
 func Reset(args []string, files []MediaFile) (err error) {
-	var fieldlist []fields.Field
-
+	var (
+		fieldlist []fields.Field
+		noXP      bool
+	)
+	if len(args) != 0 && args[len(args)-1] == "noxp" {
+		args, noXP = args[:len(args)-1], true
+	}
 	if fieldlist, err = parseFieldList("reset", args); err != nil {
 		return err
 	}
@@ -25,6 +33,7 @@ func Reset(args []string, files []MediaFile) (err error) {
 			fields.GroupsField,
 			fields.KeywordsField,
 			fields.LocationField,
+			fields.ShownField,
 			fields.PeopleField,
 			fields.PlacesField,
 			fields.TitleField,
@@ -32,6 +41,9 @@ func Reset(args []string, files []MediaFile) (err error) {
 		}
 	}
 	for i, file := range files {
+		if noXP {
+			removeXPTags(file.Provider)
+		}
 		for _, field := range fieldlist {
 			values := field.GetValues(file.Provider)
 			if err := field.SetValues(file.Provider, values); err == metadata.ErrNotSupported {
@@ -50,3 +62,16 @@ func Reset(args []string, files []MediaFile) (err error) {
 	// a change to the field value and won't rewrite the tag.  I can live
 	// with that.
 }
+
+// removeXPTags causes subsequent changes to the file's metadata to remove the
+// corresponding Windows XP* tags, rather than keeping them in sync.
+func removeXPTags(p metadata.Provider) {
+	switch p := p.(type) {
+	case multi.Provider:
+		for _, sp := range p {
+			removeXPTags(sp)
+		}
+	case interface{ RemoveXPTags() }:
+		p.RemoveXPTags()
+	}
+}
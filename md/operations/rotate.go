@@ -0,0 +1,56 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/filefmts"
+)
+
+// Rotate rotates or flips the media.  Normally it does so by changing their
+// orientation; if "lossless" is specified, it instead transforms the image
+// data losslessly, leaving them with normal orientation.
+func Rotate(args []string, files []MediaFile) (err error) {
+	var (
+		op       = metadata.Rotate0
+		lossless bool
+	)
+	if len(args) == 0 {
+		return errors.New("rotate: missing direction")
+	}
+	for _, arg := range args {
+		switch arg {
+		case "left", "l", "ccw":
+			op = op.Then(metadata.Rotate270)
+		case "right", "r", "cw":
+			op = op.Then(metadata.Rotate90)
+		case "180":
+			op = op.Then(metadata.FlipXY)
+		case "flip", "f", "fl", "fli":
+			op = op.Then(metadata.FlipX)
+		case "lossless", "lo", "los", "loss", "lossl", "lossle", "lossles":
+			lossless = true
+		default:
+			return fmt.Errorf("rotate: %q is not left, right, 180, flip, or lossless", arg)
+		}
+	}
+	for i, file := range files {
+		o := file.Provider.Orientation().Then(op)
+		if !lossless {
+			if err = file.Provider.SetOrientation(o); err != nil {
+				return fmt.Errorf("%s: rotate: %s", file.Path, err)
+			}
+		} else if o == metadata.Rotate0 {
+			continue
+		} else if _, ok := file.Handler.(filefmts.TransformableFormat); ok {
+			if err = file.Media.Transform(o); err != nil {
+				return fmt.Errorf("%s: rotate: %s", file.Path, err)
+			}
+		} else {
+			return fmt.Errorf("%s: rotate: lossless rotation is not supported for this file type", file.Path)
+		}
+		files[i].Changed = true
+	}
+	return nil
+}
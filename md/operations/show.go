@@ -26,6 +26,7 @@ func Show(args []string, files []MediaFile) (err error) {
 			fields.ArtistField,
 			fields.GPSField,
 			fields.LocationField,
+			fields.ShownField,
 			fields.PlacesField,
 			fields.PeopleField,
 			fields.FacesField,
@@ -33,6 +34,23 @@ func Show(args []string, files []MediaFile) (err error) {
 			fields.TopicsField,
 			fields.KeywordsField,
 			fields.CaptionField,
+			fields.RatingField,
+			fields.LabelField,
+			fields.CopyrightField,
+			fields.CreditField,
+			fields.SourceField,
+			fields.UsageField,
+			fields.LicenseField,
+			fields.CameraField,
+			fields.LensField,
+			fields.SerialField,
+			fields.GPSDateTimeField,
+			fields.FocalLengthField,
+			fields.FNumberField,
+			fields.ExposureField,
+			fields.ISOField,
+			fields.DimensionsField,
+			fields.OrientationField,
 		}
 	}
 	for _, field := range fieldlist {
@@ -62,6 +80,16 @@ func Show(args []string, files []MediaFile) (err error) {
 					fmt.Fprintf(tw, "%s\t%s%s\t%s\n", file.Path, check, field.Label(), escapeString(field.RenderValue(value)))
 				}
 			}
+			// Show the other language alternatives of fields that have
+			// them.
+			if lfield, ok := field.(fields.LanguageField); ok {
+				for _, lang := range lfield.Languages(file.Provider) {
+					lf := lfield.WithLanguage(lang)
+					for _, value := range lf.GetValues(file.Provider) {
+						fmt.Fprintf(tw, "%s\t  %s\t%s\n", file.Path, lf.Label(), escapeString(lf.RenderValue(value)))
+					}
+				}
+			}
 		}
 	}
 	tw.Flush()
@@ -24,6 +24,7 @@ func Tags(args []string, files []MediaFile) (err error) {
 			fields.ArtistField,
 			fields.GPSField,
 			fields.LocationField,
+			fields.ShownField,
 			fields.PlacesField,
 			fields.PeopleField,
 			fields.FacesField,
@@ -31,6 +32,23 @@ func Tags(args []string, files []MediaFile) (err error) {
 			fields.TopicsField,
 			fields.KeywordsField,
 			fields.CaptionField,
+			fields.RatingField,
+			fields.LabelField,
+			fields.CopyrightField,
+			fields.CreditField,
+			fields.SourceField,
+			fields.UsageField,
+			fields.LicenseField,
+			fields.CameraField,
+			fields.LensField,
+			fields.SerialField,
+			fields.GPSDateTimeField,
+			fields.FocalLengthField,
+			fields.FNumberField,
+			fields.ExposureField,
+			fields.ISOField,
+			fields.DimensionsField,
+			fields.OrientationField,
 		}
 	}
 	tw = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
@@ -0,0 +1,164 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/track"
+)
+
+// defaultAccuracy is the default for the worst accuracy, in meters, of the
+// Google Takeout location history points used to find positions.
+const defaultAccuracy = 100
+
+// defaultTolerance is the default for the maximum time between a media file
+// and the Google Takeout location history point used to find its position.
+const defaultTolerance = 30 * time.Minute
+
+// Takeout sets the GPS coordinates of media that don't have them, from the
+// nearest point in a Google Takeout location history, and suggests places
+// from the place visits in the semantic location history.
+func Takeout(args []string, files []MediaFile) (err error) {
+	var (
+		tracks    []track.Track
+		visits    track.Visits
+		offset    time.Duration
+		accuracy  float64 = defaultAccuracy
+		tolerance         = defaultTolerance
+		dryRun    bool
+	)
+	for len(args) != 0 {
+		switch args[0] {
+		case "accuracy", "a", "ac", "acc", "accu", "accur", "accura", "accurac":
+			if len(args) < 2 {
+				return errors.New("takeout: missing accuracy")
+			}
+			if accuracy, err = strconv.ParseFloat(args[1], 64); err != nil || accuracy <= 0 {
+				return fmt.Errorf("takeout: invalid accuracy %q", args[1])
+			}
+			args = args[2:]
+		case "offset", "of", "off", "offs", "offse":
+			if len(args) < 2 {
+				return errors.New("takeout: missing offset")
+			}
+			if offset, err = time.ParseDuration(args[1]); err != nil {
+				return fmt.Errorf("takeout: invalid offset %q", args[1])
+			}
+			args = args[2:]
+		case "tolerance", "to", "tol", "tole", "toler", "tolera", "toleran", "toleranc":
+			if len(args) < 2 {
+				return errors.New("takeout: missing tolerance")
+			}
+			if tolerance, err = time.ParseDuration(args[1]); err != nil || tolerance < 0 {
+				return fmt.Errorf("takeout: invalid tolerance %q", args[1])
+			}
+			args = args[2:]
+		case "dryrun", "d", "dr", "dry", "dryr", "dryru":
+			dryRun = true
+			args = args[1:]
+		default:
+			if tracks, visits, err = readTakeout(args[0], tracks, visits); err != nil {
+				return fmt.Errorf("takeout: %s", err)
+			}
+			args = args[1:]
+		}
+	}
+	if len(tracks) == 0 {
+		return errors.New("takeout: missing location history")
+	}
+	sort.SliceStable(visits, func(i, j int) bool { return visits[i].Start.Before(visits[j].Start) })
+	tr := track.Merge(tracks...).FilterAccuracy(accuracy)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tDATE/TIME\tGPS\tSUGGESTED PLACE")
+	for i, file := range files {
+		var (
+			gps    metadata.GPSCoords
+			place  string
+			result string
+		)
+		dt := file.Provider.DateTime()
+		switch {
+		case dt.Empty():
+			result = "no date/time"
+		case !file.Provider.GPS().Empty():
+			result = "already set"
+		default:
+			if p, ok := tr.Nearest(dt.AsTime().Add(-offset), tolerance); ok {
+				gps.SetLatitude(p.Latitude)
+				gps.SetLongitude(p.Longitude)
+				if p.HasAltitude {
+					gps.SetAltitude(p.Altitude)
+				}
+				result = gps.String()
+			} else {
+				result = fmt.Sprintf("no location within %s", tolerance)
+			}
+		}
+		if !dt.Empty() {
+			if v, ok := visits.At(dt.AsTime().Add(-offset)); ok {
+				place = visitPlace(v)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", file.Path, dt, result, place)
+		if dryRun || gps.Empty() {
+			continue
+		}
+		if err = file.Provider.SetGPS(gps); err != nil {
+			tw.Flush()
+			return fmt.Errorf("%s: takeout: %s", file.Path, err)
+		}
+		files[i].Changed = true
+	}
+	tw.Flush()
+	return nil
+}
+
+// readTakeout reads a Google Takeout location history file, or all of the
+// JSON files in a directory tree (such as "Semantic Location History"), and
+// adds their points and place visits to the supplied lists.
+func readTakeout(path string, tracks []track.Track, visits track.Visits) ([]track.Track, track.Visits, error) {
+	err := filepath.WalkDir(path, func(fpath string, d fs.DirEntry, err error) error {
+		var (
+			fh *os.File
+			tr track.Track
+			vs track.Visits
+		)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || (fpath != path && !strings.EqualFold(filepath.Ext(fpath), ".json")) {
+			return nil
+		}
+		if fh, err = os.Open(fpath); err != nil {
+			return err
+		}
+		defer fh.Close()
+		if tr, vs, err = track.ReadTakeout(fh); err != nil {
+			return fmt.Errorf("%s: %s", fpath, err)
+		}
+		tracks, visits = append(tracks, tr), append(visits, vs...)
+		return nil
+	})
+	return tracks, visits, err
+}
+
+// visitPlace returns the place suggested by a place visit.
+func visitPlace(v track.Visit) string {
+	switch {
+	case v.Name == "":
+		return v.Address
+	case v.Address == "" || strings.HasPrefix(v.Address, v.Name):
+		return v.Name
+	default:
+		return v.Name + ", " + v.Address
+	}
+}
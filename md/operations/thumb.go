@@ -0,0 +1,70 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/filefmts"
+)
+
+// Thumb extracts, regenerates, or removes the embedded thumbnail images of the
+// media.
+func Thumb(args []string, files []MediaFile) (err error) {
+	if len(args) == 0 {
+		return errors.New("thumb: missing action")
+	}
+	if len(args) > 1 {
+		return errors.New("thumb: excess arguments")
+	}
+	for i, file := range files {
+		th, ok := file.Handler.(filefmts.ThumbnailFormat)
+		if !ok {
+			return fmt.Errorf("%s: thumb: thumbnails are not supported for this file type", file.Path)
+		}
+		switch args[0] {
+		case "extract", "e", "ex", "ext", "extr", "extra", "extrac":
+			err = extractThumbnail(file.Path, th)
+		case "regenerate", "reg", "rege", "regen", "regene", "regener", "regenera", "regenerat":
+			err = th.RegenerateThumbnail()
+			files[i].Changed = true
+		case "remove", "rem", "remo", "remov", "rm":
+			err = th.RemoveThumbnail()
+			files[i].Changed = true
+		default:
+			return fmt.Errorf("thumb: %q is not extract, regenerate, or remove", args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("%s: thumb: %s", file.Path, err)
+		}
+	}
+	return nil
+}
+
+// extractThumbnail writes the thumbnail image of the media file at path to a
+// file with the same name and a ".thumb.jpg" extension.
+func extractThumbnail(path string, th filefmts.ThumbnailFormat) (err error) {
+	var (
+		thumb metadata.Reader
+		fh    *os.File
+	)
+	if thumb, err = th.Thumbnail(); err != nil {
+		return err
+	}
+	if thumb == nil {
+		return errors.New("no thumbnail")
+	}
+	path = strings.TrimSuffix(path, filepath.Ext(path)) + ".thumb.jpg"
+	if fh, err = os.Create(path); err != nil {
+		return err
+	}
+	if _, err = io.Copy(fh, io.NewSectionReader(thumb, 0, thumb.Size())); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
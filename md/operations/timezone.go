@@ -0,0 +1,105 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/timezone"
+)
+
+// TimeZone adds the time zone to the date/time of each of the media that
+// lacks one, based on the time zone in effect at their GPS coordinates.  In
+// annotate mode, the camera clock is assumed to have been set to local time,
+// so only the time zone offset is added.  In convert mode, the camera clock is
+// assumed to have been set to UTC, so the date and time are converted to local
+// time as well.
+func TimeZone(args []string, files []MediaFile) (err error) {
+	var (
+		convert bool
+		dryRun  bool
+		tw      *tabwriter.Writer
+	)
+	if len(args) == 0 {
+		return errors.New("timezone: missing mode (convert or annotate)")
+	}
+	switch args[0] {
+	case "annotate", "a", "an", "ann", "anno", "annot", "annota", "annotat":
+		convert = false
+	case "convert", "c", "co", "con", "conv", "conve", "conver":
+		convert = true
+	default:
+		return fmt.Errorf("timezone: invalid mode %q (expected convert or annotate)", args[0])
+	}
+	for _, arg := range args[1:] {
+		switch arg {
+		case "dryrun", "d", "dr", "dry", "dryr", "dryru":
+			dryRun = true
+		default:
+			return fmt.Errorf("timezone: unrecognized argument %q", arg)
+		}
+	}
+	if dryRun {
+		tw = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "FILE\tDATE/TIME\tTIME ZONE\tNEW DATE/TIME")
+	}
+	for i, file := range files {
+		var (
+			ndt    metadata.DateTime
+			zone   string
+			result string
+		)
+		dt := file.Provider.DateTime()
+		gps := file.Provider.GPS()
+		switch {
+		case dt.Empty():
+			result = "no date/time"
+		case dt.HasZone() && !(convert && isUTC(dt)):
+			result = "already has time zone"
+		case gps.Empty():
+			result = "no GPS coordinates"
+		default:
+			if zone = timezone.Lookup(gps.Latitude(), gps.Longitude()); zone == "" {
+				result = "time zone unknown at GPS coordinates"
+				break
+			}
+			var loc *time.Location
+			if loc, err = time.LoadLocation(zone); err != nil {
+				return fmt.Errorf("%s: timezone: %s", file.Path, err)
+			}
+			if convert && !dt.HasZone() {
+				// The clock was UTC, so say so before converting.
+				if err = dt.Parse(dt.String() + "Z"); err != nil {
+					return fmt.Errorf("%s: timezone: %s", file.Path, err)
+				}
+			}
+			ndt = dt.InLocation(loc)
+			result = ndt.String()
+		}
+		if dryRun {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", file.Path, file.Provider.DateTime(), zone, result)
+			continue
+		}
+		if ndt.Empty() {
+			fmt.Fprintf(os.Stderr, "WARNING: %s: timezone: %s\n", file.Path, result)
+			continue
+		}
+		if err = file.Provider.SetDateTime(ndt); err != nil {
+			return fmt.Errorf("%s: timezone: %s", file.Path, err)
+		}
+		files[i].Changed = true
+	}
+	if dryRun {
+		tw.Flush()
+	}
+	return nil
+}
+
+// isUTC returns whether the date/time is explicitly in UTC.
+func isUTC(dt metadata.DateTime) bool {
+	_, offset := dt.AsTime().Zone()
+	return dt.HasZone() && offset == 0
+}
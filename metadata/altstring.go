@@ -0,0 +1,118 @@
+package metadata
+
+// An AltString is a set of language alternatives for a single conceptual
+// string.  The first alternative is the default language.
+type AltString []AltItem
+
+// An AltItem is a single language variant of an AltString.
+type AltItem struct {
+	Value string
+	Lang  string
+}
+
+// NewAltString creates a new AltString, with a single default alternative.
+func NewAltString(s string) AltString {
+	return AltString{{s, "x-default"}}
+}
+
+// Empty returns true if the AltString contains no non-empty values.
+func (as AltString) Empty() bool {
+	for _, ai := range as {
+		if ai.Value != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Default returns the default string from the AltString.
+func (as AltString) Default() string {
+	if len(as) == 0 {
+		return ""
+	}
+	return as[0].Value
+}
+
+// Get returns the value of the AltString for the specified language.
+func (as AltString) Get(lang string) string {
+	for _, alt := range as {
+		if alt.Lang == lang {
+			return alt.Value
+		}
+	}
+	return ""
+}
+
+// SetDefault returns a copy of the AltString with its default value changed.
+// Any other alternative that had the same value as the old default is changed
+// as well, since it was presumably a copy of the default.  Setting an empty
+// default removes the default alternative and those copies of it, but keeps the
+// other languages.
+func (as AltString) SetDefault(value string) AltString {
+	if len(as) == 0 {
+		if value == "" {
+			return nil
+		}
+		return NewAltString(value)
+	}
+	var old = as.Default()
+	var nas = make(AltString, 0, len(as))
+	for i, alt := range as {
+		if i == 0 || alt.Value == old {
+			if value == "" {
+				continue
+			}
+			alt.Value = value
+		}
+		nas = append(nas, alt)
+	}
+	if len(nas) == 0 {
+		return nil
+	}
+	return nas
+}
+
+// Set returns a copy of the AltString with the value for the specified
+// language changed.  Setting an empty value removes that language.  If the
+// AltString was empty, the value becomes the default as well.
+func (as AltString) Set(lang, value string) AltString {
+	if lang == "" || lang == "x-default" || (len(as) != 0 && as[0].Lang == lang) {
+		return as.SetDefault(value)
+	}
+	if len(as) == 0 {
+		if value == "" {
+			return nil
+		}
+		return AltString{{value, "x-default"}, {value, lang}}
+	}
+	var nas = make(AltString, 0, len(as)+1)
+	var found bool
+	for _, alt := range as {
+		if alt.Lang == lang {
+			found = true
+			if value == "" {
+				continue
+			}
+			alt.Value = value
+		}
+		nas = append(nas, alt)
+	}
+	if !found && value != "" {
+		nas = append(nas, AltItem{value, lang})
+	}
+	return nas
+}
+
+// Equal returns true if the two AltStrings have the same alternatives in the
+// same order.
+func (as AltString) Equal(other AltString) bool {
+	if len(as) != len(other) {
+		return false
+	}
+	for i := range as {
+		if as[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package metadata
+
+import "testing"
+
+func TestAltString(t *testing.T) {
+	as := AltString{{"Hello", "x-default"}, {"Hello", "en-US"}, {"Hallo", "de"}}
+	if got := as.SetDefault("Hi"); !got.Equal(AltString{{"Hi", "x-default"}, {"Hi", "en-US"}, {"Hallo", "de"}}) {
+		t.Errorf("SetDefault result is wrong: %v", got)
+	}
+	if got := as.Set("de", "Servus"); !got.Equal(AltString{{"Hello", "x-default"}, {"Hello", "en-US"}, {"Servus", "de"}}) {
+		t.Errorf("Set of existing language is wrong: %v", got)
+	}
+	if got := as.Set("de", ""); !got.Equal(AltString{{"Hello", "x-default"}, {"Hello", "en-US"}}) {
+		t.Errorf("Set of empty value is wrong: %v", got)
+	}
+	if got := as.Set("fr", "Bonjour"); got.Get("fr") != "Bonjour" || len(got) != 4 {
+		t.Errorf("Set of new language is wrong: %v", got)
+	}
+	if got := AltString(nil).Set("de", "Hallo"); got.Default() != "Hallo" || got.Get("de") != "Hallo" {
+		t.Errorf("Set on empty AltString is wrong: %v", got)
+	}
+	if got := as.SetDefault(""); !got.Equal(AltString{{"Hallo", "de"}}) {
+		t.Errorf("SetDefault of empty value is wrong: %v", got)
+	}
+	if got := (AltString{{"Hello", "x-default"}, {"Hello", "en-US"}}).SetDefault(""); got != nil {
+		t.Errorf("SetDefault of empty value with only copies is wrong: %v", got)
+	}
+}
@@ -0,0 +1,300 @@
+// Package gif handles marshaling and unmarshaling of GIF file blocks.
+package gif
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers"
+)
+
+// Block introducers and labels.
+const (
+	introExtension  = 0x21
+	introImage      = 0x2C
+	introTrailer    = 0x3B
+	labelAppExt     = 0xFF
+	appIdentSize    = 11
+	version87a      = "87a"
+	version89a      = "89a"
+	headerSize      = 13
+	flagColorTable  = 0x80
+	colorTableSizes = 0x07
+)
+
+// xmpIdent is the application identifier and authentication code of the
+// application extension block that contains XMP metadata.
+var xmpIdent = []byte("XMP DataXMP")
+
+// xmpTrailer is the "magic trailer" that follows the XMP packet in its
+// application extension block.  The XMP packet is not divided into sub-blocks
+// as it should be; instead, GIF readers interpret its bytes as sub-block
+// sizes, and this trailer ensures that whatever byte they are looking at when
+// they reach it, they will find their way to the terminating zero byte.
+var xmpTrailer = func() []byte {
+	var by = make([]byte, 258)
+	by[0] = 0x01
+	for i := 0; i < 256; i++ {
+		by[i+1] = byte(255 - i)
+	}
+	return by // by[257] is the block terminator, zero
+}()
+
+// A GIF is a container of blocks.
+type GIF struct {
+	header []byte // signature, logical screen descriptor, and global color table
+	blocks []*block
+	xmp    *block
+	all    []*block
+	size   int64
+}
+
+// A block is a single block of a GIF file (i.e., an extension, an image, or
+// the trailer).  In the case of the trailer, it includes any data that follows
+// the trailer.
+type block struct {
+	raw       metadata.Reader // entire block
+	data      metadata.Reader // XMP packet, in the XMP block
+	container containers.Container
+	rewrite   bool  // whether the block must be rendered from its container
+	csize     int64 // rendered size of the container
+}
+
+var _ containers.Container = (*GIF)(nil) // verify interface compliance
+
+// Read reads and parses the container structure from the supplied Reader.  The
+// reader will continue to be used after Read returns, and must remain open and
+// usable as long as the Container is in scope.
+func (gif *GIF) Read(r metadata.Reader) (err error) {
+	var (
+		buf    [headerSize]byte
+		offset int64
+		end    int64
+		bl     *block
+	)
+	if _, err = r.ReadAt(buf[:], 0); err != nil || string(buf[0:3]) != "GIF" {
+		return errors.New("GIF: not a GIF file")
+	}
+	offset = headerSize
+	if buf[10]&flagColorTable != 0 {
+		offset += 3 << (buf[10]&colorTableSizes + 1)
+	}
+	gif.header = make([]byte, offset)
+	if _, err = r.ReadAt(gif.header, 0); err != nil {
+		return errors.New("GIF: truncated header")
+	}
+	for {
+		if _, err = r.ReadAt(buf[0:2], offset); err != nil && !(err == io.EOF && buf[0] == introTrailer) {
+			return errors.New("GIF: missing trailer")
+		}
+		switch buf[0] {
+		case introTrailer:
+			gif.blocks = append(gif.blocks, &block{raw: io.NewSectionReader(r, offset, r.Size()-offset)})
+			return nil
+		case introExtension:
+			if end, err = skipSubBlocks(r, offset+2); err != nil {
+				return err
+			}
+			bl = &block{raw: io.NewSectionReader(r, offset, end-offset)}
+			if buf[1] == labelAppExt && isXMPBlock(bl) {
+				if gif.xmp != nil {
+					return errors.New("GIF: multiple XMP blocks")
+				}
+				if err = readXMPBlock(bl); err != nil {
+					return fmt.Errorf("GIF: XMP block: %s", err)
+				}
+				gif.xmp = bl
+			}
+		case introImage:
+			if _, err = r.ReadAt(buf[0:10], offset); err != nil {
+				return errors.New("GIF: truncated image descriptor")
+			}
+			end = offset + 10
+			if buf[9]&flagColorTable != 0 {
+				end += 3 << (buf[9]&colorTableSizes + 1)
+			}
+			if end, err = skipSubBlocks(r, end+1); err != nil { // +1 for LZW minimum code size
+				return err
+			}
+			bl = &block{raw: io.NewSectionReader(r, offset, end-offset)}
+		default:
+			return fmt.Errorf("GIF: invalid block introducer 0x%02X at offset %d", buf[0], offset)
+		}
+		gif.blocks = append(gif.blocks, bl)
+		offset = end
+	}
+}
+
+// skipSubBlocks skips over a sequence of data sub-blocks starting at the
+// specified offset, and returns the offset following its terminator.
+func skipSubBlocks(r metadata.Reader, offset int64) (end int64, err error) {
+	var buf [1]byte
+
+	for {
+		if _, err = r.ReadAt(buf[:], offset); err != nil {
+			return 0, errors.New("GIF: truncated data sub-blocks")
+		}
+		offset++
+		if buf[0] == 0 {
+			return offset, nil
+		}
+		offset += int64(buf[0])
+	}
+}
+
+// isXMPBlock returns whether the application extension block contains XMP
+// metadata.
+func isXMPBlock(bl *block) bool {
+	var buf [3 + appIdentSize]byte
+
+	if _, err := bl.raw.ReadAt(buf[:], 0); err != nil {
+		return false
+	}
+	return buf[2] == appIdentSize && bytes.Equal(buf[3:], xmpIdent)
+}
+
+// readXMPBlock verifies the magic trailer of an XMP application extension
+// block, and sets the block's data reader to the XMP packet.
+func readXMPBlock(bl *block) (err error) {
+	var (
+		buf   = make([]byte, len(xmpTrailer))
+		start = int64(3 + appIdentSize)
+		size  = bl.raw.Size() - start - int64(len(xmpTrailer))
+	)
+	if size < 0 {
+		return errors.New("missing magic trailer")
+	}
+	if _, err = bl.raw.ReadAt(buf, start+size); err != nil {
+		return err
+	}
+	if !bytes.Equal(buf, xmpTrailer) {
+		return errors.New("invalid magic trailer")
+	}
+	bl.data = io.NewSectionReader(bl.raw, start, size)
+	return nil
+}
+
+// Empty returns whether the container is empty (and should therefore be omitted
+// from the written file, along with whatever tag in the parent container points
+// to it).
+func (gif *GIF) Empty() bool { return false } // GIFs are never empty
+
+// Dirty returns whether any of the GIF blocks have been changed.
+func (gif *GIF) Dirty() bool { return gif.xmp.dirty() }
+
+// Layout computes the rendered layout of the container, i.e. prepares for a
+// call to Write, and returns what the rendered size of the container will be.
+func (gif *GIF) Layout() int64 {
+	var blocks = gif.blocks
+
+	// A new XMP block (i.e., one that wasn't in the block list when the
+	// file was read) gets inserted ahead of all other blocks.
+	if gif.xmp != nil && gif.xmp.raw == nil {
+		blocks = append([]*block{gif.xmp}, blocks...)
+	}
+	gif.all = gif.all[:0]
+	gif.size = int64(len(gif.header))
+	for _, bl := range blocks {
+		if bl.container != nil && bl.container.Empty() {
+			continue
+		}
+		gif.all = append(gif.all, bl)
+		gif.size += bl.layout()
+	}
+	// Extension blocks aren't allowed in GIF87a files, so adding XMP
+	// requires upgrading the file to GIF89a.
+	if gif.xmp.dirty() && !gif.xmp.container.Empty() && string(gif.header[3:6]) == version87a {
+		copy(gif.header[3:6], version89a)
+	}
+	return gif.size
+}
+
+// Write writes the rendered container to the specified writer.
+func (gif *GIF) Write(w io.Writer) (count int, err error) {
+	var n int
+
+	n, err = w.Write(gif.header)
+	count += n
+	if err != nil {
+		return count, err
+	}
+	for _, bl := range gif.all {
+		n, err = bl.write(w)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	if int(gif.size) != count {
+		panic("actual size different from predicted size")
+	}
+	return count, nil
+}
+
+// XMP returns the contents of the XMP block, if any.
+func (gif *GIF) XMP() metadata.Reader {
+	if gif.xmp != nil {
+		return gif.xmp.data
+	}
+	return nil
+}
+
+// SetXMPContainer sets the contents of the XMP block to those provided by the
+// supplied container.
+func (gif *GIF) SetXMPContainer(c containers.Container) {
+	if gif.xmp == nil {
+		gif.xmp = new(block)
+	}
+	gif.xmp.container = c
+}
+
+// dirty returns whether the block has been changed.
+func (bl *block) dirty() bool {
+	if bl == nil || bl.container == nil {
+		return false
+	}
+	return bl.container.Dirty()
+}
+
+// layout computes the rendered size of the block.
+func (bl *block) layout() int64 {
+	if bl.container == nil || !bl.container.Dirty() && bl.raw != nil {
+		bl.rewrite = false
+		return bl.raw.Size()
+	}
+	bl.rewrite = true
+	bl.csize = bl.container.Layout()
+	return 3 + appIdentSize + bl.csize + int64(len(xmpTrailer))
+}
+
+// write writes the block to the specified writer.
+func (bl *block) write(w io.Writer) (count int, err error) {
+	var (
+		n   int
+		n64 int64
+	)
+	if !bl.rewrite {
+		bl.raw.Seek(0, io.SeekStart)
+		n64, err = io.Copy(w, bl.raw)
+		return int(n64), err
+	}
+	n, err = w.Write(append([]byte{introExtension, labelAppExt, appIdentSize}, xmpIdent...))
+	count += n
+	if err != nil {
+		return count, err
+	}
+	n, err = bl.container.Write(w)
+	count += n
+	if err != nil {
+		return count, err
+	}
+	if int64(n) != bl.csize {
+		panic("actual size different from predicted size")
+	}
+	n, err = w.Write(xmpTrailer)
+	count += n
+	return count, err
+}
@@ -0,0 +1,85 @@
+package gif
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/raw"
+)
+
+var (
+	testScreen = []byte{1, 0, 1, 0, 0x80, 0, 0, 0, 0, 0, 0xFF, 0xFF, 0xFF} // 1x1, 2-color global table
+	testImage  = []byte{0x2C, 0, 0, 0, 0, 1, 0, 1, 0, 0, 2, 2, 0x4C, 0x01, 0}
+	testGCE    = []byte{0x21, 0xF9, 4, 0, 0, 0, 0, 0}
+)
+
+func makeGIF(version string, blocks ...[]byte) []byte {
+	var by = append([]byte("GIF"+version), testScreen...)
+	by = append(by, bytes.Join(blocks, nil)...)
+	return append(by, 0x3B)
+}
+
+func makeXMPBlock(xmp string) []byte {
+	var by = append([]byte{0x21, 0xFF, 11}, "XMP DataXMP"...)
+	by = append(by, xmp...)
+	return append(by, xmpTrailer...)
+}
+
+func rewrite(t *testing.T, input []byte, xmp []byte) []byte {
+	var (
+		gif GIF
+		out bytes.Buffer
+	)
+	if err := gif.Read(bytes.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if xmp != nil {
+		var r raw.Raw
+		gif.SetXMPContainer(&r)
+		r.SetData(xmp)
+	}
+	gif.Layout()
+	if _, err := gif.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestReadXMP(t *testing.T) {
+	var gif GIF
+
+	input := makeGIF("89a", testGCE, makeXMPBlock("<x/>"), testImage)
+	if err := gif.Read(bytes.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if by, _ := io.ReadAll(gif.XMP()); string(by) != "<x/>" {
+		t.Errorf("XMP: got %q", by)
+	}
+	if len(gif.blocks) != 4 {
+		t.Errorf("got %d blocks, expected 4", len(gif.blocks))
+	}
+}
+
+func TestReadWriteUnchanged(t *testing.T) {
+	input := makeGIF("89a", testGCE, makeXMPBlock("<x/>"), testImage)
+	if out := rewrite(t, input, nil); !bytes.Equal(out, input) {
+		t.Error("output differs from input")
+	}
+}
+
+func TestReplaceXMP(t *testing.T) {
+	input := makeGIF("89a", testGCE, makeXMPBlock("<x/>"), testImage)
+	expected := makeGIF("89a", testGCE, makeXMPBlock("<x>new</x>"), testImage)
+	if out := rewrite(t, input, []byte("<x>new</x>")); !bytes.Equal(out, expected) {
+		t.Errorf("output mismatch:\n got %q\nwant %q", out, expected)
+	}
+}
+
+func TestAddXMP(t *testing.T) {
+	input := makeGIF("87a", testImage)
+	expected := makeGIF("89a", makeXMPBlock("<x/>"), testImage)
+	if out := rewrite(t, input, []byte("<x/>")); !bytes.Equal(out, expected) {
+		t.Errorf("output mismatch:\n got %q\nwant %q", out, expected)
+	}
+}
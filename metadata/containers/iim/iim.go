@@ -13,8 +13,16 @@ import (
 	"github.com/rothskeller/photo-tools/metadata"
 	"github.com/rothskeller/photo-tools/metadata/containers"
 	"github.com/rothskeller/photo-tools/metadata/containers/raw"
+	"golang.org/x/text/encoding"
 )
 
+// idCodedCharacterSet is the ID of the data set that identifies the character
+// set of the block's text data sets, and utf8Escape is the value of that data
+// set that identifies UTF-8.
+const idCodedCharacterSet uint16 = 0x015A
+
+var utf8Escape = []byte{0x1B, 0x25, 0x47}
+
 // An IIM structure represents the entire IIM block.
 type IIM struct {
 	dsmap         map[uint16][]DataSet
@@ -133,6 +141,37 @@ func (iim *IIM) RemoveDataSets(id uint16) {
 	}
 }
 
+// DataSetIDs returns the IDs of all data sets in the IIM block, in ascending
+// order.
+func (iim *IIM) DataSetIDs() (ids []uint16) {
+	for id, dss := range iim.dsmap {
+		if len(dss) != 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Transcode converts the text data sets in the IIM block (those for which
+// isText returns true) from a legacy character set to UTF-8, using the supplied
+// decoder, and changes the Coded Character Set data set to match.  It doesn't
+// mark the block dirty, since the information in it hasn't changed; but
+// whenever the block is next written, it will be written in UTF-8.
+func (iim *IIM) Transcode(dec *encoding.Decoder, isText func(id uint16) bool) {
+	for id, dss := range iim.dsmap {
+		if !isText(id) {
+			continue
+		}
+		for i := range dss {
+			if by, err := dec.Bytes(dss[i].Data); err == nil {
+				dss[i].Data = by
+			}
+		}
+	}
+	iim.dsmap[idCodedCharacterSet] = []DataSet{{idCodedCharacterSet, utf8Escape}}
+}
+
 // SetHashContainer sets the container into which the IIM block's hash should be
 // written when the block is written.
 func (iim *IIM) SetHashContainer(hc *raw.Raw) { iim.hashContainer = hc }
@@ -0,0 +1,162 @@
+package isobmff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// A box is a single box in an ISO base media file.  This structure describes
+// its location in the input file; the data are not cached.
+type box struct {
+	btype  string
+	offset int64 // offset of the start of the box header
+	hsize  int64 // size of the box header
+	size   int64 // size of the box, including header
+	toEOF  bool  // box header has size zero, i.e., box extends to end of file
+}
+
+// readBox reads the header of the box at the specified offset.  The box must
+// end no later than end.
+func readBox(r metadata.Reader, offset, end int64) (b *box, err error) {
+	var buf [16]byte
+
+	if end-offset < 8 {
+		return nil, errors.New("truncated box header")
+	}
+	if _, err = r.ReadAt(buf[:8], offset); err != nil {
+		return nil, err
+	}
+	b = &box{btype: string(buf[4:8]), offset: offset, hsize: 8}
+	switch size := binary.BigEndian.Uint32(buf[0:4]); size {
+	case 0:
+		b.size = end - offset
+		b.toEOF = true
+	case 1:
+		if end-offset < 16 {
+			return nil, fmt.Errorf("%s box: truncated header", b.btype)
+		}
+		if _, err = r.ReadAt(buf[8:16], offset+8); err != nil {
+			return nil, err
+		}
+		b.size = int64(binary.BigEndian.Uint64(buf[8:16]))
+		b.hsize = 16
+	default:
+		b.size = int64(size)
+	}
+	if b.btype == "uuid" {
+		b.hsize += 16
+	}
+	if b.size < b.hsize || b.size > end-offset {
+		return nil, fmt.Errorf("%s box: invalid size", b.btype)
+	}
+	return b, nil
+}
+
+// readBoxes reads the headers of all of the boxes in the range [start, end).
+func readBoxes(r metadata.Reader, start, end int64) (boxes []*box, err error) {
+	for start < end {
+		var b *box
+
+		if b, err = readBox(r, start, end); err != nil {
+			return nil, err
+		}
+		boxes = append(boxes, b)
+		start += b.size
+	}
+	return boxes, nil
+}
+
+// data returns the contents of the box, excluding its header.
+func (b *box) data(r metadata.Reader) (by []byte, err error) {
+	by = make([]byte, b.size-b.hsize)
+	_, err = r.ReadAt(by, b.offset+b.hsize)
+	return by, err
+}
+
+// findBox returns the first box of the specified type in the list, or nil if
+// there is none.
+func findBox(boxes []*box, btype string) *box {
+	for _, b := range boxes {
+		if b.btype == btype {
+			return b
+		}
+	}
+	return nil
+}
+
+// boxHeader returns the header for a box of the specified type whose contents
+// (excluding the header) have the specified size.
+func boxHeader(btype string, size int64) []byte {
+	var buf [16]byte
+
+	if size+8 <= math.MaxUint32 {
+		binary.BigEndian.PutUint32(buf[0:4], uint32(size+8))
+		copy(buf[4:8], btype)
+		return buf[:8]
+	}
+	binary.BigEndian.PutUint32(buf[0:4], 1)
+	copy(buf[4:8], btype)
+	binary.BigEndian.PutUint64(buf[8:16], uint64(size+16))
+	return buf[:]
+}
+
+// makeBox returns a complete box with the specified type and contents.
+func makeBox(btype string, data []byte) []byte {
+	return append(boxHeader(btype, int64(len(data))), data...)
+}
+
+// A parser extracts big-endian integers and strings from a byte slice.  Once
+// it runs out of data, it returns zero values and sets its err field.
+type parser struct {
+	by  []byte
+	err error
+}
+
+// uint returns the next size bytes as an unsigned integer.  A size of zero
+// returns zero without consuming anything.
+func (p *parser) uint(size int) (v uint64) {
+	if len(p.by) < size {
+		p.by, p.err = nil, errors.New("truncated")
+		return 0
+	}
+	for i := 0; i < size; i++ {
+		v = v<<8 | uint64(p.by[i])
+	}
+	p.by = p.by[size:]
+	return v
+}
+
+// fourcc returns the next four bytes as a string.
+func (p *parser) fourcc() string {
+	if len(p.by) < 4 {
+		p.by, p.err = nil, errors.New("truncated")
+		return ""
+	}
+	s := string(p.by[:4])
+	p.by = p.by[4:]
+	return s
+}
+
+// str returns the next null-terminated string.
+func (p *parser) str() string {
+	idx := bytes.IndexByte(p.by, 0)
+	if idx < 0 {
+		p.by, p.err = nil, errors.New("unterminated string")
+		return ""
+	}
+	s := string(p.by[:idx])
+	p.by = p.by[idx+1:]
+	return s
+}
+
+// putUint appends an unsigned integer of the specified size to the buffer.
+func putUint(buf *bytes.Buffer, v uint64, size int) {
+	for i := size - 1; i >= 0; i-- {
+		buf.WriteByte(byte(v >> (8 * i)))
+	}
+}
@@ -0,0 +1,425 @@
+// Package isobmff handles marshaling and unmarshaling of files in the ISO base
+// media file format (ISO/IEC 14496-12), such as HEIF images and QuickTime and
+// MP4 movies.
+package isobmff
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers"
+)
+
+// ISOBMFF is a container for an ISO base media file.
+type ISOBMFF struct {
+	r     metadata.Reader
+	boxes []*box // top-level boxes
+	// The following describe the top-level meta box, if any.
+	meta           *box
+	metaChildren   []*box
+	idat           *box
+	iinf           *box
+	iloc           *box
+	iref           *box
+	pitm           uint32
+	iinfVersion    uint8
+	ilocVersion    uint8
+	irefVersion    uint8
+	ilocOffsetSize int
+	ilocLengthSize int
+	ilocIndexSize  int
+	items          map[uint32]*item
+	infes          []*item // items in iinf order
+	locs           []*item // items in iloc order
+	refs           []*reference
+	maxItemID      uint32
+	exif           *item
+	xmp            *item
+	// The following describe the top-level moov box, if any.
+	movie *movie
+	// The following describe the top-level uuid box containing XMP, if any.
+	// It is used only in files that have no item information.
+	xmpBox          *box
+	xmpBoxReader    metadata.Reader
+	xmpBoxContainer containers.Container
+	// The following are computed by Layout.
+	pieces []piece
+	size   int64
+	err    error
+}
+
+// A piece is a contiguous part of the rendered file.  It is either a range of
+// bytes copied from the input file, literal data, the rendering of a container,
+// or a run of zeros.
+type piece struct {
+	from, to  int64
+	data      []byte
+	container containers.Container
+	csize     int64 // rendered size of container
+	zeros     int64
+}
+
+var _ containers.Container = (*ISOBMFF)(nil) // verify interface compliance
+
+// Read reads and parses the container structure from the supplied Reader.  The
+// reader will continue to be used after Read returns, and must remain open and
+// usable as long as the Container is in scope.
+func (c *ISOBMFF) Read(r metadata.Reader) (err error) {
+	c.r = r
+	if c.boxes, err = readBoxes(r, 0, r.Size()); err != nil {
+		return fmt.Errorf("ISOBMFF: %s", err)
+	}
+	if meta := findBox(c.boxes, "meta"); meta != nil {
+		if err = c.readMeta(meta); err != nil {
+			return fmt.Errorf("ISOBMFF: %s", err)
+		}
+	}
+	if moov := findBox(c.boxes, "moov"); moov != nil {
+		if err = c.readMovie(moov); err != nil {
+			return fmt.Errorf("ISOBMFF: %s", err)
+		}
+	}
+	if err = c.readXMPBox(); err != nil {
+		return fmt.Errorf("ISOBMFF: %s", err)
+	}
+	return nil
+}
+
+// Empty returns whether the container is empty (and should therefore be omitted
+// from the written file, along with whatever tag in the parent container points
+// to it).
+func (c *ISOBMFF) Empty() bool { return false } // files are never empty
+
+// Dirty returns whether any of the metadata have been changed.
+func (c *ISOBMFF) Dirty() bool {
+	return c.itemsDirty() || c.xmpBoxDirty() || (c.movie != nil && c.movie.dirty)
+}
+
+// itemsDirty returns whether any of the metadata items have been changed.
+func (c *ISOBMFF) itemsDirty() bool {
+	for _, it := range []*item{c.exif, c.xmp} {
+		if it != nil && it.container != nil && it.container.Dirty() {
+			return true
+		}
+	}
+	return false
+}
+
+// xmpBoxDirty returns whether the XMP in the top-level uuid box has changed.
+func (c *ISOBMFF) xmpBoxDirty() bool {
+	return c.xmpBoxContainer != nil && c.xmpBoxContainer.Dirty()
+}
+
+// Layout computes the rendered layout of the container, i.e. prepares for a
+// call to Write, and returns what the rendered size of the container will be.
+//
+// Metadata items whose rendered size is no larger than their original size are
+// rewritten in place.  Items that have grown, or that are new, are relocated to
+// a new mdat box at the end of the file.  Since this requires changing the meta
+// box, the offsets of all items following the meta box are adjusted
+// accordingly.
+//
+// The moov box and the top-level XMP uuid box are rewritten in place if they
+// still fit there.  Otherwise, they are replaced with free boxes and moved to
+// the end of the file.  Either way, the media data in the file do not move.
+func (c *ISOBMFF) Layout() int64 {
+	var (
+		replace  = make(map[*box][]piece)
+		patches  []*item
+		appended []piece
+	)
+	c.pieces, c.err = nil, nil
+	c.size = c.r.Size()
+	if !c.Dirty() {
+		c.pieces = []piece{{from: 0, to: c.size}}
+		return c.size
+	}
+	if c.itemsDirty() {
+		if patches, appended, c.err = c.layoutItems(replace); c.err != nil {
+			c.size = 0
+			return 0
+		}
+	}
+	if c.xmpBoxDirty() {
+		var pieces []piece
+
+		if !c.xmpBoxContainer.Empty() {
+			pieces = c.renderXMPBox()
+		}
+		appended = replaceBox(c.xmpBox, pieces, replace, appended)
+	}
+	if c.movie != nil && c.movie.dirty {
+		appended = replaceBox(c.movie.moov, c.renderMovie(), replace, appended)
+	}
+	// Now assemble the pieces of the output file.
+	for _, b := range c.boxes {
+		var start = b.offset
+
+		if pieces, ok := replace[b]; ok {
+			c.pieces = append(c.pieces, pieces...)
+			continue
+		}
+		if b.toEOF && len(appended) != 0 {
+			// This box extends to the end of the file, but we're
+			// about to add one after it.  Give it an explicit size.
+			if b.size > math.MaxUint32 {
+				c.err = fmt.Errorf("ISOBMFF: %s box too large", b.btype)
+				c.size = 0
+				return 0
+			}
+			c.pieces = append(c.pieces, piece{data: boxHeader(b.btype, b.size-8)[:8]})
+			start += 8
+		}
+		for len(patches) != 0 && int64(patches[0].extents[0].offset) < b.offset+b.size {
+			var it = patches[0]
+
+			c.pieces = append(c.pieces, piece{from: start, to: int64(it.extents[0].offset)})
+			c.pieces = append(c.pieces, itemPieces(it, int64(it.extents[0].length)-it.length)...)
+			start = int64(it.extents[0].offset + it.extents[0].length)
+			patches = patches[1:]
+		}
+		c.pieces = append(c.pieces, piece{from: start, to: b.offset + b.size})
+	}
+	c.pieces = append(c.pieces, appended...)
+	c.size = piecesSize(c.pieces)
+	return c.size
+}
+
+// layoutItems lays out the changed metadata items and the meta box that
+// describes them.  It records the new meta box in replace, adjusts c.size for
+// the change in its size, and returns the items to be rewritten in place (in
+// file order) and the pieces to be appended to the file.
+func (c *ISOBMFF) layoutItems(replace map[*box][]piece) (patches []*item, appended []piece, err error) {
+	var (
+		newItems  []*item
+		relocs    []*item
+		appendLen int64
+		header    []byte
+		delta     int64
+		metaEnd   int64
+		newMeta   []piece
+	)
+	if c.meta == nil || c.iinf == nil || c.iloc == nil {
+		return nil, nil, errors.New("ISOBMFF: no item information to update")
+	}
+	// Decide what to do with each of the metadata items.
+	for _, it := range []*item{c.exif, c.xmp} {
+		if it == nil {
+			continue
+		}
+		it.disposition = unchanged
+		if it.container == nil || !it.container.Dirty() {
+			continue
+		}
+		if it.container.Empty() {
+			if !it.isNew {
+				it.disposition = removed
+			}
+			continue
+		}
+		it.length = int64(len(it.prefix)) + it.container.Layout()
+		if !it.isNew && it.cm == 0 && len(it.extents) == 1 && it.length <= int64(it.extents[0].length) &&
+			!c.inMeta(it.extents[0]) {
+			it.disposition = inPlace
+			patches = append(patches, it)
+			continue
+		}
+		it.disposition = relocated
+		it.offset = appendLen
+		appendLen += it.length
+		relocs = append(relocs, it)
+		if it.isNew {
+			if it.id == 0 {
+				it.id = c.newItemID()
+			}
+			newItems = append(newItems, it)
+		}
+	}
+	if c.ilocVersion < 2 && c.maxItemID > 0xFFFF {
+		return nil, nil, errors.New("ISOBMFF: too many items")
+	}
+	// Make sure the offset and length fields in the iloc are large enough.
+	if c.r.Size()+appendLen+1<<20 > math.MaxUint32 {
+		c.ilocOffsetSize = 8
+	} else if c.ilocOffsetSize < 4 {
+		c.ilocOffsetSize = 4
+	}
+	if c.ilocLengthSize < 4 {
+		c.ilocLengthSize = 4
+	}
+	// Render the meta box once with dummy offsets, to learn its size.
+	newMeta = c.renderMeta(newItems, func(o int64) int64 { return o })
+	delta = piecesSize(newMeta) - c.meta.size
+	metaEnd = c.meta.offset + c.meta.size
+	if delta != 0 && c.movie != nil {
+		return nil, nil, errors.New("ISOBMFF: cannot resize meta box in a file with a moov box")
+	}
+	var shift = func(o int64) int64 {
+		if o >= metaEnd {
+			return o + delta
+		}
+		return o
+	}
+	// Compute the new offsets of the changed items, and render the meta
+	// box again with the real offsets.
+	c.size += delta
+	if appendLen != 0 {
+		header = boxHeader("mdat", appendLen)
+		appended = append(appended, piece{data: header})
+	}
+	for _, it := range patches {
+		it.offset = shift(int64(it.extents[0].offset))
+	}
+	for _, it := range relocs {
+		it.offset += c.size + int64(len(header))
+		appended = append(appended, itemPieces(it, 0)...)
+	}
+	replace[c.meta] = c.renderMeta(newItems, shift)
+	sort.Slice(patches, func(i, j int) bool { return patches[i].extents[0].offset < patches[j].extents[0].offset })
+	return patches, appended, nil
+}
+
+// itemPieces returns the pieces for the rendering of an item, followed by the
+// specified amount of zero padding.
+func itemPieces(it *item, pad int64) (pieces []piece) {
+	if len(it.prefix) != 0 {
+		pieces = append(pieces, piece{data: it.prefix})
+	}
+	pieces = append(pieces, piece{container: it.container, csize: it.length - int64(len(it.prefix))})
+	if pad != 0 {
+		pieces = append(pieces, piece{zeros: pad})
+	}
+	return pieces
+}
+
+// replaceBox arranges for the box b (which may be nil) to be replaced by the
+// specified pieces.  If they fit in the space occupied by b, they replace it in
+// place, with a free box filling any leftover space.  Otherwise, b is replaced
+// by a free box, and the pieces are added to the list of pieces to be appended
+// to the file.  replaceBox returns the updated list.
+func replaceBox(b *box, pieces []piece, replace map[*box][]piece, appended []piece) []piece {
+	var size = piecesSize(pieces)
+
+	if b != nil && (size == b.size || size+8 <= b.size) {
+		if size < b.size {
+			pieces = append(pieces, freeBox(b.size-size)...)
+		}
+		replace[b] = pieces
+		return appended
+	}
+	if b != nil {
+		replace[b] = freeBox(b.size)
+	}
+	return append(appended, pieces...)
+}
+
+// freeBox returns the pieces of a free box of the specified total size.
+func freeBox(size int64) []piece {
+	var header = boxHeader("free", size-8)
+
+	if len(header) != 8 {
+		header = boxHeader("free", size-16)
+	}
+	return []piece{{data: header}, {zeros: size - int64(len(header))}}
+}
+
+// inMeta returns whether the specified extent overlaps the meta box.
+func (c *ISOBMFF) inMeta(ext extent) bool {
+	return int64(ext.offset) < c.meta.offset+c.meta.size && int64(ext.offset+ext.length) > c.meta.offset
+}
+
+// renderMeta renders the meta box, returning it as a list of pieces.
+func (c *ISOBMFF) renderMeta(newItems []*item, shift func(int64) int64) (pieces []piece) {
+	var (
+		iref   = c.renderIREF(newItems)
+		size   int64
+		vflags = make([]byte, 4)
+	)
+	c.r.ReadAt(vflags, c.meta.offset+c.meta.hsize)
+	pieces = append(pieces, piece{}, piece{data: vflags}) // header filled in below
+	for _, child := range c.metaChildren {
+		switch child {
+		case c.iinf:
+			pieces = append(pieces, piece{data: c.renderIINF(newItems)})
+		case c.iloc:
+			pieces = append(pieces, piece{data: c.renderILOC(newItems, shift)})
+		case c.iref:
+			if iref != nil {
+				pieces = append(pieces, piece{data: iref})
+			}
+		default:
+			pieces = append(pieces, piece{from: child.offset, to: child.offset + child.size})
+		}
+	}
+	if c.iref == nil && iref != nil {
+		pieces = append(pieces, piece{data: iref})
+	}
+	size = piecesSize(pieces)
+	pieces[0].data = boxHeader("meta", size)
+	return pieces
+}
+
+// piecesSize returns the total size of a list of pieces.
+func piecesSize(pieces []piece) (size int64) {
+	for _, p := range pieces {
+		switch {
+		case p.data != nil:
+			size += int64(len(p.data))
+		case p.container != nil:
+			size += p.csize
+		case p.zeros != 0:
+			size += p.zeros
+		default:
+			size += p.to - p.from
+		}
+	}
+	return size
+}
+
+// Write writes the rendered container to the specified writer.
+func (c *ISOBMFF) Write(w io.Writer) (count int, err error) {
+	var (
+		n   int
+		n64 int64
+	)
+	if c.err != nil {
+		return 0, c.err
+	}
+	for _, p := range c.pieces {
+		switch {
+		case p.data != nil:
+			n, err = w.Write(p.data)
+			count += n
+		case p.container != nil:
+			n, err = p.container.Write(w)
+			count += n
+		case p.zeros != 0:
+			n64, err = io.CopyN(w, zeroReader{}, p.zeros)
+			count += int(n64)
+		default:
+			n64, err = io.Copy(w, io.NewSectionReader(c.r, p.from, p.to-p.from))
+			count += int(n64)
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+	if int64(count) != c.size {
+		panic("actual size different from predicted size")
+	}
+	return count, nil
+}
+
+// zeroReader is an io.Reader that returns an infinite stream of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(buf []byte) (int, error) {
+	for i := range buf {
+		buf[i] = 0
+	}
+	return len(buf), nil
+}
@@ -0,0 +1,288 @@
+package isobmff
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/raw"
+)
+
+// fullBox returns a full box with the specified type, version, and contents.
+func fullBox(btype string, version byte, data ...[]byte) []byte {
+	return makeBox(btype, append([]byte{version, 0, 0, 0}, bytes.Join(data, nil)...))
+}
+
+// makeTestFile returns a minimal HEIF file with an image item (ID 1) whose
+// data are "IMAGEDATA", and, if exif is non-nil, an Exif item (ID 2) with the
+// specified contents.
+func makeTestFile(exif []byte) []byte {
+	var (
+		ftyp  = makeBox("ftyp", []byte("heic\000\000\000\000mif1heic"))
+		infes = [][]byte{fullBox("infe", 2, []byte{0, 1, 0, 0}, []byte("hvc1\000"))}
+		mdat  = append([]byte("IMAGEDATA"), exif...)
+	)
+	if exif != nil {
+		infes = append(infes, fullBox("infe", 2, []byte{0, 2, 0, 0}, []byte("Exif\000")))
+	}
+	var iloc = func(mdatOffset uint32) []byte {
+		var buf bytes.Buffer
+		buf.Write([]byte{0x44, 0x00}) // offset_size 4, length_size 4
+		putUint(&buf, uint64(len(infes)), 2)
+		buf.Write([]byte{0, 1, 0, 0, 0, 1})
+		putUint(&buf, uint64(mdatOffset), 4)
+		putUint(&buf, 9, 4)
+		if exif != nil {
+			buf.Write([]byte{0, 2, 0, 0, 0, 1})
+			putUint(&buf, uint64(mdatOffset+9), 4)
+			putUint(&buf, uint64(len(exif)), 4)
+		}
+		return fullBox("iloc", 0, buf.Bytes())
+	}
+	var meta = func(mdatOffset uint32) []byte {
+		var iinf = fullBox("iinf", 0, []byte{0, byte(len(infes))}, bytes.Join(infes, nil))
+		return fullBox("meta", 0, fullBox("pitm", 0, []byte{0, 1}), iinf, iloc(mdatOffset))
+	}
+	mdatOffset := uint32(len(ftyp) + len(meta(0)) + 8)
+	return bytes.Join([][]byte{ftyp, meta(mdatOffset), makeBox("mdat", mdat)}, nil)
+}
+
+// itemData returns the data of the specified item in the file.
+func itemData(t *testing.T, file []byte, id uint32) []byte {
+	var c ISOBMFF
+	if err := c.Read(bytes.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+	it := c.items[id]
+	if it == nil {
+		t.Fatalf("item %d not found", id)
+	}
+	if err := c.readItem(it); err != nil {
+		t.Fatal(err)
+	}
+	by, _ := io.ReadAll(it.reader)
+	return by
+}
+
+func rewrite(t *testing.T, file []byte, exif []byte, xmp []byte) []byte {
+	var (
+		c   ISOBMFF
+		buf bytes.Buffer
+	)
+	if err := c.Read(bytes.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+	if exif != nil {
+		var r raw.Raw
+		if c.EXIF() != nil {
+			r.Read(c.EXIF())
+		}
+		c.SetEXIFContainer(&r)
+		r.SetData(exif)
+	}
+	if xmp != nil {
+		var r raw.Raw
+		c.SetXMPContainer(&r)
+		r.SetData(xmp)
+	}
+	c.Layout()
+	if _, err := c.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadEXIF(t *testing.T) {
+	var c ISOBMFF
+	file := makeTestFile([]byte("\000\000\000\006Exif\000\000MM"))
+	if err := c.Read(bytes.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+	by, _ := io.ReadAll(c.EXIF())
+	if string(by) != "MM" {
+		t.Errorf("EXIF: got %q, expected \"MM\"", by)
+	}
+	if c.XMP() != nil {
+		t.Error("XMP: got data, expected nil")
+	}
+}
+
+func TestTruncatedIREF(t *testing.T) {
+	var (
+		c    ISOBMFF
+		ftyp = makeBox("ftyp", []byte("heic\000\000\000\000mif1heic"))
+		meta = fullBox("meta", 0, makeBox("iref", nil), fullBox("pitm", 0, []byte{0, 1}))
+	)
+	if err := c.Read(bytes.NewReader(append(ftyp, meta...))); err == nil {
+		t.Error("expected error for truncated iref box")
+	}
+}
+
+func TestUnchanged(t *testing.T) {
+	file := makeTestFile([]byte("\000\000\000\006Exif\000\000MM"))
+	if out := rewrite(t, file, nil, nil); !bytes.Equal(out, file) {
+		t.Error("output differs from input")
+	}
+}
+
+func TestShrinkInPlace(t *testing.T) {
+	file := makeTestFile([]byte("\000\000\000\006Exif\000\000MMMM"))
+	out := rewrite(t, file, []byte("II"), nil)
+	if len(out) != len(file) {
+		t.Errorf("size changed from %d to %d", len(file), len(out))
+	}
+	if by := itemData(t, out, 2); string(by) != "\000\000\000\006Exif\000\000II" {
+		t.Errorf("Exif item: got %q", by)
+	}
+	if by := itemData(t, out, 1); string(by) != "IMAGEDATA" {
+		t.Errorf("image item: got %q", by)
+	}
+}
+
+func TestGrowAndAdd(t *testing.T) {
+	file := makeTestFile([]byte("\000\000\000\006Exif\000\000MM"))
+	out := rewrite(t, file, []byte("a much longer TIFF block"), []byte("<x/>"))
+	if by := itemData(t, out, 1); string(by) != "IMAGEDATA" {
+		t.Errorf("image item: got %q", by)
+	}
+	if by := itemData(t, out, 2); string(by) != "\000\000\000\006Exif\000\000a much longer TIFF block" {
+		t.Errorf("Exif item: got %q", by)
+	}
+	var c ISOBMFF
+	if err := c.Read(bytes.NewReader(out)); err != nil {
+		t.Fatal(err)
+	}
+	if by, _ := io.ReadAll(c.XMP()); string(by) != "<x/>" {
+		t.Errorf("XMP item: got %q", by)
+	}
+	if len(c.refs) != 1 || c.refs[0].rtype != "cdsc" || c.refs[0].from != c.xmp.id || c.refs[0].to[0] != 1 {
+		t.Errorf("XMP item reference missing")
+	}
+}
+
+func TestAddEXIF(t *testing.T) {
+	file := makeTestFile(nil)
+	out := rewrite(t, file, []byte("MM"), nil)
+	if by := itemData(t, out, 1); string(by) != "IMAGEDATA" {
+		t.Errorf("image item: got %q", by)
+	}
+	if by := itemData(t, out, 2); string(by) != "\000\000\000\006Exif\000\000MM" {
+		t.Errorf("Exif item: got %q", by)
+	}
+}
+
+// makeTestMovie returns a minimal QuickTime movie file with a creation time of
+// 2001-02-03 04:05:06 UTC, a \xA9nam user data item and a title key with the
+// specified value, and media data "MEDIADATA".
+func makeTestMovie(title string) []byte {
+	var (
+		ftyp = makeBox("ftyp", []byte("qt  \000\000\000\000qt  "))
+		mdat = makeBox("mdat", []byte("MEDIADATA"))
+		mvhd = fullBox("mvhd", 0, []byte{0xB6, 0xA1, 0x33, 0xF2}, make([]byte, 96))
+		udta = makeBox("udta", makeBox("\xA9nam", makeUserDataText(title)))
+		hdlr = fullBox("hdlr", 0, make([]byte, 4), []byte("mdta"), make([]byte, 13))
+		keys = fullBox("keys", 0, []byte{0, 0, 0, 1, 0, 0, 0, 33}, []byte("mdtacom.apple.quicktime.title"))
+		ilst = makeBox("ilst", makeBox("\000\000\000\001", makeBox("data", append([]byte{0, 0, 0, 1, 0, 0, 0, 0}, title...))))
+		moov = makeBox("moov", bytes.Join([][]byte{mvhd, udta, makeBox("meta", bytes.Join([][]byte{hdlr, keys, ilst}, nil))}, nil))
+	)
+	return bytes.Join([][]byte{ftyp, mdat, moov}, nil)
+}
+
+// readMovie reads the specified movie file, and verifies that its media data
+// have not moved.
+func readMovie(t *testing.T, file []byte) *ISOBMFF {
+	var c ISOBMFF
+
+	if err := c.Read(bytes.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(file[20:37], []byte("\000\000\000\021mdatMEDIADATA")) {
+		t.Error("media data moved")
+	}
+	return &c
+}
+
+func TestMovieRead(t *testing.T) {
+	c := readMovie(t, makeTestMovie("Title"))
+	if ct := c.CreationTime(); !ct.Equal(time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)) {
+		t.Errorf("CreationTime: got %s", ct)
+	}
+	if s := c.UserDataText("\xA9nam"); s != "Title" {
+		t.Errorf("\\xA9nam: got %q", s)
+	}
+	if s := c.Key("com.apple.quicktime.title"); s != "Title" {
+		t.Errorf("title key: got %q", s)
+	}
+}
+
+func TestMovieUnchanged(t *testing.T) {
+	var buf bytes.Buffer
+
+	file := makeTestMovie("Title")
+	c := readMovie(t, file)
+	c.SetKey("com.apple.quicktime.title", "Title")
+	c.Layout()
+	if _, err := c.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), file) {
+		t.Error("output differs from input")
+	}
+}
+
+func TestMovieShrink(t *testing.T) {
+	var buf bytes.Buffer
+
+	file := makeTestMovie("A Long Title")
+	c := readMovie(t, file)
+	c.SetKey("com.apple.quicktime.title", "Short")
+	c.SetUserDataText("\xA9nam", "")
+	c.Layout()
+	if _, err := c.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != len(file) {
+		t.Errorf("size changed from %d to %d", len(file), buf.Len())
+	}
+	c = readMovie(t, buf.Bytes())
+	if s := c.Key("com.apple.quicktime.title"); s != "Short" {
+		t.Errorf("title key: got %q", s)
+	}
+	if s := c.UserDataText("\xA9nam"); s != "" {
+		t.Errorf("\\xA9nam: got %q", s)
+	}
+}
+
+func TestMovieGrow(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		r   raw.Raw
+	)
+	c := readMovie(t, makeTestMovie("Title"))
+	c.SetKey("com.apple.quicktime.description", "A description")
+	c.SetUserDataText("\xA9xyz", "+37.3354-122.0199/")
+	c.SetCreationTime(time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC))
+	c.SetXMPContainer(&r)
+	r.SetData([]byte("<x/>"))
+	c.Layout()
+	if _, err := c.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	c = readMovie(t, buf.Bytes())
+	if s := c.Key("com.apple.quicktime.title"); s != "Title" {
+		t.Errorf("title key: got %q", s)
+	}
+	if s := c.Key("com.apple.quicktime.description"); s != "A description" {
+		t.Errorf("description key: got %q", s)
+	}
+	if s := c.UserDataText("\xA9xyz"); s != "+37.3354-122.0199/" {
+		t.Errorf("\\xA9xyz: got %q", s)
+	}
+	if ct := c.CreationTime(); !ct.Equal(time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)) {
+		t.Errorf("CreationTime: got %s", ct)
+	}
+	if by, _ := io.ReadAll(c.XMP()); string(by) != "<x/>" {
+		t.Errorf("XMP: got %q", by)
+	}
+}
@@ -0,0 +1,579 @@
+package isobmff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers"
+)
+
+const xmpContentType = "application/rdf+xml"
+
+// defaultEXIFPrefix is the prefix we put at the start of newly created Exif
+// items:  the offset to the TIFF header, followed by the traditional JPEG
+// namespace string.
+var defaultEXIFPrefix = []byte{0, 0, 0, 6, 'E', 'x', 'i', 'f', 0, 0}
+
+// An item is an item described in the meta box of the file.
+type item struct {
+	id      uint32
+	itype   string
+	ctype   string // content type, for "mime" items
+	infe    []byte // raw infe box; nil for new items
+	located bool   // whether the item appears in the iloc box
+	cm      uint8  // construction method
+	dri     uint16 // data reference index
+	extents []extent
+	// The following are used only for the Exif and XMP items, which are
+	// the only ones we change.
+	isNew     bool
+	prefix    []byte // Exif only: bytes preceding the TIFF header
+	reader    metadata.Reader
+	container containers.Container
+	// The following are computed by Layout.
+	disposition disposition
+	length      int64 // rendered length of the item
+	offset      int64 // new file offset of the item
+}
+
+// An extent is a contiguous range of bytes of an item.  The offset includes the
+// item's base offset.
+type extent struct {
+	index  uint64
+	offset uint64
+	length uint64
+}
+
+// A reference is a set of references of a single type from one item to other
+// items.
+type reference struct {
+	rtype string
+	from  uint32
+	to    []uint32
+}
+
+// A disposition indicates what Layout decided to do with an item.
+type disposition int
+
+const (
+	unchanged disposition = iota
+	removed
+	inPlace
+	relocated
+)
+
+// readMeta reads the contents of the meta box.
+func (c *ISOBMFF) readMeta(meta *box) (err error) {
+	var children []*box
+
+	c.meta = meta
+	if children, err = readBoxes(c.r, meta.offset+meta.hsize+4, meta.offset+meta.size); err != nil {
+		return fmt.Errorf("meta: %s", err)
+	}
+	c.metaChildren = children
+	c.items = make(map[uint32]*item)
+	for _, child := range children {
+		switch child.btype {
+		case "pitm":
+			err = c.readPITM(child)
+		case "iinf":
+			err = c.readIINF(child)
+		case "iloc":
+			err = c.readILOC(child)
+		case "iref":
+			err = c.readIREF(child)
+		case "idat":
+			c.idat = child
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", child.btype, err)
+		}
+	}
+	c.findMetadataItems()
+	return nil
+}
+
+// item returns the item with the specified ID, creating it if needed.
+func (c *ISOBMFF) item(id uint32) *item {
+	if it := c.items[id]; it != nil {
+		return it
+	}
+	it := &item{id: id}
+	c.items[id] = it
+	return it
+}
+
+func (c *ISOBMFF) readPITM(b *box) (err error) {
+	var by []byte
+
+	if by, err = b.data(c.r); err != nil {
+		return err
+	}
+	p := parser{by: by}
+	if p.uint(1) == 0 {
+		p.uint(3)
+		c.pitm = uint32(p.uint(2))
+	} else {
+		p.uint(3)
+		c.pitm = uint32(p.uint(4))
+	}
+	return p.err
+}
+
+func (c *ISOBMFF) readIINF(b *box) (err error) {
+	var (
+		by    []byte
+		infes []*box
+		start int64
+	)
+	if by, err = b.data(c.r); err != nil {
+		return err
+	}
+	p := parser{by: by}
+	c.iinf = b
+	c.iinfVersion = uint8(p.uint(1))
+	p.uint(3)
+	start = b.offset + b.hsize + 6
+	if c.iinfVersion == 0 {
+		p.uint(2)
+	} else {
+		p.uint(4)
+		start += 2
+	}
+	if p.err != nil {
+		return p.err
+	}
+	if infes, err = readBoxes(c.r, start, b.offset+b.size); err != nil {
+		return err
+	}
+	for _, infe := range infes {
+		if infe.btype != "infe" {
+			continue
+		}
+		if err = c.readINFE(infe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ISOBMFF) readINFE(b *box) (err error) {
+	var (
+		by  []byte
+		raw = make([]byte, b.size)
+		it  *item
+	)
+	if _, err = c.r.ReadAt(raw, b.offset); err != nil {
+		return err
+	}
+	by = raw[b.hsize:]
+	p := parser{by: by}
+	version := p.uint(1)
+	p.uint(3)
+	switch version {
+	case 0, 1:
+		it = c.item(uint32(p.uint(2)))
+		p.uint(2) // item_protection_index
+		p.str()   // item_name
+		it.itype = "mime"
+		it.ctype = p.str()
+	case 2, 3:
+		if version == 2 {
+			it = c.item(uint32(p.uint(2)))
+		} else {
+			it = c.item(uint32(p.uint(4)))
+		}
+		p.uint(2) // item_protection_index
+		it.itype = p.fourcc()
+		p.str() // item_name
+		if it.itype == "mime" {
+			it.ctype = p.str()
+		}
+	default:
+		return fmt.Errorf("infe: unsupported version %d", version)
+	}
+	if p.err != nil {
+		return fmt.Errorf("infe: %s", p.err)
+	}
+	it.infe = raw
+	c.infes = append(c.infes, it)
+	return nil
+}
+
+func (c *ISOBMFF) readILOC(b *box) (err error) {
+	var by []byte
+
+	if by, err = b.data(c.r); err != nil {
+		return err
+	}
+	p := parser{by: by}
+	c.iloc = b
+	c.ilocVersion = uint8(p.uint(1))
+	if c.ilocVersion > 2 {
+		return fmt.Errorf("unsupported version %d", c.ilocVersion)
+	}
+	p.uint(3)
+	sizes := p.uint(2)
+	c.ilocOffsetSize = int(sizes>>12) & 0xF
+	c.ilocLengthSize = int(sizes>>8) & 0xF
+	baseOffsetSize := int(sizes>>4) & 0xF
+	if c.ilocVersion != 0 {
+		c.ilocIndexSize = int(sizes) & 0xF
+	}
+	var count uint64
+	if c.ilocVersion < 2 {
+		count = p.uint(2)
+	} else {
+		count = p.uint(4)
+	}
+	for i := uint64(0); i < count && p.err == nil; i++ {
+		var it *item
+
+		if c.ilocVersion < 2 {
+			it = c.item(uint32(p.uint(2)))
+		} else {
+			it = c.item(uint32(p.uint(4)))
+		}
+		if c.ilocVersion != 0 {
+			it.cm = uint8(p.uint(2) & 0xF)
+		}
+		it.dri = uint16(p.uint(2))
+		base := p.uint(baseOffsetSize)
+		ecount := p.uint(2)
+		it.extents = make([]extent, ecount)
+		for j := range it.extents {
+			if c.ilocVersion != 0 {
+				it.extents[j].index = p.uint(c.ilocIndexSize)
+			}
+			it.extents[j].offset = base + p.uint(c.ilocOffsetSize)
+			it.extents[j].length = p.uint(c.ilocLengthSize)
+		}
+		it.located = true
+		c.locs = append(c.locs, it)
+	}
+	return p.err
+}
+
+func (c *ISOBMFF) readIREF(b *box) (err error) {
+	var (
+		by   []byte
+		refs []*box
+	)
+	if by, err = b.data(c.r); err != nil {
+		return err
+	}
+	if len(by) < 4 {
+		return errors.New("truncated")
+	}
+	c.iref = b
+	c.irefVersion = by[0]
+	idsize := 2
+	if c.irefVersion != 0 {
+		idsize = 4
+	}
+	if refs, err = readBoxes(c.r, b.offset+b.hsize+4, b.offset+b.size); err != nil {
+		return err
+	}
+	for _, rb := range refs {
+		if by, err = rb.data(c.r); err != nil {
+			return err
+		}
+		p := parser{by: by}
+		ref := &reference{rtype: rb.btype, from: uint32(p.uint(idsize))}
+		ref.to = make([]uint32, p.uint(2))
+		for i := range ref.to {
+			ref.to[i] = uint32(p.uint(idsize))
+		}
+		if p.err != nil {
+			return fmt.Errorf("%s: %s", rb.btype, p.err)
+		}
+		c.refs = append(c.refs, ref)
+	}
+	return nil
+}
+
+// findMetadataItems locates the Exif and XMP items, if any, and prepares
+// readers for their contents.
+func (c *ISOBMFF) findMetadataItems() {
+	for _, it := range c.infes {
+		if !it.located || it.dri != 0 || it.cm > 1 || (it.cm == 1 && c.idat == nil) {
+			continue // we don't know how to handle this one
+		}
+		switch {
+		case it.itype == "Exif" && c.exif == nil:
+			if c.readItem(it) != nil || it.reader.Size() < 4 {
+				continue
+			}
+			var buf [4]byte
+			it.reader.ReadAt(buf[:], 0)
+			skip := int64(binary.BigEndian.Uint32(buf[:])) + 4
+			if skip > it.reader.Size() {
+				continue
+			}
+			it.prefix = make([]byte, skip)
+			it.reader.ReadAt(it.prefix, 0)
+			it.reader = io.NewSectionReader(it.reader, skip, it.reader.Size()-skip)
+			c.exif = it
+		case it.itype == "mime" && it.ctype == xmpContentType && c.xmp == nil:
+			if c.readItem(it) != nil {
+				continue
+			}
+			// As with JPEG, remove any trailing null bytes, which the
+			// RDF parser can't handle.
+			var buf [1]byte
+			size := it.reader.Size()
+			for size > 0 {
+				if it.reader.ReadAt(buf[:], size-1); buf[0] != 0 {
+					break
+				}
+				size--
+			}
+			it.reader = io.NewSectionReader(it.reader, 0, size)
+			c.xmp = it
+		}
+	}
+}
+
+// readItem sets a reader for the contents of the item.
+func (c *ISOBMFF) readItem(it *item) (err error) {
+	var base int64
+
+	if it.cm == 1 {
+		base = c.idat.offset + c.idat.hsize
+	}
+	if len(it.extents) == 1 {
+		ext := it.extents[0]
+		if base+int64(ext.offset+ext.length) > c.r.Size() {
+			return errors.New("item extends beyond end of file")
+		}
+		it.reader = io.NewSectionReader(c.r, base+int64(ext.offset), int64(ext.length))
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, ext := range it.extents {
+		if _, err = io.Copy(&buf, io.NewSectionReader(c.r, base+int64(ext.offset), int64(ext.length))); err != nil {
+			return err
+		}
+	}
+	it.reader = bytes.NewReader(buf.Bytes())
+	return nil
+}
+
+// EXIF returns the contents of the Exif item, if any, starting with the TIFF
+// header.
+func (c *ISOBMFF) EXIF() metadata.Reader {
+	if c.exif != nil && c.exif.reader != nil {
+		return c.exif.reader
+	}
+	return nil
+}
+
+// XMP returns the contents of the XMP item (or, in a movie, the XMP uuid box),
+// if any.
+func (c *ISOBMFF) XMP() metadata.Reader {
+	if c.xmp != nil && c.xmp.reader != nil {
+		return c.xmp.reader
+	}
+	if c.xmpBoxReader != nil {
+		return c.xmpBoxReader
+	}
+	return nil
+}
+
+// SetEXIFContainer sets the contents of the Exif item to those provided by the
+// supplied container.
+func (c *ISOBMFF) SetEXIFContainer(cont containers.Container) {
+	if c.exif == nil {
+		c.exif = &item{itype: "Exif", isNew: true, prefix: defaultEXIFPrefix}
+	}
+	c.exif.container = cont
+}
+
+// SetXMPContainer sets the contents of the XMP item to those provided by the
+// supplied container.  In a file with no item information (e.g., a movie), it
+// sets the contents of the top-level XMP uuid box instead.
+func (c *ISOBMFF) SetXMPContainer(cont containers.Container) {
+	if c.xmp == nil && c.iinf == nil {
+		c.xmpBoxContainer = cont
+		return
+	}
+	if c.xmp == nil {
+		c.xmp = &item{itype: "mime", ctype: xmpContentType, isNew: true}
+	}
+	c.xmp.container = cont
+}
+
+// newItemID returns an unused item ID.
+func (c *ISOBMFF) newItemID() (id uint32) {
+	for id = range c.items {
+		if id > c.maxItemID {
+			c.maxItemID = id
+		}
+	}
+	c.maxItemID++
+	return c.maxItemID
+}
+
+// makeINFE returns an infe box for a new item.
+func makeINFE(it *item) []byte {
+	var buf bytes.Buffer
+
+	if it.id <= 0xFFFF {
+		buf.Write([]byte{2, 0, 0, 0})
+		putUint(&buf, uint64(it.id), 2)
+	} else {
+		buf.Write([]byte{3, 0, 0, 0})
+		putUint(&buf, uint64(it.id), 4)
+	}
+	putUint(&buf, 0, 2) // item_protection_index
+	buf.WriteString(it.itype)
+	buf.WriteByte(0) // empty item_name
+	if it.itype == "mime" {
+		buf.WriteString(it.ctype)
+		buf.WriteByte(0)
+	}
+	return makeBox("infe", buf.Bytes())
+}
+
+// renderIINF renders the iinf box, including any new items and omitting any
+// removed ones.
+func (c *ISOBMFF) renderIINF(newItems []*item) []byte {
+	var (
+		buf   bytes.Buffer
+		infes [][]byte
+	)
+	for _, it := range c.infes {
+		if it.disposition != removed {
+			infes = append(infes, it.infe)
+		}
+	}
+	for _, it := range newItems {
+		infes = append(infes, makeINFE(it))
+	}
+	if c.iinfVersion == 0 && len(infes) <= 0xFFFF {
+		buf.Write([]byte{0, 0, 0, 0})
+		putUint(&buf, uint64(len(infes)), 2)
+	} else {
+		buf.Write([]byte{1, 0, 0, 0})
+		putUint(&buf, uint64(len(infes)), 4)
+	}
+	for _, infe := range infes {
+		buf.Write(infe)
+	}
+	return makeBox("iinf", buf.Bytes())
+}
+
+// renderIREF renders the iref box, including references from any new items
+// and omitting references to or from any removed ones.
+func (c *ISOBMFF) renderIREF(newItems []*item) []byte {
+	var (
+		buf    bytes.Buffer
+		refs   []*reference
+		idsize = 2
+	)
+	for _, ref := range c.refs {
+		if it := c.items[ref.from]; it != nil && it.disposition == removed {
+			continue
+		}
+		var to []uint32
+		for _, id := range ref.to {
+			if it := c.items[id]; it == nil || it.disposition != removed {
+				to = append(to, id)
+			}
+		}
+		if len(to) != 0 {
+			refs = append(refs, &reference{ref.rtype, ref.from, to})
+		}
+	}
+	if c.pitm != 0 {
+		for _, it := range newItems {
+			refs = append(refs, &reference{"cdsc", it.id, []uint32{c.pitm}})
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	if c.irefVersion != 0 || c.maxItemID > 0xFFFF {
+		idsize = 4
+		buf.Write([]byte{1, 0, 0, 0})
+	} else {
+		buf.Write([]byte{0, 0, 0, 0})
+	}
+	for _, ref := range refs {
+		var rbuf bytes.Buffer
+
+		putUint(&rbuf, uint64(ref.from), idsize)
+		putUint(&rbuf, uint64(len(ref.to)), 2)
+		for _, id := range ref.to {
+			putUint(&rbuf, uint64(id), idsize)
+		}
+		buf.Write(makeBox(ref.rtype, rbuf.Bytes()))
+	}
+	return makeBox("iref", buf.Bytes())
+}
+
+// renderILOC renders the iloc box, with the new locations of any changed items
+// and offsets shifted by the specified function.
+func (c *ISOBMFF) renderILOC(newItems []*item, shift func(int64) int64) []byte {
+	var (
+		buf    bytes.Buffer
+		locs   []*item
+		idsize = 2
+	)
+	for _, it := range c.locs {
+		if it.disposition != removed {
+			locs = append(locs, it)
+		}
+	}
+	locs = append(locs, newItems...)
+	buf.WriteByte(c.ilocVersion)
+	buf.Write([]byte{0, 0, 0})
+	buf.WriteByte(byte(c.ilocOffsetSize<<4 | c.ilocLengthSize))
+	buf.WriteByte(byte(c.ilocIndexSize)) // base_offset_size is always zero
+	if c.ilocVersion < 2 {
+		putUint(&buf, uint64(len(locs)), 2)
+	} else {
+		idsize = 4
+		putUint(&buf, uint64(len(locs)), 4)
+	}
+	for _, it := range locs {
+		putUint(&buf, uint64(it.id), idsize)
+		switch it.disposition {
+		case inPlace, relocated:
+			// Changed items are always stored in the file itself, as
+			// a single extent.
+			if c.ilocVersion != 0 {
+				putUint(&buf, 0, 2)
+			}
+			putUint(&buf, 0, 2)
+			putUint(&buf, 1, 2)
+			if c.ilocVersion != 0 {
+				putUint(&buf, 0, c.ilocIndexSize)
+			}
+			putUint(&buf, uint64(it.offset), c.ilocOffsetSize)
+			putUint(&buf, uint64(it.length), c.ilocLengthSize)
+		default:
+			if c.ilocVersion != 0 {
+				putUint(&buf, uint64(it.cm), 2)
+			}
+			putUint(&buf, uint64(it.dri), 2)
+			putUint(&buf, uint64(len(it.extents)), 2)
+			for _, ext := range it.extents {
+				if c.ilocVersion != 0 {
+					putUint(&buf, ext.index, c.ilocIndexSize)
+				}
+				if it.cm == 0 && it.dri == 0 {
+					putUint(&buf, uint64(shift(int64(ext.offset))), c.ilocOffsetSize)
+				} else {
+					putUint(&buf, ext.offset, c.ilocOffsetSize)
+				}
+				putUint(&buf, ext.length, c.ilocLengthSize)
+			}
+		}
+	}
+	return makeBox("iloc", buf.Bytes())
+}
@@ -0,0 +1,529 @@
+package isobmff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// xmpUUID is the extended type of the top-level uuid box that holds the XMP
+// packet in a QuickTime or MP4 movie file.
+var xmpUUID = []byte{0xBE, 0x7A, 0xCF, 0xCB, 0x97, 0xA9, 0x42, 0xE8, 0x9C, 0x71, 0x99, 0x94, 0x91, 0xE3, 0xAF, 0xAC}
+
+// epoch1904 is the origin of the time stamps in a movie header box.
+var epoch1904 = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// languageUndetermined is the packed ISO-639-2/T code "und", used as the
+// language of new user data text items.
+const languageUndetermined = 0x55C4
+
+// A movie describes the metadata we handle from a top-level moov box.
+type movie struct {
+	moov     *box
+	children []*box
+	mvhdBox  *box
+	mvhd     []byte // entire mvhd box
+	// The following describe the moov/udta box, if any.
+	udta      *box
+	udtaItems []*udtaItem
+	// The following describe the moov/meta box, if any.
+	meta         *box
+	metaVFlags   []byte // version and flags, if meta is a full box
+	metaChildren []*box
+	keysBox      *box
+	ilstBox      *box
+	keys         []string // key names in the mdta namespace, in keys order
+	keyNS        []string // corresponding key namespaces
+	ilst         []*ilstItem
+	dirty        bool
+	keysDirty    bool
+}
+
+// A udtaItem is a single child of the moov/udta box.
+type udtaItem struct {
+	btype string
+	b     *box   // box in the input file, if unchanged
+	data  []byte // replacement contents, if changed
+}
+
+// An ilstItem is a single value in the moov/meta/ilst box.
+type ilstItem struct {
+	index uint32 // 1-based index into keys
+	b     *box   // box in the input file, if unchanged
+	dtype uint32 // well-known type of the value
+	value []byte
+}
+
+// readMovie reads the metadata from the top-level moov box.
+func (c *ISOBMFF) readMovie(moov *box) (err error) {
+	var mv = movie{moov: moov}
+
+	if mv.children, err = readBoxes(c.r, moov.offset+moov.hsize, moov.offset+moov.size); err != nil {
+		return fmt.Errorf("moov: %s", err)
+	}
+	if mv.mvhdBox = findBox(mv.children, "mvhd"); mv.mvhdBox != nil {
+		mv.mvhd = make([]byte, mv.mvhdBox.size)
+		if _, err = c.r.ReadAt(mv.mvhd, mv.mvhdBox.offset); err != nil {
+			return fmt.Errorf("mvhd: %s", err)
+		}
+		if len(mv.mvhd) < int(mv.mvhdBox.hsize)+20 {
+			return errors.New("mvhd: truncated")
+		}
+	}
+	if mv.udta = findBox(mv.children, "udta"); mv.udta != nil {
+		if err = c.readUDTA(&mv); err != nil {
+			return fmt.Errorf("udta: %s", err)
+		}
+	}
+	if mv.meta = findBox(mv.children, "meta"); mv.meta != nil {
+		if err = c.readMovieMeta(&mv); err != nil {
+			return fmt.Errorf("moov/meta: %s", err)
+		}
+	}
+	c.movie = &mv
+	return nil
+}
+
+// readUDTA reads the list of children of the moov/udta box.  Older QuickTime
+// files may end the list with a 32-bit zero terminator, which we ignore (and
+// drop if we rewrite the box).
+func (c *ISOBMFF) readUDTA(mv *movie) (err error) {
+	var (
+		start = mv.udta.offset + mv.udta.hsize
+		end   = mv.udta.offset + mv.udta.size
+	)
+	for end-start >= 8 {
+		var child *box
+
+		if child, err = readBox(c.r, start, end); err != nil {
+			return err
+		}
+		mv.udtaItems = append(mv.udtaItems, &udtaItem{btype: child.btype, b: child})
+		start += child.size
+	}
+	return nil
+}
+
+// readMovieMeta reads the keys and values from the moov/meta box.  This box is
+// a QuickTime metadata box, which (unlike its ISO counterpart) is not a full
+// box; but some writers give it version and flags anyway.  We tell the two
+// apart by where the hdlr box is.
+func (c *ISOBMFF) readMovieMeta(mv *movie) (err error) {
+	var (
+		buf   [4]byte
+		start = mv.meta.offset + mv.meta.hsize
+	)
+	if _, err = c.r.ReadAt(buf[:], start+4); err != nil {
+		return err
+	}
+	if string(buf[:]) != "hdlr" {
+		mv.metaVFlags = make([]byte, 4)
+		c.r.ReadAt(mv.metaVFlags, start)
+		start += 4
+	}
+	if mv.metaChildren, err = readBoxes(c.r, start, mv.meta.offset+mv.meta.size); err != nil {
+		return err
+	}
+	if mv.keysBox = findBox(mv.metaChildren, "keys"); mv.keysBox != nil {
+		if err = c.readKeys(mv); err != nil {
+			return err
+		}
+	}
+	if mv.ilstBox = findBox(mv.metaChildren, "ilst"); mv.ilstBox != nil {
+		if err = c.readILST(mv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readKeys reads the moov/meta/keys box.
+func (c *ISOBMFF) readKeys(mv *movie) (err error) {
+	var (
+		data  []byte
+		p     parser
+		count uint64
+	)
+	if data, err = mv.keysBox.data(c.r); err != nil {
+		return err
+	}
+	p.by = data
+	p.uint(4) // version and flags
+	count = p.uint(4)
+	for i := uint64(0); i < count && p.err == nil; i++ {
+		var size = p.uint(4)
+		var ns = p.fourcc()
+
+		if size < 8 || uint64(len(p.by)) < size-8 {
+			return errors.New("keys: invalid key size")
+		}
+		mv.keyNS = append(mv.keyNS, ns)
+		mv.keys = append(mv.keys, string(p.by[:size-8]))
+		p.by = p.by[size-8:]
+	}
+	if p.err != nil {
+		return fmt.Errorf("keys: %s", p.err)
+	}
+	return nil
+}
+
+// readILST reads the moov/meta/ilst box.
+func (c *ISOBMFF) readILST(mv *movie) (err error) {
+	var children []*box
+
+	if children, err = readBoxes(c.r, mv.ilstBox.offset+mv.ilstBox.hsize, mv.ilstBox.offset+mv.ilstBox.size); err != nil {
+		return fmt.Errorf("ilst: %s", err)
+	}
+	for _, child := range children {
+		var (
+			li     = ilstItem{index: binary.BigEndian.Uint32([]byte(child.btype)), b: child}
+			dboxes []*box
+			dbox   *box
+			data   []byte
+		)
+		if dboxes, err = readBoxes(c.r, child.offset+child.hsize, child.offset+child.size); err != nil {
+			return fmt.Errorf("ilst: %s", err)
+		}
+		if dbox = findBox(dboxes, "data"); dbox == nil {
+			continue
+		}
+		if data, err = dbox.data(c.r); err != nil {
+			return err
+		}
+		if len(data) < 8 {
+			return errors.New("ilst: truncated data box")
+		}
+		li.dtype = binary.BigEndian.Uint32(data[0:4]) & 0xFFFFFF
+		li.value = data[8:]
+		mv.ilst = append(mv.ilst, &li)
+	}
+	return nil
+}
+
+// readXMPBox finds and reads the top-level uuid box containing XMP, if any.
+func (c *ISOBMFF) readXMPBox() (err error) {
+	var uuid [16]byte
+
+	for _, b := range c.boxes {
+		if b.btype != "uuid" {
+			continue
+		}
+		if _, err = c.r.ReadAt(uuid[:], b.offset+b.hsize-16); err != nil {
+			return err
+		}
+		if bytes.Equal(uuid[:], xmpUUID) {
+			c.xmpBox = b
+			c.xmpBoxReader = io.NewSectionReader(c.r, b.offset+b.hsize, b.size-b.hsize)
+			return nil
+		}
+	}
+	return nil
+}
+
+// CreationTime returns the creation time from the movie header box.  It
+// returns a zero time if there is no movie header or it has no creation time.
+func (c *ISOBMFF) CreationTime() time.Time {
+	var secs uint64
+
+	if c.movie == nil || c.movie.mvhd == nil {
+		return time.Time{}
+	}
+	data := c.movie.mvhd[c.movie.mvhdBox.hsize:]
+	if data[0] == 1 {
+		secs = binary.BigEndian.Uint64(data[4:12])
+	} else {
+		secs = uint64(binary.BigEndian.Uint32(data[4:8]))
+	}
+	if secs == 0 {
+		return time.Time{}
+	}
+	return epoch1904.Add(time.Duration(secs) * time.Second)
+}
+
+// SetCreationTime sets the creation time in the movie header box.  A zero time
+// clears it.  It has no effect if the file has no movie header.
+func (c *ISOBMFF) SetCreationTime(t time.Time) {
+	var secs uint64
+
+	if c.movie == nil || c.movie.mvhd == nil || t.Equal(c.CreationTime()) {
+		return
+	}
+	if !t.IsZero() && t.After(epoch1904) {
+		secs = uint64(t.Sub(epoch1904) / time.Second)
+	}
+	data := c.movie.mvhd[c.movie.mvhdBox.hsize:]
+	if data[0] == 1 {
+		binary.BigEndian.PutUint64(data[4:12], secs)
+	} else {
+		binary.BigEndian.PutUint32(data[4:8], uint32(secs))
+	}
+	c.movie.dirty = true
+}
+
+// UserDataText returns the text of the specified QuickTime user data text item
+// (e.g. "\xA9nam") in the moov/udta box.  It returns an empty string if there
+// is no such item.  If the item has text in multiple languages, only the first
+// is returned.
+func (c *ISOBMFF) UserDataText(btype string) string {
+	var data []byte
+
+	if c.movie == nil {
+		return ""
+	}
+	for _, ui := range c.movie.udtaItems {
+		if ui.btype != btype {
+			continue
+		}
+		if ui.data != nil {
+			data = ui.data
+		} else {
+			data, _ = ui.b.data(c.r)
+		}
+		if len(data) < 4 {
+			return ""
+		}
+		size := int(binary.BigEndian.Uint16(data[0:2]))
+		if size > len(data)-4 {
+			size = len(data) - 4
+		}
+		return string(data[4 : 4+size])
+	}
+	return ""
+}
+
+// SetUserDataText sets the text of the specified QuickTime user data text item
+// in the moov/udta box.  An empty string removes the item.  It has no effect
+// if the file has no moov box.
+func (c *ISOBMFF) SetUserDataText(btype, value string) {
+	var mv = c.movie
+
+	if mv == nil || value == c.UserDataText(btype) {
+		return
+	}
+	mv.dirty = true
+	for i, ui := range mv.udtaItems {
+		if ui.btype != btype {
+			continue
+		}
+		if value == "" {
+			mv.udtaItems = append(mv.udtaItems[:i], mv.udtaItems[i+1:]...)
+			return
+		}
+		ui.b, ui.data = nil, makeUserDataText(value)
+		return
+	}
+	mv.udtaItems = append(mv.udtaItems, &udtaItem{btype: btype, data: makeUserDataText(value)})
+}
+
+// makeUserDataText returns the contents of a QuickTime user data text item.
+func makeUserDataText(value string) []byte {
+	var buf = make([]byte, 4, 4+len(value))
+
+	binary.BigEndian.PutUint16(buf[0:2], uint16(len(value)))
+	binary.BigEndian.PutUint16(buf[2:4], languageUndetermined)
+	return append(buf, value...)
+}
+
+// Key returns the UTF-8 string value of the specified key (in the mdta
+// namespace) in the moov/meta box.  It returns an empty string if there is no
+// such key, or if its value is not a UTF-8 string.
+func (c *ISOBMFF) Key(name string) string {
+	if li := c.keyItem(name); li != nil && li.dtype == 1 {
+		return string(li.value)
+	}
+	return ""
+}
+
+// keyItem returns the ilst item for the specified key, or nil if there is none.
+func (c *ISOBMFF) keyItem(name string) *ilstItem {
+	if c.movie == nil {
+		return nil
+	}
+	idx := c.movie.keyIndex(name)
+	for _, li := range c.movie.ilst {
+		if idx != 0 && li.index == idx {
+			return li
+		}
+	}
+	return nil
+}
+
+// keyIndex returns the 1-based index of the specified key, or zero if it is not
+// in the keys list.
+func (mv *movie) keyIndex(name string) uint32 {
+	for i, key := range mv.keys {
+		if key == name && mv.keyNS[i] == "mdta" {
+			return uint32(i + 1)
+		}
+	}
+	return 0
+}
+
+// SetKey sets the value of the specified key (in the mdta namespace) in the
+// moov/meta box to the specified UTF-8 string.  An empty string removes the
+// value.  It has no effect if the file has no moov box.
+func (c *ISOBMFF) SetKey(name, value string) {
+	var (
+		mv = c.movie
+		li = c.keyItem(name)
+	)
+	if mv == nil || (li == nil && value == "") || (li != nil && li.dtype == 1 && string(li.value) == value) {
+		return
+	}
+	mv.dirty = true
+	if value == "" {
+		for i := range mv.ilst {
+			if mv.ilst[i] == li {
+				mv.ilst = append(mv.ilst[:i], mv.ilst[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+	if li == nil {
+		var idx = mv.keyIndex(name)
+
+		if idx == 0 {
+			mv.keys = append(mv.keys, name)
+			mv.keyNS = append(mv.keyNS, "mdta")
+			mv.keysDirty = true
+			idx = uint32(len(mv.keys))
+		}
+		li = &ilstItem{index: idx}
+		mv.ilst = append(mv.ilst, li)
+	}
+	li.b, li.dtype, li.value = nil, 1, []byte(value)
+}
+
+// renderMovie renders the moov box, returning it as a list of pieces.
+func (c *ISOBMFF) renderMovie() (pieces []piece) {
+	var mv = c.movie
+
+	pieces = append(pieces, piece{}) // header filled in below
+	for _, child := range mv.children {
+		switch child {
+		case mv.mvhdBox:
+			pieces = append(pieces, piece{data: mv.mvhd})
+		case mv.udta:
+			pieces = append(pieces, c.renderUDTA()...)
+		case mv.meta:
+			pieces = append(pieces, c.renderMovieMeta()...)
+		default:
+			pieces = append(pieces, piece{from: child.offset, to: child.offset + child.size})
+		}
+	}
+	if mv.udta == nil && len(mv.udtaItems) != 0 {
+		pieces = append(pieces, c.renderUDTA()...)
+	}
+	if mv.meta == nil && len(mv.ilst) != 0 {
+		pieces = append(pieces, c.renderMovieMeta()...)
+	}
+	pieces[0].data = boxHeader("moov", piecesSize(pieces))
+	return pieces
+}
+
+// renderUDTA renders the moov/udta box, returning it as a list of pieces.
+func (c *ISOBMFF) renderUDTA() (pieces []piece) {
+	pieces = append(pieces, piece{}) // header filled in below
+	for _, ui := range c.movie.udtaItems {
+		if ui.b != nil {
+			pieces = append(pieces, piece{from: ui.b.offset, to: ui.b.offset + ui.b.size})
+		} else {
+			pieces = append(pieces, piece{data: makeBox(ui.btype, ui.data)})
+		}
+	}
+	pieces[0].data = boxHeader("udta", piecesSize(pieces))
+	return pieces
+}
+
+// renderMovieMeta renders the moov/meta box, returning it as a list of pieces.
+func (c *ISOBMFF) renderMovieMeta() (pieces []piece) {
+	var mv = c.movie
+
+	pieces = append(pieces, piece{}) // header filled in below
+	if mv.metaVFlags != nil {
+		pieces = append(pieces, piece{data: mv.metaVFlags})
+	}
+	if mv.meta == nil {
+		var hdlr = make([]byte, 8, 25) // version, flags, and pre_defined
+
+		hdlr = append(hdlr, "mdta"...)
+		hdlr = append(hdlr, make([]byte, 13)...) // reserved, and empty name
+		pieces = append(pieces, piece{data: makeBox("hdlr", hdlr)})
+	}
+	for _, child := range mv.metaChildren {
+		switch child {
+		case mv.keysBox:
+			pieces = append(pieces, c.renderKeys())
+		case mv.ilstBox:
+			pieces = append(pieces, c.renderILST()...)
+		default:
+			pieces = append(pieces, piece{from: child.offset, to: child.offset + child.size})
+		}
+	}
+	if mv.keysBox == nil {
+		pieces = append(pieces, c.renderKeys())
+	}
+	if mv.ilstBox == nil {
+		pieces = append(pieces, c.renderILST()...)
+	}
+	pieces[0].data = boxHeader("meta", piecesSize(pieces))
+	return pieces
+}
+
+// renderKeys renders the moov/meta/keys box.
+func (c *ISOBMFF) renderKeys() piece {
+	var (
+		mv  = c.movie
+		buf bytes.Buffer
+	)
+	if !mv.keysDirty && mv.keysBox != nil {
+		return piece{from: mv.keysBox.offset, to: mv.keysBox.offset + mv.keysBox.size}
+	}
+	putUint(&buf, 0, 4) // version and flags
+	putUint(&buf, uint64(len(mv.keys)), 4)
+	for i, key := range mv.keys {
+		putUint(&buf, uint64(len(key)+8), 4)
+		buf.WriteString(mv.keyNS[i])
+		buf.WriteString(key)
+	}
+	return piece{data: makeBox("keys", buf.Bytes())}
+}
+
+// renderILST renders the moov/meta/ilst box, returning it as a list of pieces.
+func (c *ISOBMFF) renderILST() (pieces []piece) {
+	pieces = append(pieces, piece{}) // header filled in below
+	for _, li := range c.movie.ilst {
+		if li.b != nil {
+			pieces = append(pieces, piece{from: li.b.offset, to: li.b.offset + li.b.size})
+			continue
+		}
+		var (
+			data  = make([]byte, 8, 8+len(li.value))
+			index [4]byte
+		)
+		binary.BigEndian.PutUint32(data[0:4], li.dtype)
+		data = append(data, li.value...)
+		binary.BigEndian.PutUint32(index[:], li.index)
+		pieces = append(pieces, piece{data: makeBox(string(index[:]), makeBox("data", data))})
+	}
+	pieces[0].data = boxHeader("ilst", piecesSize(pieces))
+	return pieces
+}
+
+// renderXMPBox renders the top-level uuid box containing XMP, returning it as
+// a list of pieces.
+func (c *ISOBMFF) renderXMPBox() (pieces []piece) {
+	size := c.xmpBoxContainer.Layout()
+	header := boxHeader("uuid", size+16)
+	return []piece{
+		{data: append(header[:len(header):len(header)], xmpUUID...)},
+		{container: c.xmpBoxContainer, csize: size},
+	}
+}
+
+// HasMovie returns whether the file has a movie (i.e., a top-level moov box).
+func (c *ISOBMFF) HasMovie() bool { return c.movie != nil }
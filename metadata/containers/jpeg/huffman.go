@@ -0,0 +1,256 @@
+package jpeg
+
+import (
+	"errors"
+)
+
+// A huffTable is a Huffman coding table, as found in a DHT segment.
+type huffTable struct {
+	counts [16]byte // number of codes of each length, 1 through 16
+	values []byte   // symbols, in order of increasing code length
+	// Decoding tables, as in section F.2.2.3 of the JPEG specification.
+	maxcode [17]int32
+	mincode [17]int32
+	valptr  [17]int32
+	// Encoding tables, as in section C.2 of the JPEG specification.
+	ehufco [256]uint16
+	ehufsi [256]byte
+}
+
+// build computes the decoding and encoding tables for the Huffman table.
+func (ht *huffTable) build() error {
+	var code, k int32
+
+	for l := 1; l <= 16; l++ {
+		count := int32(ht.counts[l-1])
+		ht.valptr[l] = k
+		ht.mincode[l] = code
+		if count == 0 {
+			ht.maxcode[l] = -1
+		} else {
+			if k+count > int32(len(ht.values)) {
+				return errors.New("invalid Huffman table")
+			}
+			for i := k; i < k+count; i++ {
+				ht.ehufco[ht.values[i]] = uint16(code + i - k)
+				ht.ehufsi[ht.values[i]] = byte(l)
+			}
+			ht.maxcode[l] = code + count - 1
+		}
+		code = (code + count) << 1
+		k += count
+	}
+	return nil
+}
+
+// optimalHuffTable returns an optimal Huffman table for the specified symbol
+// frequencies, using the procedure in section K.2 of the JPEG specification
+// (as refined by libjpeg).  freqs must have at least one nonzero entry.
+func optimalHuffTable(freqs *[256]int64) (ht *huffTable) {
+	var (
+		freq     [257]int64
+		codesize [257]int
+		others   [257]int
+		bits     [33]int
+	)
+	copy(freq[:], freqs[:])
+	// Code point 256 is reserved so that no code consists entirely of one
+	// bits.
+	freq[256] = 1
+	for i := range others {
+		others[i] = -1
+	}
+	for {
+		// Find the two smallest nonzero frequencies, preferring the
+		// higher-numbered symbol in case of ties.
+		c1, c2 := -1, -1
+		var v1, v2 int64
+		for i := 0; i <= 256; i++ {
+			if freq[i] != 0 && (c1 < 0 || freq[i] <= v1) {
+				c1, v1 = i, freq[i]
+			}
+		}
+		for i := 0; i <= 256; i++ {
+			if freq[i] != 0 && i != c1 && (c2 < 0 || freq[i] <= v2) {
+				c2, v2 = i, freq[i]
+			}
+		}
+		if c2 < 0 {
+			break
+		}
+		freq[c1] += freq[c2]
+		freq[c2] = 0
+		codesize[c1]++
+		for others[c1] >= 0 {
+			c1 = others[c1]
+			codesize[c1]++
+		}
+		others[c1] = c2
+		codesize[c2]++
+		for others[c2] >= 0 {
+			c2 = others[c2]
+			codesize[c2]++
+		}
+	}
+	for i := 0; i <= 256; i++ {
+		if codesize[i] != 0 {
+			bits[codesize[i]]++
+		}
+	}
+	// Limit the code lengths to 16 bits.
+	for i := 32; i > 16; i-- {
+		for bits[i] > 0 {
+			j := i - 2
+			for bits[j] == 0 {
+				j--
+			}
+			bits[i] -= 2
+			bits[i-1]++
+			bits[j+1] += 2
+			bits[j]--
+		}
+	}
+	// Remove the reserved code point from the longest code length.
+	i := 16
+	for bits[i] == 0 {
+		i--
+	}
+	bits[i]--
+	ht = new(huffTable)
+	for l := 1; l <= 16; l++ {
+		ht.counts[l-1] = byte(bits[l])
+	}
+	for size := 1; size <= 32; size++ {
+		for sym := 0; sym < 256; sym++ {
+			if codesize[sym] == size {
+				ht.values = append(ht.values, byte(sym))
+			}
+		}
+	}
+	ht.build()
+	return ht
+}
+
+// A bitReader reads bits from entropy-coded data, removing stuffed bytes.
+type bitReader struct {
+	data   []byte
+	pos    int
+	acc    uint32
+	n      uint
+	marker bool // true if we've reached a marker
+}
+
+// fill ensures that there are at least 25 bits in the accumulator.  If a
+// marker is reached, zero bits are supplied in place of further data.
+func (br *bitReader) fill() {
+	for br.n <= 24 {
+		var b byte
+		if !br.marker && br.pos < len(br.data) {
+			b = br.data[br.pos]
+			if b != 0xFF {
+				br.pos++
+			} else if br.pos+1 < len(br.data) && br.data[br.pos+1] == 0 {
+				br.pos += 2
+			} else {
+				br.marker, b = true, 0
+			}
+		}
+		br.acc = br.acc<<8 | uint32(b)
+		br.n += 8
+	}
+}
+
+// bits returns the next n bits of the data.
+func (br *bitReader) bits(n uint) int32 {
+	if n == 0 {
+		return 0
+	}
+	br.fill()
+	br.n -= n
+	return int32(br.acc>>br.n) & (1<<n - 1)
+}
+
+// decode decodes and returns the next Huffman-coded symbol in the data.
+func (br *bitReader) decode(ht *huffTable) (byte, error) {
+	var code int32
+
+	for l := 1; l <= 16; l++ {
+		code = code<<1 | br.bits(1)
+		if code <= ht.maxcode[l] {
+			return ht.values[ht.valptr[l]+code-ht.mincode[l]], nil
+		}
+	}
+	return 0, errors.New("invalid Huffman code")
+}
+
+// receive reads an n-bit value from the data and extends it to a signed
+// coefficient value, as in section F.2.2.1 of the JPEG specification.
+func (br *bitReader) receive(n uint) int32 {
+	v := br.bits(n)
+	if n != 0 && v < 1<<(n-1) {
+		v += -1<<n + 1
+	}
+	return v
+}
+
+// nextMarker discards any remaining bits and skips to the next marker in
+// the data.  It returns the marker code, or zero if the data are exhausted.
+func (br *bitReader) nextMarker() byte {
+	br.acc, br.n, br.marker = 0, 0, false
+	for br.pos+1 < len(br.data) {
+		if br.data[br.pos] == 0xFF && br.data[br.pos+1] != 0 && br.data[br.pos+1] != 0xFF {
+			br.pos += 2
+			return br.data[br.pos-1]
+		}
+		br.pos++
+	}
+	br.pos = len(br.data)
+	return 0
+}
+
+// A bitWriter writes bits of entropy-coded data, stuffing bytes as needed.
+type bitWriter struct {
+	buf []byte
+	acc uint64
+	n   uint
+}
+
+// bits writes the low n bits of v.
+func (bw *bitWriter) bits(v uint32, n uint) {
+	bw.acc = bw.acc<<n | uint64(v)&(1<<n-1)
+	bw.n += n
+	for bw.n >= 8 {
+		b := byte(bw.acc >> (bw.n - 8))
+		bw.buf = append(bw.buf, b)
+		if b == 0xFF {
+			bw.buf = append(bw.buf, 0)
+		}
+		bw.n -= 8
+	}
+	bw.acc &= 1<<bw.n - 1
+}
+
+// flush pads the data to a byte boundary with one bits.
+func (bw *bitWriter) flush() {
+	if pad := (8 - bw.n%8) % 8; pad != 0 {
+		bw.bits(1<<pad-1, pad)
+	}
+}
+
+// marker writes a marker to the data.  The data must be flushed first.
+func (bw *bitWriter) marker(m byte) { bw.buf = append(bw.buf, 0xFF, m) }
+
+// category returns the size category of a coefficient value, and the bits
+// that encode it within that category, as in section F.1.2.1 of the JPEG
+// specification.
+func category(v int32) (size uint, bits uint32) {
+	a := v
+	if v < 0 {
+		a, v = -v, v-1
+	}
+	for a != 0 {
+		size++
+		a >>= 1
+	}
+	return size, uint32(v) & (1<<size - 1)
+}
@@ -31,16 +31,26 @@ var (
 
 // A JPEG is a container of Segments.
 type JPEG struct {
-	start *segmentGroup
-	jfif  []*segmentGroup // multiple namespaces, so can't be one group
-	exif  *segmentGroup
-	xmp   *segmentGroup
-	// xmpext *segmentGroup
+	start  *segmentGroup
+	jfif   []*segmentGroup // multiple namespaces, so can't be one group
+	exif   *segmentGroup
+	xmp    *segmentGroup
+	xmpext *xmpExtension
 	psir   *segmentGroup
 	others []*segmentGroup
 	end    *segmentGroup
-	all    []*segmentGroup
+	all    []part
 	size   int64
+
+	transformed bool
+}
+
+// A part is a portion of the rendered JPEG file: either a segment group or the
+// set of extended XMP segments.
+type part interface {
+	Empty() bool
+	Layout() int64
+	Write(io.Writer) (int, error)
 }
 
 var _ containers.Container = (*JPEG)(nil) // verify interface compliance
@@ -75,8 +85,11 @@ func (jpeg *JPEG) Read(r metadata.Reader) (err error) {
 			jpeg.exif = jpeg.exif.merge(seg, 0)
 		case bytes.Equal(seg.namespace, nsXMP):
 			jpeg.xmp = jpeg.xmp.merge(seg, 0)
-		// case bytes.Equal(seg.namespace, nsXMPext):
-		// 	jpeg.xmpext = jpeg.xmpext.merge(seg, 40)
+		case bytes.Equal(seg.namespace, nsXMPext):
+			if jpeg.xmpext == nil {
+				jpeg.xmpext = new(xmpExtension)
+			}
+			jpeg.xmpext.segments = append(jpeg.xmpext.segments, seg)
 		case bytes.Equal(seg.namespace, nsPSIR):
 			jpeg.psir = jpeg.psir.merge(seg, 0)
 		}
@@ -89,15 +102,16 @@ func (jpeg *JPEG) Read(r metadata.Reader) (err error) {
 // to it).
 func (jpeg *JPEG) Empty() bool { return false } // JPEGs are never empty
 
-// Dirty returns whether any of the JPEG segments have been changed.
+// Dirty returns whether any of the JPEG segments have been changed, or the
+// image data have been transformed.
 func (jpeg *JPEG) Dirty() bool {
-	return jpeg.exif.Dirty() || jpeg.xmp.Dirty() || jpeg.psir.Dirty()
+	return jpeg.transformed || jpeg.exif.Dirty() || jpeg.xmp.Dirty() || jpeg.xmpext.Dirty() || jpeg.psir.Dirty()
 }
 
 // Layout computes the rendered layout of the container, i.e. prepares for a
 // call to Write, and returns what the rendered size of the container will be.
 func (jpeg *JPEG) Layout() int64 {
-	jpeg.all = []*segmentGroup{jpeg.start}
+	jpeg.all = []part{jpeg.start}
 	for _, seg := range jpeg.jfif {
 		if !seg.Empty() {
 			jpeg.all = append(jpeg.all, seg)
@@ -109,9 +123,9 @@ func (jpeg *JPEG) Layout() int64 {
 	if !jpeg.xmp.Empty() {
 		jpeg.all = append(jpeg.all, jpeg.xmp)
 	}
-	// if !jpeg.xmpext.Empty() {
-	// 	jpeg.all = append(jpeg.all, jpeg.xmpext)
-	// }
+	if !jpeg.xmpext.Empty() {
+		jpeg.all = append(jpeg.all, jpeg.xmpext)
+	}
 	if !jpeg.psir.Empty() {
 		jpeg.all = append(jpeg.all, jpeg.psir)
 	}
@@ -179,13 +193,12 @@ func (jpeg *JPEG) XMP() metadata.Reader {
 	return jpeg.xmp.reader
 }
 
-// XMPext returns the contents of the XMP extension segment, if any.
-// func (jpeg *JPEG) XMPext() metadata.Reader {
-// 	if jpeg.xmpext != nil {
-// 		return jpeg.xmpext.reader
-// 	}
-// 	return nil
-// }
+// XMPext returns the contents of the extended XMP packet with the specified
+// GUID, reassembled from the extended XMP segments that carry it.  It returns
+// nil if there is no such packet or if it is incomplete.
+func (jpeg *JPEG) XMPext(guid string) metadata.Reader {
+	return jpeg.xmpext.packet(guid)
+}
 
 // PSIR returns the contents of the PSIR segment, if any.
 func (jpeg *JPEG) PSIR() metadata.Reader {
@@ -219,6 +232,19 @@ func (jpeg *JPEG) SetXMPContainer(c containers.Container) {
 	jpeg.xmp.container = c
 }
 
+// SetXMPextContainer sets the contents of the extended XMP segments to those
+// provided by the supplied container, which will be identified by the
+// specified GUID.  Any extended XMP segments read from the file are discarded.
+// If the container is nil, the file will have no extended XMP segments.
+func (jpeg *JPEG) SetXMPextContainer(guid string, c containers.Container) {
+	if jpeg.xmpext == nil {
+		jpeg.xmpext = new(xmpExtension)
+	}
+	jpeg.xmpext.segments = nil
+	jpeg.xmpext.guid = guid
+	jpeg.xmpext.container = c
+}
+
 // SetPSIRContainer sets the contents of the PSIR segment to those provided by the
 // supplied container.
 func (jpeg *JPEG) SetPSIRContainer(c containers.Container) {
@@ -230,3 +256,20 @@ func (jpeg *JPEG) SetPSIRContainer(c containers.Container) {
 	}
 	jpeg.psir.container = c
 }
+
+// ImageData returns a minimal JPEG file containing the image in the container,
+// without any of its metadata segments.  It is suitable for decoding the image.
+func (jpeg *JPEG) ImageData() (r metadata.Reader, err error) {
+	var buf bytes.Buffer
+
+	for _, seg := range append(append([]*segmentGroup{jpeg.start}, jpeg.others...), jpeg.end) {
+		if seg.Empty() {
+			continue
+		}
+		seg.Layout()
+		if _, err = seg.Write(&buf); err != nil {
+			return nil, err
+		}
+	}
+	return bytes.NewReader(buf.Bytes()), nil
+}
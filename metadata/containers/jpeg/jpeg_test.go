@@ -0,0 +1,126 @@
+package jpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/raw"
+)
+
+const testGUID = "0123456789ABCDEF0123456789ABCDEF"
+
+// makeSegment returns a JPEG segment with the specified marker and contents.
+func makeSegment(marker byte, data ...[]byte) []byte {
+	var body = bytes.Join(data, nil)
+	var seg = []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:4], uint16(len(body)+2))
+	return append(seg, body...)
+}
+
+// makeXMPext returns an extended XMP segment carrying the specified portion
+// of an extended XMP packet.
+func makeXMPext(guid string, length, offset int, data string) []byte {
+	var hdr [8]byte
+	binary.BigEndian.PutUint32(hdr[0:4], uint32(length))
+	binary.BigEndian.PutUint32(hdr[4:8], uint32(offset))
+	return makeSegment(markerXMPext, nsXMPext, []byte(guid), hdr[:], []byte(data))
+}
+
+// makeTestFile returns a minimal JPEG file with the specified segments.
+func makeTestFile(segments ...[]byte) []byte {
+	var file = []byte{0xFF, 0xD8}
+	file = append(file, bytes.Join(segments, nil)...)
+	return append(file, 0xFF, 0xDA, 'S', 'C', 'A', 'N', 0xFF, 0xD9)
+}
+
+func readTestFile(t *testing.T, file []byte) *JPEG {
+	var jpeg JPEG
+	if err := jpeg.Read(bytes.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+	return &jpeg
+}
+
+func TestXMPextRead(t *testing.T) {
+	// Segments out of order, plus one for a different packet.
+	jpeg := readTestFile(t, makeTestFile(
+		makeSegment(markerXMP, nsXMP, []byte("<x/>")),
+		makeXMPext(testGUID, 10, 5, "FGHIJ"),
+		makeXMPext("FEDCBA9876543210FEDCBA9876543210", 3, 0, "XYZ"),
+		makeXMPext(testGUID, 10, 0, "ABCDE"),
+	))
+	if by, _ := io.ReadAll(jpeg.XMPext(testGUID)); string(by) != "ABCDEFGHIJ" {
+		t.Errorf("XMPext: got %q", by)
+	}
+	if r := jpeg.XMPext("00000000000000000000000000000000"); r != nil {
+		t.Error("XMPext with unknown GUID: got data, expected nil")
+	}
+}
+
+func TestXMPextIncomplete(t *testing.T) {
+	jpeg := readTestFile(t, makeTestFile(makeXMPext(testGUID, 10, 0, "ABCDE")))
+	if r := jpeg.XMPext(testGUID); r != nil {
+		t.Error("XMPext: got data, expected nil")
+	}
+}
+
+func TestXMPextUnchanged(t *testing.T) {
+	var buf bytes.Buffer
+
+	file := makeTestFile(
+		makeSegment(markerXMP, nsXMP, []byte("<x/>")),
+		makeXMPext(testGUID, 10, 0, "ABCDE"),
+		makeXMPext(testGUID, 10, 5, "FGHIJ"),
+	)
+	jpeg := readTestFile(t, file)
+	jpeg.Layout()
+	if _, err := jpeg.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), file) {
+		t.Error("output differs from input")
+	}
+}
+
+func TestXMPextWrite(t *testing.T) {
+	var (
+		buf  bytes.Buffer
+		r    raw.Raw
+		data = bytes.Repeat([]byte("0123456789"), 10000)
+	)
+	jpeg := readTestFile(t, makeTestFile(makeXMPext(testGUID, 10, 0, "ABCDEFGHIJ")))
+	r.SetData(data)
+	jpeg.SetXMPextContainer("FEDCBA9876543210FEDCBA9876543210", &r)
+	jpeg.Layout()
+	if _, err := jpeg.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	jpeg = readTestFile(t, buf.Bytes())
+	if got := len(jpeg.xmpext.segments); got != 2 {
+		t.Errorf("got %d segments, expected 2", got)
+	}
+	if r := jpeg.XMPext(testGUID); r != nil {
+		t.Error("old XMPext: got data, expected nil")
+	}
+	if by, _ := io.ReadAll(jpeg.XMPext("FEDCBA9876543210FEDCBA9876543210")); !bytes.Equal(by, data) {
+		t.Errorf("XMPext: got %d bytes", len(by))
+	}
+}
+
+func TestImageData(t *testing.T) {
+	jpeg := readTestFile(t, makeTestFile(
+		makeSegment(markerEXIF, nsEXIF, []byte("EXIF")),
+		makeSegment(markerXMP, nsXMP, []byte("<x/>")),
+		makeSegment(0xDB, []byte("DQT")),
+	))
+	r, err := jpeg.ImageData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := makeTestFile(makeSegment(0xDB, []byte("DQT")))
+	if by, _ := io.ReadAll(io.NewSectionReader(r, 0, r.Size())); !bytes.Equal(by, expected) {
+		t.Errorf("ImageData: got % x, expected % x", by, expected)
+	}
+}
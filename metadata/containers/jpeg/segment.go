@@ -74,10 +74,8 @@ func (seg *segmentGroup) Read(r metadata.Reader) (err error) {
 		seg.namespace = nsEXIF
 	case seg.marker == markerXMP && count > len(nsXMP) && bytes.Equal(buf[:len(nsXMP)], nsXMP):
 		seg.namespace = nsXMP
-	// Disabling the recognition of XMPext namespaces until we can better
-	// implement handling multi-segment XMPext namespaces.
-	// case seg.marker == markerXMPext && count > len(nsXMPext) && bytes.Equal(buf[:len(nsXMPext)], nsXMPext):
-	// 	seg.namespace = nsXMPext
+	case seg.marker == markerXMPext && count > len(nsXMPext) && bytes.Equal(buf[:len(nsXMPext)], nsXMPext):
+		seg.namespace = nsXMPext
 	case seg.marker == markerPSIR && count > len(nsPSIR) && bytes.Equal(buf[:len(nsPSIR)], nsPSIR):
 		seg.namespace = nsPSIR
 	}
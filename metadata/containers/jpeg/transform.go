@@ -0,0 +1,647 @@
+package jpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// zigzag maps the zigzag order of DCT coefficients, in which they appear in
+// the file, to their natural (row-major) order.
+var zigzag = [64]int{
+	0, 1, 8, 16, 9, 2, 3, 10,
+	17, 24, 32, 25, 18, 11, 4, 5,
+	12, 19, 26, 33, 40, 48, 41, 34,
+	27, 20, 13, 6, 7, 14, 21, 28,
+	35, 42, 49, 56, 57, 50, 43, 36,
+	29, 22, 15, 23, 30, 37, 44, 51,
+	58, 59, 52, 45, 38, 31, 39, 46,
+	53, 60, 61, 54, 47, 55, 62, 63,
+}
+
+// A frame is the decoded image data of a JPEG file:  the DCT coefficients of
+// each of its components, along with the parameters needed to encode them.
+type frame struct {
+	marker    byte // SOFn marker
+	precision byte
+	width     int
+	height    int
+	comps     []*component
+	qtables   [4]*qtable
+	htables   [2][4]*huffTable
+	restart   int
+	scans     [][]int // indices into comps of the components in each scan
+	hmax      int
+	vmax      int
+	mcusX     int
+	mcusY     int
+	trailer   []byte // data following the EOI marker
+}
+
+// A qtable is a quantization table, in natural order.
+type qtable struct {
+	precision byte
+	values    [64]uint16
+}
+
+// A component is one component of a JPEG image.
+type component struct {
+	id     byte
+	h      int
+	v      int
+	tq     byte
+	td     byte
+	ta     byte
+	bw     int // width in blocks of the allocated coefficient storage
+	bh     int // height in blocks of the allocated coefficient storage
+	blocks [][64]int32
+}
+
+// Transform losslessly transforms the image data of the JPEG file, by
+// manipulating its DCT coefficients, so that the image looks the way it
+// would have been displayed with the specified orientation.  Partial blocks
+// at edges that the transformation would move to the top or left of the image
+// are trimmed.  It returns the dimensions of the image before and after the
+// transformation, and the dimensions of the (top left) portion of the original
+// image that was kept.  It supports only sequential Huffman-coded JPEG files.
+func (jpeg *JPEG) Transform(o metadata.Orientation) (orig, kept, result metadata.Dimensions, err error) {
+	var (
+		f         *frame
+		nf        *frame
+		transpose bool
+		flipX     bool
+		flipY     bool
+		others    []*segmentGroup
+		out       []byte
+	)
+	switch o {
+	case 0, metadata.Rotate0:
+	case metadata.FlipX:
+		flipX = true
+	case metadata.FlipXY:
+		flipX, flipY = true, true
+	case metadata.FlipY:
+		flipY = true
+	case metadata.Rotate90FlipX:
+		transpose = true
+	case metadata.Rotate90:
+		transpose, flipX = true, true
+	case metadata.Rotate270FlipX:
+		transpose, flipX, flipY = true, true, true
+	case metadata.Rotate270:
+		transpose, flipY = true, true
+	default:
+		return orig, kept, result, fmt.Errorf("JPEG: invalid orientation %d", o)
+	}
+	if f, others, err = jpeg.readFrame(); err != nil {
+		return orig, kept, result, fmt.Errorf("JPEG: %s", err)
+	}
+	orig = metadata.Dimensions{Width: f.width, Height: f.height}
+	// Work out which source edges end up on the top or left, and trim the
+	// partial blocks from them.
+	reverseX, reverseY := flipX, flipY
+	if transpose {
+		reverseX, reverseY = flipY, flipX
+	}
+	if mcuW := 8 * f.hmax; reverseX && f.width > mcuW {
+		f.width -= f.width % mcuW
+	}
+	if mcuH := 8 * f.vmax; reverseY && f.height > mcuH {
+		f.height -= f.height % mcuH
+	}
+	kept = metadata.Dimensions{Width: f.width, Height: f.height}
+	nf = f.transform(transpose, flipX, flipY)
+	result = metadata.Dimensions{Width: nf.width, Height: nf.height}
+	if out, err = nf.encode(); err != nil {
+		return orig, kept, result, fmt.Errorf("JPEG: %s", err)
+	}
+	// Replace the table segments and the scans with the new ones.
+	jpeg.others = append(others, nf.tableSegments()...)
+	jpeg.end = &segmentGroup{marker: 0xDA, reader: bytes.NewReader(out)}
+	jpeg.transformed = true
+	return orig, kept, result, nil
+}
+
+// readFrame reads and decodes the image data of the JPEG file.  It returns
+// the decoded frame and the list of segments other than those containing
+// tables used in decoding.
+func (jpeg *JPEG) readFrame() (f *frame, others []*segmentGroup, err error) {
+	var (
+		data    []byte
+		htables [2][4]*huffTable
+		sawSOF  bool
+	)
+	f = new(frame)
+	for _, seg := range jpeg.others {
+		switch seg.marker {
+		case 0xC0, 0xC1, 0xDB, 0xC4, 0xDD:
+			if data, err = readSegmentData(seg); err != nil {
+				return nil, nil, err
+			}
+			if err = f.readTable(seg.marker, data, &htables); err != nil {
+				return nil, nil, err
+			}
+			sawSOF = sawSOF || seg.marker == 0xC0 || seg.marker == 0xC1
+		case 0xC2, 0xC3, 0xC5, 0xC6, 0xC7, 0xC9, 0xCA, 0xCB, 0xCD, 0xCE, 0xCF:
+			return nil, nil, errors.New("lossless transformation is supported only for sequential Huffman-coded JPEGs")
+		case 0xCC:
+			return nil, nil, errors.New("lossless transformation is not supported for arithmetic-coded JPEGs")
+		default:
+			others = append(others, seg)
+		}
+	}
+	if !sawSOF {
+		return nil, nil, errors.New("missing SOF segment")
+	}
+	if data, err = readSegmentData(jpeg.end); err != nil {
+		return nil, nil, err
+	}
+	// The data start just after the first SOS marker, and contain the
+	// scans and any interspersed table segments.
+	br := bitReader{data: data}
+	for marker := byte(0xDA); ; {
+		if marker != 0xDA && marker != 0xD9 {
+			// A segment between scans.
+			if br.pos+2 > len(data) {
+				return nil, nil, errors.New("premature end of data")
+			}
+			length := int(binary.BigEndian.Uint16(data[br.pos:]))
+			if length < 2 || br.pos+length > len(data) {
+				return nil, nil, errors.New("invalid segment length")
+			}
+			seg := data[br.pos+2 : br.pos+length]
+			br.pos += length
+			switch marker {
+			case 0xDB, 0xC4, 0xDD:
+				if err = f.readTable(marker, seg, &htables); err != nil {
+					return nil, nil, err
+				}
+			case 0xDC:
+				return nil, nil, errors.New("DNL segments are not supported")
+			}
+			// Other segments (APPn, COM) between scans are dropped.
+		}
+		switch marker {
+		case 0xD9:
+			f.trailer = data[br.pos:]
+			return f, others, nil
+		case 0xDA:
+			if err = f.decodeScan(&br, &htables); err != nil {
+				return nil, nil, err
+			}
+		}
+		if marker = br.nextMarker(); marker == 0 {
+			// Missing EOI marker; assume the end of the data.
+			return f, others, nil
+		}
+	}
+}
+
+// readSegmentData returns the contents of a segment, excluding its marker and
+// length.
+func readSegmentData(seg *segmentGroup) (data []byte, err error) {
+	data = make([]byte, seg.reader.Size())
+	if _, err = seg.reader.ReadAt(data, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readTable parses a segment that defines tables or parameters needed to
+// decode the image data.
+func (f *frame) readTable(marker byte, data []byte, htables *[2][4]*huffTable) error {
+	switch marker {
+	case 0xC0, 0xC1: // SOF0, SOF1
+		if len(data) < 6 || len(data) < 6+3*int(data[5]) || data[5] == 0 {
+			return errors.New("invalid SOF segment")
+		}
+		f.marker, f.precision = marker, data[0]
+		f.height = int(binary.BigEndian.Uint16(data[1:]))
+		f.width = int(binary.BigEndian.Uint16(data[3:]))
+		if f.height == 0 || f.width == 0 {
+			return errors.New("images with undefined height are not supported")
+		}
+		f.hmax, f.vmax = 1, 1
+		for i := 0; i < int(data[5]); i++ {
+			c := &component{id: data[6+3*i], h: int(data[7+3*i] >> 4), v: int(data[7+3*i] & 15), tq: data[8+3*i] & 3}
+			if c.h < 1 || c.h > 4 || c.v < 1 || c.v > 4 {
+				return errors.New("invalid sampling factor")
+			}
+			if data[5] == 1 {
+				// A single component is always coded one block
+				// per MCU, regardless of its sampling factors.
+				c.h, c.v = 1, 1
+			}
+			if c.h > f.hmax {
+				f.hmax = c.h
+			}
+			if c.v > f.vmax {
+				f.vmax = c.v
+			}
+			f.comps = append(f.comps, c)
+		}
+		f.mcusX = (f.width + 8*f.hmax - 1) / (8 * f.hmax)
+		f.mcusY = (f.height + 8*f.vmax - 1) / (8 * f.vmax)
+		for _, c := range f.comps {
+			c.bw, c.bh = f.mcusX*c.h, f.mcusY*c.v
+			c.blocks = make([][64]int32, c.bw*c.bh)
+		}
+	case 0xDB: // DQT
+		for len(data) != 0 {
+			qt := &qtable{precision: data[0] >> 4}
+			size := 65 + 64*int(qt.precision)
+			if qt.precision > 1 || data[0]&15 > 3 || len(data) < size {
+				return errors.New("invalid DQT segment")
+			}
+			for i := 0; i < 64; i++ {
+				if qt.precision == 0 {
+					qt.values[zigzag[i]] = uint16(data[1+i])
+				} else {
+					qt.values[zigzag[i]] = binary.BigEndian.Uint16(data[1+2*i:])
+				}
+			}
+			f.qtables[data[0]&15] = qt
+			data = data[size:]
+		}
+	case 0xC4: // DHT
+		for len(data) != 0 {
+			ht := new(huffTable)
+			if len(data) < 17 || data[0]>>4 > 1 || data[0]&15 > 3 {
+				return errors.New("invalid DHT segment")
+			}
+			copy(ht.counts[:], data[1:17])
+			count := 0
+			for _, c := range ht.counts {
+				count += int(c)
+			}
+			if count > 256 || len(data) < 17+count {
+				return errors.New("invalid DHT segment")
+			}
+			ht.values = data[17 : 17+count]
+			if err := ht.build(); err != nil {
+				return err
+			}
+			htables[data[0]>>4][data[0]&15] = ht
+			data = data[17+count:]
+		}
+	case 0xDD: // DRI
+		if len(data) != 2 {
+			return errors.New("invalid DRI segment")
+		}
+		f.restart = int(binary.BigEndian.Uint16(data))
+	}
+	return nil
+}
+
+// decodeScan decodes the scan whose header starts at the current position of
+// the bit reader.
+func (f *frame) decodeScan(br *bitReader, htables *[2][4]*huffTable) (err error) {
+	var (
+		data  = br.data[br.pos:]
+		scan  []int
+		preds = make([]int32, len(f.comps))
+		rst   byte
+	)
+	if f.comps == nil {
+		return errors.New("missing SOF segment")
+	}
+	if len(data) < 3 || len(data) < 2+int(binary.BigEndian.Uint16(data)) || len(data) < 6+2*int(data[2]) {
+		return errors.New("invalid SOS segment")
+	}
+	for i := 0; i < int(data[2]); i++ {
+		ci := -1
+		for j, c := range f.comps {
+			if c.id == data[3+2*i] {
+				ci = j
+			}
+		}
+		if ci < 0 {
+			return errors.New("SOS segment references unknown component")
+		}
+		c := f.comps[ci]
+		c.td, c.ta = data[4+2*i]>>4, data[4+2*i]&15
+		if c.td > 3 || c.ta > 3 || htables[0][c.td] == nil || htables[1][c.ta] == nil {
+			return errors.New("SOS segment references undefined Huffman table")
+		}
+		scan = append(scan, ci)
+	}
+	f.scans = append(f.scans, scan)
+	br.pos += int(binary.BigEndian.Uint16(data))
+	return f.scanBlocks(scan, func(ci int, blk *[64]int32) error {
+		var (
+			c   = f.comps[ci]
+			sym byte
+		)
+		if sym, err = br.decode(htables[0][c.td]); err != nil {
+			return err
+		}
+		if sym > 16 {
+			return errors.New("invalid DC coefficient size")
+		}
+		preds[ci] += br.receive(uint(sym))
+		blk[0] = preds[ci]
+		for k := 1; k < 64; k++ {
+			if sym, err = br.decode(htables[1][c.ta]); err != nil {
+				return err
+			}
+			if sym&15 == 0 {
+				if sym != 0xF0 {
+					break
+				}
+				k += 15
+				continue
+			}
+			if k += int(sym >> 4); k > 63 {
+				return errors.New("invalid AC coefficient run")
+			}
+			blk[zigzag[k]] = br.receive(uint(sym & 15))
+		}
+		return nil
+	}, func() error {
+		if marker := br.nextMarker(); marker != 0xD0+rst {
+			return errors.New("missing restart marker")
+		}
+		rst = (rst + 1) & 7
+		for i := range preds {
+			preds[i] = 0
+		}
+		return nil
+	})
+}
+
+// scanBlocks calls the block function for each block in the specified scan,
+// in the order in which they are coded, and calls the restart function at the
+// end of each restart interval.
+func (f *frame) scanBlocks(scan []int, block func(int, *[64]int32) error, restart func() error) (err error) {
+	var mx, my int
+
+	if len(scan) == 1 {
+		// Non-interleaved scans contain only the blocks that cover the
+		// component's portion of the image, one block per MCU.
+		c := f.comps[scan[0]]
+		mx = ((f.width*c.h+f.hmax-1)/f.hmax + 7) / 8
+		my = ((f.height*c.v+f.vmax-1)/f.vmax + 7) / 8
+	} else {
+		mx, my = f.mcusX, f.mcusY
+	}
+	for y, n := 0, 0; y < my; y++ {
+		for x := 0; x < mx; x, n = x+1, n+1 {
+			if f.restart != 0 && n != 0 && n%f.restart == 0 {
+				if err = restart(); err != nil {
+					return err
+				}
+			}
+			if len(scan) == 1 {
+				c := f.comps[scan[0]]
+				if err = block(scan[0], &c.blocks[y*c.bw+x]); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, ci := range scan {
+				c := f.comps[ci]
+				for v := 0; v < c.v; v++ {
+					for h := 0; h < c.h; h++ {
+						if err = block(ci, &c.blocks[(y*c.v+v)*c.bw+x*c.h+h]); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// transform returns a new frame containing the transformed image data.  The
+// transposition, if any, is done first, followed by the flips.  The receiver
+// must already have been trimmed so that any reversed dimensions are whole
+// MCUs.
+func (f *frame) transform(transpose, flipX, flipY bool) (nf *frame) {
+	nf = &frame{
+		marker: f.marker, precision: f.precision, width: f.width, height: f.height,
+		restart: f.restart, scans: f.scans, hmax: f.hmax, vmax: f.vmax, trailer: f.trailer,
+	}
+	if transpose {
+		nf.width, nf.height, nf.hmax, nf.vmax = f.height, f.width, f.vmax, f.hmax
+	}
+	nf.mcusX = (nf.width + 8*nf.hmax - 1) / (8 * nf.hmax)
+	nf.mcusY = (nf.height + 8*nf.vmax - 1) / (8 * nf.vmax)
+	for i, qt := range f.qtables {
+		if qt != nil && transpose {
+			nqt := &qtable{precision: qt.precision}
+			for j := range qt.values {
+				nqt.values[j%8*8+j/8] = qt.values[j]
+			}
+			qt = nqt
+		}
+		nf.qtables[i] = qt
+	}
+	for _, c := range f.comps {
+		nc := &component{id: c.id, h: c.h, v: c.v, tq: c.tq, td: c.td, ta: c.ta}
+		if transpose {
+			nc.h, nc.v = c.v, c.h
+		}
+		nc.bw, nc.bh = nf.mcusX*nc.h, nf.mcusY*nc.v
+		nc.blocks = make([][64]int32, nc.bw*nc.bh)
+		// The number of blocks, in each dimension, that cover the
+		// component's portion of the result image.  These are exact in
+		// any flipped dimension, because of the trimming.
+		cw := (nf.width*nc.h/nf.hmax + 7) / 8
+		ch := (nf.height*nc.v/nf.vmax + 7) / 8
+		for by := 0; by < nc.bh; by++ {
+			for bx := 0; bx < nc.bw; bx++ {
+				sx, sy := bx, by
+				if flipX {
+					sx = cw - 1 - bx
+				}
+				if flipY {
+					sy = ch - 1 - by
+				}
+				if transpose {
+					sx, sy = sy, sx
+				}
+				if sx < 0 || sy < 0 || sx >= c.bw || sy >= c.bh {
+					continue // padding block; leave it zero
+				}
+				transformBlock(&nc.blocks[by*nc.bw+bx], &c.blocks[sy*c.bw+sx], transpose, flipX, flipY)
+			}
+		}
+		nf.comps = append(nf.comps, nc)
+	}
+	return nf
+}
+
+// transformBlock transforms the DCT coefficients of a single block.
+// Transposing the block transposes its coefficients; mirroring it negates
+// the coefficients with odd horizontal (or vertical) frequency.
+func transformBlock(dst, src *[64]int32, transpose, flipX, flipY bool) {
+	for v := 0; v < 8; v++ {
+		for u := 0; u < 8; u++ {
+			coef := src[v*8+u]
+			du, dv := u, v
+			if transpose {
+				du, dv = v, u
+			}
+			if flipX && du&1 != 0 {
+				coef = -coef
+			}
+			if flipY && dv&1 != 0 {
+				coef = -coef
+			}
+			dst[dv*8+du] = coef
+		}
+	}
+}
+
+// encode encodes the image data of the frame, returning the contents of the
+// scans, starting just after the first SOS marker and ending with the EOI
+// marker and any trailing data.  Optimal Huffman tables are computed for the
+// data, and are left in the frame for tableSegments.
+func (f *frame) encode() (out []byte, err error) {
+	var (
+		freqs   [2][4]*[256]int64
+		htables [2][4]*huffTable
+	)
+	// First pass:  gather symbol statistics.
+	for _, scan := range f.scans {
+		f.encodeScan(scan, func(class, id int, sym byte) {
+			if freqs[class][id] == nil {
+				freqs[class][id] = new([256]int64)
+			}
+			freqs[class][id][sym]++
+		}, nil)
+	}
+	for class := range freqs {
+		for id, freq := range freqs[class] {
+			if freq != nil {
+				htables[class][id] = optimalHuffTable(freq)
+			}
+		}
+	}
+	f.htables = htables
+	// Second pass:  encode the data.
+	for i, scan := range f.scans {
+		var bw bitWriter
+
+		if i != 0 {
+			bw.marker(0xDA)
+		}
+		// The SOS segment, without its marker.
+		bw.buf = append(bw.buf, 0, byte(6+2*len(scan)), byte(len(scan)))
+		for _, ci := range scan {
+			c := f.comps[ci]
+			bw.buf = append(bw.buf, c.id, c.td<<4|c.ta)
+		}
+		bw.buf = append(bw.buf, 0, 63, 0)
+		f.encodeScan(scan, func(class, id int, sym byte) {
+			ht := htables[class][id]
+			bw.bits(uint32(ht.ehufco[sym]), uint(ht.ehufsi[sym]))
+		}, &bw)
+		bw.flush()
+		out = append(out, bw.buf...)
+	}
+	out = append(out, 0xFF, 0xD9)
+	return append(out, f.trailer...), nil
+}
+
+// encodeScan encodes one scan of the frame.  The symbol function is called
+// for each Huffman-coded symbol, with the table class and ID to use.  If bw
+// is not nil, the additional bits and restart markers are written to it.
+func (f *frame) encodeScan(scan []int, symbol func(int, int, byte), bw *bitWriter) {
+	var (
+		preds = make([]int32, len(f.comps))
+		rst   byte
+	)
+	f.scanBlocks(scan, func(ci int, blk *[64]int32) error {
+		var (
+			c    = f.comps[ci]
+			run  int
+			size uint
+			bits uint32
+		)
+		size, bits = category(blk[0] - preds[ci])
+		preds[ci] = blk[0]
+		symbol(0, int(c.td), byte(size))
+		if bw != nil {
+			bw.bits(bits, size)
+		}
+		for k := 1; k < 64; k++ {
+			coef := blk[zigzag[k]]
+			if coef == 0 {
+				run++
+				continue
+			}
+			for ; run > 15; run -= 16 {
+				symbol(1, int(c.ta), 0xF0)
+			}
+			size, bits = category(coef)
+			symbol(1, int(c.ta), byte(run<<4)|byte(size))
+			if bw != nil {
+				bw.bits(bits, size)
+			}
+			run = 0
+		}
+		if run != 0 {
+			symbol(1, int(c.ta), 0x00)
+		}
+		return nil
+	}, func() error {
+		if bw != nil {
+			bw.flush()
+			bw.marker(0xD0 + rst)
+		}
+		rst = (rst + 1) & 7
+		for i := range preds {
+			preds[i] = 0
+		}
+		return nil
+	})
+}
+
+// tableSegments returns the DQT, SOF, DHT, and DRI segments for the frame.
+// It must be called after encode.
+func (f *frame) tableSegments() (segs []*segmentGroup) {
+	var data []byte
+
+	for i, qt := range f.qtables {
+		if qt == nil {
+			continue
+		}
+		data = append(data, qt.precision<<4|byte(i))
+		for j := 0; j < 64; j++ {
+			if qt.precision == 0 {
+				data = append(data, byte(qt.values[zigzag[j]]))
+			} else {
+				data = append(data, byte(qt.values[zigzag[j]]>>8), byte(qt.values[zigzag[j]]))
+			}
+		}
+	}
+	segs = append(segs, &segmentGroup{marker: 0xDB, reader: bytes.NewReader(data)})
+	data = []byte{f.precision, byte(f.height >> 8), byte(f.height), byte(f.width >> 8), byte(f.width), byte(len(f.comps))}
+	for _, c := range f.comps {
+		data = append(data, c.id, byte(c.h<<4|c.v), c.tq)
+	}
+	segs = append(segs, &segmentGroup{marker: f.marker, reader: bytes.NewReader(data)})
+	data = nil
+	for class := range f.htables {
+		for id, ht := range f.htables[class] {
+			if ht == nil {
+				continue
+			}
+			data = append(data, byte(class<<4|id))
+			data = append(data, ht.counts[:]...)
+			data = append(data, ht.values...)
+		}
+	}
+	segs = append(segs, &segmentGroup{marker: 0xC4, reader: bytes.NewReader(data)})
+	if f.restart != 0 {
+		data = []byte{byte(f.restart >> 8), byte(f.restart)}
+		segs = append(segs, &segmentGroup{marker: 0xDD, reader: bytes.NewReader(data)})
+	}
+	return segs
+}
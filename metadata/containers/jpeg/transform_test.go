@@ -0,0 +1,116 @@
+package jpeg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	stdjpeg "image/jpeg"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// makeTestImage returns a JPEG file containing a w by h image with enough
+// detail to catch misplaced blocks and coefficients.
+func makeTestImage(t *testing.T, w, h int, gray bool) []byte {
+	var (
+		img image.Image
+		buf bytes.Buffer
+	)
+	if gray {
+		g := image.NewGray(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				g.SetGray(x, y, color.Gray{Y: uint8(x*5 + y*3 + (x*y)%17)})
+			}
+		}
+		img = g
+	} else {
+		rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				rgba.Set(x, y, color.RGBA{R: uint8(x * 6), G: uint8(y * 9), B: uint8((x * y) % 251), A: 255})
+			}
+		}
+		img = rgba
+	}
+	if err := stdjpeg.Encode(&buf, img, &stdjpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// sourcePixel returns the coordinates in the source image of the pixel that
+// a transformation with orientation o places at (x, y), given the dimensions
+// of the kept portion of the source image.
+func sourcePixel(o metadata.Orientation, x, y, w, h int) (int, int) {
+	switch o {
+	case metadata.FlipX:
+		return w - 1 - x, y
+	case metadata.FlipXY:
+		return w - 1 - x, h - 1 - y
+	case metadata.FlipY:
+		return x, h - 1 - y
+	case metadata.Rotate90FlipX:
+		return y, x
+	case metadata.Rotate90:
+		return y, h - 1 - x
+	case metadata.Rotate270FlipX:
+		return w - 1 - y, h - 1 - x
+	case metadata.Rotate270:
+		return w - 1 - y, x
+	}
+	return x, y
+}
+
+func TestTransform(t *testing.T) {
+	for _, gray := range []bool{false, true} {
+		file := makeTestImage(t, 40, 24, gray)
+		src, err := stdjpeg.Decode(bytes.NewReader(file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for o := metadata.Rotate0; o <= metadata.Rotate270; o++ {
+			var buf bytes.Buffer
+
+			jpeg := readTestFile(t, file)
+			orig, kept, result, err := jpeg.Transform(o)
+			if err != nil {
+				t.Fatalf("gray=%v o=%d: %s", gray, o, err)
+			}
+			if orig != (metadata.Dimensions{Width: 40, Height: 24}) {
+				t.Errorf("gray=%v o=%d: orig = %v", gray, o, orig)
+			}
+			jpeg.Layout()
+			if _, err = jpeg.Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			dst, err := stdjpeg.Decode(&buf)
+			if err != nil {
+				t.Fatalf("gray=%v o=%d: decode: %s", gray, o, err)
+			}
+			if b := dst.Bounds(); b.Dx() != result.Width || b.Dy() != result.Height {
+				t.Fatalf("gray=%v o=%d: result is %dx%d, expected %v", gray, o, b.Dx(), b.Dy(), result)
+			}
+			for y := 0; y < result.Height; y++ {
+				for x := 0; x < result.Width; x++ {
+					sx, sy := sourcePixel(o, x, y, kept.Width, kept.Height)
+					r1, g1, b1, _ := dst.At(x, y).RGBA()
+					r2, g2, b2, _ := src.At(sx, sy).RGBA()
+					if diff(r1, r2) > 3 || diff(g1, g2) > 3 || diff(b1, b2) > 3 {
+						t.Fatalf("gray=%v o=%d: pixel (%d,%d) differs from source (%d,%d)", gray, o, x, y, sx, sy)
+					}
+				}
+			}
+		}
+	}
+}
+
+// diff returns the difference between two 16-bit color values, scaled to 8
+// bits.
+func diff(a, b uint32) uint32 {
+	if a > b {
+		return (a - b) >> 8
+	}
+	return (b - a) >> 8
+}
@@ -0,0 +1,181 @@
+package jpeg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers"
+)
+
+// MaxXMPSize is the size of the largest XMP packet that fits in the standard
+// XMP segment of a JPEG file.  Larger packets must be split into standard and
+// extended XMP.
+const MaxXMPSize = 0xFFFF - 2 - 29 // 29 is len(nsXMP)
+
+// xmpextHeaderSize is the size of the header at the start of each extended
+// XMP segment (following the namespace).  It contains the 32-character GUID of
+// the extended XMP packet, the full length of the packet, and the offset of
+// this segment's portion of it.
+const xmpextHeaderSize = 40
+
+// An xmpExtension is the set of extended XMP segments in a JPEG file.  Each
+// extended XMP packet is identified by a GUID (the MD5 digest of the packet),
+// and is split across as many segments as needed, each of which carries the
+// offset of its portion of the packet.
+type xmpExtension struct {
+	segments  []*segmentGroup // as read from the file
+	guid      string
+	container containers.Container
+	rendered  []byte
+	size      int64
+}
+
+// Empty returns whether the container is empty (and should therefore be omitted
+// from the written file, along with whatever tag in the parent container points
+// to it).
+func (x *xmpExtension) Empty() bool {
+	if x == nil {
+		return true
+	}
+	return len(x.segments) == 0 && (x.container == nil || x.container.Empty())
+}
+
+// Dirty returns whether the extended XMP has been changed.
+func (x *xmpExtension) Dirty() bool {
+	if x == nil || x.container == nil {
+		return false
+	}
+	return x.container.Dirty()
+}
+
+// Layout computes the rendered layout of the container, i.e. prepares for a
+// call to Write, and returns what the rendered size of the container will be.
+func (x *xmpExtension) Layout() int64 {
+	var buf bytes.Buffer
+
+	x.size, x.rendered = 0, nil
+	for _, seg := range x.segments {
+		x.size += seg.Layout()
+	}
+	if x.container == nil || x.container.Empty() {
+		return x.size
+	}
+	// We need the rendered packet in hand in order to split it into
+	// segments, so render it now.
+	x.container.Layout()
+	if _, err := x.container.Write(&buf); err != nil {
+		panic(err)
+	}
+	x.rendered = buf.Bytes()
+	var chunk = int64(xmpextChunkSize())
+	var numChunks = (int64(len(x.rendered)) + chunk - 1) / chunk
+	x.size += int64(len(x.rendered)) + numChunks*(int64(len(nsXMPext))+4+xmpextHeaderSize)
+	return x.size
+}
+
+// xmpextChunkSize returns the maximum amount of extended XMP packet data that
+// fits into a single segment.
+func xmpextChunkSize() int {
+	return 0xFFFF - 2 - len(nsXMPext) - xmpextHeaderSize
+}
+
+// Write writes the extended XMP segments to the specified writer.
+func (x *xmpExtension) Write(w io.Writer) (count int, err error) {
+	var (
+		buf   [4 + 64 + xmpextHeaderSize]byte
+		chunk = xmpextChunkSize()
+		n     int
+	)
+	for _, seg := range x.segments {
+		n, err = seg.Write(w)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	for offset := 0; offset < len(x.rendered); offset += chunk {
+		var end = offset + chunk
+		if end > len(x.rendered) {
+			end = len(x.rendered)
+		}
+		buf[0] = 0xFF
+		buf[1] = markerXMPext
+		binary.BigEndian.PutUint16(buf[2:4], uint16(2+len(nsXMPext)+xmpextHeaderSize+end-offset))
+		hdr := buf[4+len(nsXMPext):]
+		copy(buf[4:], nsXMPext)
+		copy(hdr[0:32], x.guid)
+		binary.BigEndian.PutUint32(hdr[32:36], uint32(len(x.rendered)))
+		binary.BigEndian.PutUint32(hdr[36:40], uint32(offset))
+		n, err = w.Write(buf[:4+len(nsXMPext)+xmpextHeaderSize])
+		count += n
+		if err != nil {
+			return count, err
+		}
+		n, err = w.Write(x.rendered[offset:end])
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	if int(x.size) != count {
+		panic("actual size different from predicted size")
+	}
+	return count, nil
+}
+
+// packet returns a reader for the extended XMP packet with the specified GUID,
+// reassembled from the segments that carry it.  It returns nil if there is no
+// such packet, or if some of its segments are missing.
+func (x *xmpExtension) packet(guid string) metadata.Reader {
+	type piece struct {
+		offset int64
+		reader *io.SectionReader
+	}
+	var (
+		buf    [xmpextHeaderSize]byte
+		pieces []piece
+		length int64 = -1
+		mr     multireader
+		next   int64
+	)
+	if x == nil {
+		return nil
+	}
+	for _, seg := range x.segments {
+		if seg.reader.Size() < xmpextHeaderSize {
+			continue
+		}
+		if _, err := seg.reader.ReadAt(buf[:], 0); err != nil || string(buf[0:32]) != guid {
+			continue
+		}
+		if length < 0 {
+			length = int64(binary.BigEndian.Uint32(buf[32:36]))
+		} else if length != int64(binary.BigEndian.Uint32(buf[32:36])) {
+			return nil // inconsistent lengths
+		}
+		pieces = append(pieces, piece{
+			offset: int64(binary.BigEndian.Uint32(buf[36:40])),
+			reader: io.NewSectionReader(seg.reader, xmpextHeaderSize, seg.reader.Size()-xmpextHeaderSize),
+		})
+	}
+	if len(pieces) == 0 {
+		return nil
+	}
+	// The segments are supposed to be in order, but the XMP specification
+	// allows readers to tolerate them being out of order.
+	sort.SliceStable(pieces, func(i, j int) bool { return pieces[i].offset < pieces[j].offset })
+	for _, p := range pieces {
+		if p.offset != next {
+			return nil // gap or overlap
+		}
+		mr.rdrs = append(mr.rdrs, p.reader)
+		next += p.reader.Size()
+	}
+	if next != length {
+		return nil // truncated
+	}
+	return &mr
+}
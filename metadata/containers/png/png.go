@@ -0,0 +1,328 @@
+// Package png handles marshaling and unmarshaling of PNG file chunks.
+package png
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers"
+)
+
+var pngSignature = []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+
+var (
+	typeEXIF = "eXIf"
+	typeITXT = "iTXt"
+	typeIDAT = "IDAT"
+	typeIEND = "IEND"
+)
+
+// xmpKeyword is the keyword of the iTXt chunk that contains XMP metadata.
+var xmpKeyword = []byte("XML:com.adobe.xmp")
+
+// xmpHeader is the header we write at the start of the iTXt chunk data for an
+// XMP chunk:  the keyword, a null terminator, an uncompressed flag and method,
+// and empty language tag and translated keyword.
+var xmpHeader = append(append([]byte{}, xmpKeyword...), 0, 0, 0, 0, 0)
+
+// A PNG is a container of chunks.
+type PNG struct {
+	chunks []*chunk
+	exif   *chunk
+	xmp    *chunk
+	all    []*chunk
+	size   int64
+}
+
+// A chunk is a single chunk of a PNG file.
+type chunk struct {
+	ctype     string
+	header    []byte          // data header written before the container
+	raw       metadata.Reader // entire chunk, including length and CRC
+	data      metadata.Reader // chunk data only, excluding any header
+	container containers.Container
+	rewrite   bool  // whether the chunk must be rendered from its container
+	csize     int64 // rendered size of the container
+}
+
+var _ containers.Container = (*PNG)(nil) // verify interface compliance
+
+// Read reads and parses the container structure from the supplied Reader.  The
+// reader will continue to be used after Read returns, and must remain open and
+// usable as long as the Container is in scope.
+func (png *PNG) Read(r metadata.Reader) (err error) {
+	var (
+		buf    [8]byte
+		offset int64
+		ch     *chunk
+	)
+	if _, err = r.ReadAt(buf[:], 0); err != nil || !bytes.Equal(buf[:], pngSignature) {
+		return errors.New("PNG: not a PNG file")
+	}
+	offset = 8
+	for {
+		if _, err = r.ReadAt(buf[:], offset); err != nil {
+			return errors.New("PNG: missing IEND chunk")
+		}
+		ch = &chunk{ctype: string(buf[4:8])}
+		size := int64(binary.BigEndian.Uint32(buf[0:4]))
+		if offset+size+12 > r.Size() {
+			return fmt.Errorf("PNG: %s chunk: truncated", ch.ctype)
+		}
+		ch.raw = io.NewSectionReader(r, offset, size+12)
+		ch.data = io.NewSectionReader(r, offset+8, size)
+		offset += size + 12
+		switch {
+		case ch.ctype == typeEXIF:
+			if png.exif != nil {
+				return errors.New("PNG: multiple eXIf chunks")
+			}
+			skipEXIFPrefix(ch)
+			png.exif = ch
+		case ch.ctype == typeITXT && isXMPChunk(ch):
+			if png.xmp != nil {
+				return errors.New("PNG: multiple XMP chunks")
+			}
+			if err = readXMPChunk(ch); err != nil {
+				return fmt.Errorf("PNG: XMP chunk: %s", err)
+			}
+			png.xmp = ch
+		}
+		png.chunks = append(png.chunks, ch)
+		if ch.ctype == typeIEND {
+			return nil
+		}
+	}
+}
+
+// skipEXIFPrefix handles eXIf chunks written by software that (incorrectly)
+// includes the "Exif\0\0" prefix used in JPEG files.
+func skipEXIFPrefix(ch *chunk) {
+	var buf [6]byte
+
+	if ch.data.Size() < 6 {
+		return
+	}
+	ch.data.ReadAt(buf[:], 0)
+	if bytes.Equal(buf[:], []byte("Exif\000\000")) {
+		ch.data = io.NewSectionReader(ch.data, 6, ch.data.Size()-6)
+	}
+}
+
+// isXMPChunk returns whether the iTXt chunk contains XMP metadata.
+func isXMPChunk(ch *chunk) bool {
+	var buf = make([]byte, len(xmpKeyword)+1)
+
+	if _, err := ch.data.ReadAt(buf, 0); err != nil {
+		return false
+	}
+	return bytes.Equal(buf[:len(xmpKeyword)], xmpKeyword) && buf[len(xmpKeyword)] == 0
+}
+
+// readXMPChunk parses the header of an iTXt chunk containing XMP metadata, and
+// sets the chunk's data reader to the XMP text, decompressing it if needed.
+func readXMPChunk(ch *chunk) (err error) {
+	var (
+		by   []byte
+		idx  int
+		text []byte
+	)
+	by = make([]byte, ch.data.Size())
+	if _, err = ch.data.ReadAt(by, 0); err != nil {
+		return err
+	}
+	by = by[len(xmpKeyword)+1:]
+	if len(by) < 2 {
+		return errors.New("truncated header")
+	}
+	compressed := by[0] != 0
+	if compressed && by[1] != 0 {
+		return errors.New("unknown compression method")
+	}
+	by = by[2:]
+	for i := 0; i < 2; i++ { // skip language tag and translated keyword
+		if idx = bytes.IndexByte(by, 0); idx < 0 {
+			return errors.New("truncated header")
+		}
+		by = by[idx+1:]
+	}
+	if !compressed {
+		ch.data = io.NewSectionReader(ch.data, ch.data.Size()-int64(len(by)), int64(len(by)))
+		return nil
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(by))
+	if err != nil {
+		return err
+	}
+	if text, err = io.ReadAll(zr); err != nil {
+		return err
+	}
+	ch.data = bytes.NewReader(text)
+	return nil
+}
+
+// Empty returns whether the container is empty (and should therefore be omitted
+// from the written file, along with whatever tag in the parent container points
+// to it).
+func (png *PNG) Empty() bool { return false } // PNGs are never empty
+
+// Dirty returns whether any of the PNG chunks have been changed.
+func (png *PNG) Dirty() bool {
+	return png.exif.dirty() || png.xmp.dirty()
+}
+
+// Layout computes the rendered layout of the container, i.e. prepares for a
+// call to Write, and returns what the rendered size of the container will be.
+func (png *PNG) Layout() int64 {
+	var pending []*chunk
+
+	// New metadata chunks (i.e., those that weren't in the chunk list when
+	// the file was read) get inserted before the first IDAT chunk.
+	for _, ch := range []*chunk{png.exif, png.xmp} {
+		if ch != nil && ch.raw == nil {
+			pending = append(pending, ch)
+		}
+	}
+	png.all = png.all[:0]
+	for _, ch := range png.chunks {
+		if ch.ctype == typeIDAT || ch.ctype == typeIEND {
+			png.all = append(png.all, pending...)
+			pending = nil
+		}
+		png.all = append(png.all, ch)
+	}
+	png.size = int64(len(pngSignature))
+	var all = png.all[:0]
+	for _, ch := range png.all {
+		if ch.container != nil && ch.container.Empty() {
+			continue
+		}
+		all = append(all, ch)
+		png.size += ch.layout()
+	}
+	png.all = all
+	return png.size
+}
+
+// Write writes the rendered container to the specified writer.
+func (png *PNG) Write(w io.Writer) (count int, err error) {
+	var n int
+
+	n, err = w.Write(pngSignature)
+	count += n
+	if err != nil {
+		return count, err
+	}
+	for _, ch := range png.all {
+		n, err = ch.write(w)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	if int(png.size) != count {
+		panic("actual size different from predicted size")
+	}
+	return count, nil
+}
+
+// EXIF returns the contents of the eXIf chunk, if any.
+func (png *PNG) EXIF() metadata.Reader {
+	if png.exif != nil {
+		return png.exif.data
+	}
+	return nil
+}
+
+// XMP returns the contents of the XMP chunk, if any.
+func (png *PNG) XMP() metadata.Reader {
+	if png.xmp != nil {
+		return png.xmp.data
+	}
+	return nil
+}
+
+// SetEXIFContainer sets the contents of the eXIf chunk to those provided by the
+// supplied container.
+func (png *PNG) SetEXIFContainer(c containers.Container) {
+	if png.exif == nil {
+		png.exif = &chunk{ctype: typeEXIF}
+	}
+	png.exif.container = c
+}
+
+// SetXMPContainer sets the contents of the XMP chunk to those provided by the
+// supplied container.
+func (png *PNG) SetXMPContainer(c containers.Container) {
+	if png.xmp == nil {
+		png.xmp = &chunk{ctype: typeITXT}
+	}
+	png.xmp.container = c
+	png.xmp.header = xmpHeader
+}
+
+// dirty returns whether the chunk has been changed.
+func (ch *chunk) dirty() bool {
+	if ch == nil || ch.container == nil {
+		return false
+	}
+	return ch.container.Dirty()
+}
+
+// layout computes the rendered size of the chunk.
+func (ch *chunk) layout() int64 {
+	if ch.container == nil || !ch.container.Dirty() && ch.raw != nil {
+		ch.rewrite = false
+		return ch.raw.Size()
+	}
+	ch.rewrite = true
+	ch.csize = ch.container.Layout()
+	return int64(len(ch.header)) + ch.csize + 12
+}
+
+// write writes the chunk to the specified writer.
+func (ch *chunk) write(w io.Writer) (count int, err error) {
+	var (
+		buf [8]byte
+		n   int
+		n64 int64
+		crc = crc32.NewIEEE()
+		cw  io.Writer
+	)
+	if !ch.rewrite {
+		ch.raw.Seek(0, io.SeekStart)
+		n64, err = io.Copy(w, ch.raw)
+		return int(n64), err
+	}
+	binary.BigEndian.PutUint32(buf[0:4], uint32(int64(len(ch.header))+ch.csize))
+	copy(buf[4:8], ch.ctype)
+	if n, err = w.Write(buf[:]); err != nil {
+		return n, err
+	}
+	count += n
+	crc.Write(buf[4:8])
+	cw = io.MultiWriter(w, crc)
+	n, err = cw.Write(ch.header)
+	count += n
+	if err != nil {
+		return count, err
+	}
+	n, err = ch.container.Write(cw)
+	count += n
+	if err != nil {
+		return count, err
+	}
+	if int64(n) != ch.csize {
+		panic("actual size different from predicted size")
+	}
+	binary.BigEndian.PutUint32(buf[0:4], crc.Sum32())
+	n, err = w.Write(buf[0:4])
+	count += n
+	return count, err
+}
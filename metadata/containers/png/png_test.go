@@ -0,0 +1,100 @@
+package png
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/raw"
+)
+
+// makeChunk returns the bytes of a PNG chunk with the specified type and data.
+func makeChunk(ctype string, data []byte) []byte {
+	var by = make([]byte, 8, len(data)+12)
+	binary.BigEndian.PutUint32(by[0:4], uint32(len(data)))
+	copy(by[4:8], ctype)
+	by = append(by, data...)
+	by = append(by, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(by[len(by)-4:], crc32.ChecksumIEEE(by[4:len(by)-4]))
+	return by
+}
+
+func makePNG(chunks ...[]byte) []byte {
+	var by = append([]byte{}, pngSignature...)
+	for _, chunk := range chunks {
+		by = append(by, chunk...)
+	}
+	return by
+}
+
+var (
+	testIHDR = makeChunk("IHDR", []byte{0, 0, 0, 1, 0, 0, 0, 1, 8, 0, 0, 0, 0})
+	testIDAT = makeChunk("IDAT", []byte{0x78, 0x9C, 0x63, 0x60, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01})
+	testIEND = makeChunk("IEND", nil)
+)
+
+func TestReadWriteUnchanged(t *testing.T) {
+	var (
+		input = makePNG(testIHDR, makeChunk("eXIf", []byte("Exif\000\000MM")), testIDAT, testIEND)
+		p     PNG
+		buf   bytes.Buffer
+	)
+	if err := p.Read(bytes.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	exif, _ := io.ReadAll(p.EXIF())
+	if string(exif) != "MM" {
+		t.Errorf("EXIF: got %q, expected \"MM\"", exif)
+	}
+	if p.XMP() != nil {
+		t.Error("XMP: got data, expected nil")
+	}
+	var r raw.Raw
+	r.Read(p.EXIF())
+	p.SetEXIFContainer(&r)
+	if p.Dirty() {
+		t.Error("Dirty: got true, expected false")
+	}
+	p.Layout()
+	if _, err := p.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), input) {
+		t.Error("output differs from input")
+	}
+}
+
+func TestAddXMP(t *testing.T) {
+	var (
+		input    = makePNG(testIHDR, testIDAT, testIEND)
+		expected = makePNG(testIHDR, makeChunk("iTXt", append(append([]byte{}, xmpHeader...), "<x/>"...)), testIDAT, testIEND)
+		p        PNG
+		r        raw.Raw
+		buf      bytes.Buffer
+	)
+	if err := p.Read(bytes.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	p.SetXMPContainer(&r)
+	r.SetData([]byte("<x/>"))
+	if !p.Dirty() {
+		t.Error("Dirty: got false, expected true")
+	}
+	p.Layout()
+	if _, err := p.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Fatalf("output mismatch:\nexpected % x\nactual   % x", expected, buf.Bytes())
+	}
+	p = PNG{}
+	if err := p.Read(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	xmp, _ := io.ReadAll(p.XMP())
+	if string(xmp) != "<x/>" {
+		t.Errorf("XMP: got %q, expected \"<x/>\"", xmp)
+	}
+}
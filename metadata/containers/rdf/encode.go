@@ -20,6 +20,7 @@ func (p *Packet) Layout() (size int64) {
 	// There's no way to know the size other than actually rendering the
 	// XML.
 	var buf bytes.Buffer
+	p.rendered = nil
 	if _, err := p.Write(&buf); err != nil {
 		panic(err)
 	}
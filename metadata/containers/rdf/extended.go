@@ -0,0 +1,73 @@
+package rdf
+
+import "sort"
+
+// Merge adds to the packet all of the properties of the other packet that it
+// doesn't already have.  It is used to combine a standard XMP packet with its
+// extended XMP packet after reading them, and so it does not mark the packet
+// dirty.
+func (p *Packet) Merge(other *Packet) {
+	for name, value := range other.properties {
+		if _, ok := p.properties[name]; !ok {
+			p.properties[name] = value
+		}
+	}
+	for uri, prefix := range other.nsprefixes {
+		if _, ok := p.nsprefixes[uri]; !ok {
+			p.nsprefixes[uri] = prefix
+		}
+	}
+}
+
+// Split divides the packet into a standard packet whose rendering is no larger
+// than limit bytes, and an extended packet holding the properties that don't
+// fit, moving the largest properties first.  This is needed for file formats
+// (i.e., JPEG) that limit the size of an XMP packet.  When an extended packet
+// is needed, the marker property is added to the standard packet with the
+// placeholder value; the caller is expected to replace that value with one
+// that identifies the extended packet and has the same rendered size.  When
+// no extended packet is needed, Split returns a copy of the packet without the
+// marker property and a nil extended packet.  The packet itself is unchanged.
+func (p *Packet) Split(limit int64, marker Name, placeholder Value) (std, ext *Packet) {
+	var (
+		names []Name
+		sizes = make(map[Name]int64)
+	)
+	std = p.copy()
+	delete(std.properties, marker)
+	if std.Layout() <= limit {
+		return std, nil
+	}
+	ext = &Packet{properties: make(Struct), nsprefixes: p.nsprefixes, about: p.about, dirty: p.dirty}
+	for name, value := range std.properties {
+		var single = Packet{properties: Struct{name: value}, nsprefixes: p.nsprefixes}
+
+		names = append(names, name)
+		sizes[name] = single.Layout()
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if sizes[names[i]] != sizes[names[j]] {
+			return sizes[names[i]] > sizes[names[j]]
+		}
+		return names[i].String() < names[j].String()
+	})
+	std.properties[marker] = placeholder
+	for _, name := range names {
+		if std.Layout() <= limit {
+			break
+		}
+		ext.properties[name] = std.properties[name]
+		delete(std.properties, name)
+	}
+	return std, ext
+}
+
+// copy returns a copy of the packet.  The property values are shared.
+func (p *Packet) copy() *Packet {
+	var c = Packet{properties: make(Struct, len(p.properties)), nsprefixes: p.nsprefixes, about: p.about, dirty: p.dirty}
+
+	for name, value := range p.properties {
+		c.properties[name] = value
+	}
+	return &c
+}
@@ -72,3 +72,40 @@ func Test1(t *testing.T) {
 		}
 	}
 }
+
+func TestSplit(t *testing.T) {
+	var (
+		marker = Name{"http://ns.adobe.com/xmp/note/", "HasExtendedXMP"}
+		small  = Name{"http://ns.adobe.com/xap/1.0/", "Rating"}
+		large  = Name{"http://ns.adobe.com/xap/1.0/", "Label"}
+	)
+	p := New()
+	p.RegisterNamespace("xmp", "http://ns.adobe.com/xap/1.0/")
+	p.RegisterNamespace("xmpNote", "http://ns.adobe.com/xmp/note/")
+	p.SetProperty(small, Value{Value: "3"})
+	p.SetProperty(large, Value{Value: strings.Repeat("x", 1000)})
+	std, ext := p.Split(2000, marker, Value{Value: "GUID"})
+	if ext != nil || len(std.Properties()) != 2 {
+		t.Errorf("small packet: unexpected split")
+	}
+	std, ext = p.Split(500, marker, Value{Value: "GUID"})
+	if ext == nil {
+		t.Fatal("large packet: not split")
+	}
+	if std.Property(small).Value != "3" || std.Property(marker).Value != "GUID" || std.Property(large).Value != nil {
+		t.Errorf("large packet: wrong standard properties %v", std.Properties())
+	}
+	if len(ext.Properties()) != 1 || ext.Property(large).Value == nil {
+		t.Errorf("large packet: wrong extended properties %v", ext.Properties())
+	}
+	if std.Layout() > 500 {
+		t.Errorf("large packet: standard packet too large")
+	}
+	if len(p.Properties()) != 2 {
+		t.Errorf("original packet changed")
+	}
+	std.Merge(ext)
+	if len(std.Properties()) != 3 || std.Property(large).Value == nil {
+		t.Errorf("merge: wrong properties %v", std.Properties())
+	}
+}
@@ -0,0 +1,378 @@
+// Package riff handles marshaling and unmarshaling of RIFF files, such as WebP
+// images.
+package riff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers"
+)
+
+var (
+	typeEXIF = "EXIF"
+	typeXMP  = "XMP "
+	typeVP8X = "VP8X"
+	typeVP8  = "VP8 "
+	typeVP8L = "VP8L"
+	formWebP = "WEBP"
+)
+
+// Feature flags in the VP8X chunk of a WebP file.
+const (
+	flagXMP   = 0x04
+	flagEXIF  = 0x08
+	flagAlpha = 0x10
+)
+
+// A RIFF is a container of chunks.
+type RIFF struct {
+	form   string
+	chunks []*chunk
+	vp8x   *chunk
+	exif   *chunk
+	xmp    *chunk
+	all    []*chunk
+	size   int64
+	err    error
+}
+
+// A chunk is a single chunk of a RIFF file.
+type chunk struct {
+	ctype     string
+	raw       metadata.Reader // entire chunk, including header and padding
+	data      metadata.Reader // chunk data only
+	literal   []byte          // replacement chunk data, if changed
+	container containers.Container
+	rewrite   bool  // whether the chunk must be rendered from its container
+	csize     int64 // rendered size of the container
+}
+
+var _ containers.Container = (*RIFF)(nil) // verify interface compliance
+
+// Read reads and parses the container structure from the supplied Reader.  The
+// reader will continue to be used after Read returns, and must remain open and
+// usable as long as the Container is in scope.
+func (riff *RIFF) Read(r metadata.Reader) (err error) {
+	var (
+		buf    [12]byte
+		offset int64
+		end    int64
+		ch     *chunk
+	)
+	if _, err = r.ReadAt(buf[:], 0); err != nil || string(buf[0:4]) != "RIFF" {
+		return errors.New("RIFF: not a RIFF file")
+	}
+	riff.form = string(buf[8:12])
+	if end = int64(binary.LittleEndian.Uint32(buf[4:8])) + 8; end > r.Size() {
+		return errors.New("RIFF: truncated file")
+	}
+	offset = 12
+	for offset < end {
+		if end-offset < 8 {
+			return errors.New("RIFF: truncated chunk header")
+		}
+		if _, err = r.ReadAt(buf[:8], offset); err != nil {
+			return fmt.Errorf("RIFF: %s", err)
+		}
+		ch = &chunk{ctype: string(buf[0:4])}
+		size := int64(binary.LittleEndian.Uint32(buf[4:8]))
+		padded := size + size&1
+		if offset+8+padded > end {
+			return fmt.Errorf("RIFF: %s chunk: truncated", ch.ctype)
+		}
+		ch.raw = io.NewSectionReader(r, offset, padded+8)
+		ch.data = io.NewSectionReader(r, offset+8, size)
+		offset += padded + 8
+		switch ch.ctype {
+		case typeVP8X:
+			if riff.vp8x != nil || ch.data.Size() < 10 {
+				return errors.New("RIFF: invalid VP8X chunk")
+			}
+			riff.vp8x = ch
+		case typeEXIF:
+			if riff.exif != nil {
+				return errors.New("RIFF: multiple EXIF chunks")
+			}
+			skipEXIFPrefix(ch)
+			riff.exif = ch
+		case typeXMP:
+			if riff.xmp != nil {
+				return errors.New("RIFF: multiple XMP chunks")
+			}
+			riff.xmp = ch
+		}
+		riff.chunks = append(riff.chunks, ch)
+	}
+	return nil
+}
+
+// skipEXIFPrefix handles EXIF chunks written by software that (incorrectly)
+// includes the "Exif\0\0" prefix used in JPEG files.
+func skipEXIFPrefix(ch *chunk) {
+	var buf [6]byte
+
+	if ch.data.Size() < 6 {
+		return
+	}
+	ch.data.ReadAt(buf[:], 0)
+	if bytes.Equal(buf[:], []byte("Exif\000\000")) {
+		ch.data = io.NewSectionReader(ch.data, 6, ch.data.Size()-6)
+	}
+}
+
+// Empty returns whether the container is empty (and should therefore be omitted
+// from the written file, along with whatever tag in the parent container points
+// to it).
+func (riff *RIFF) Empty() bool { return false } // RIFFs are never empty
+
+// Dirty returns whether any of the RIFF chunks have been changed.
+func (riff *RIFF) Dirty() bool {
+	return riff.exif.dirty() || riff.xmp.dirty()
+}
+
+// Layout computes the rendered layout of the container, i.e. prepares for a
+// call to Write, and returns what the rendered size of the container will be.
+//
+// New metadata chunks are added at the end of the file (but with EXIF before
+// XMP), which is where the WebP specification puts them.  In a WebP file, the feature flags in the VP8X
+// chunk are updated to reflect which metadata chunks are present; if the file
+// was in the simple (lossy or lossless) format, a VP8X chunk is added to
+// convert it to the extended format.
+func (riff *RIFF) Layout() int64 {
+	var (
+		chunks []*chunk
+		flags  byte
+	)
+	riff.err = nil
+	for _, ch := range riff.chunks {
+		if ch == riff.xmp && riff.exif != nil && riff.exif.raw == nil {
+			chunks = append(chunks, riff.exif) // EXIF goes before XMP
+		}
+		chunks = append(chunks, ch)
+	}
+	if riff.exif != nil && riff.exif.raw == nil && (riff.xmp == nil || riff.xmp.raw == nil) {
+		chunks = append(chunks, riff.exif)
+	}
+	if riff.xmp != nil && riff.xmp.raw == nil {
+		chunks = append(chunks, riff.xmp)
+	}
+	riff.all = riff.all[:0]
+	for _, ch := range chunks {
+		if ch.container != nil && ch.container.Empty() {
+			continue
+		}
+		switch ch {
+		case riff.exif:
+			flags |= flagEXIF
+		case riff.xmp:
+			flags |= flagXMP
+		}
+		riff.all = append(riff.all, ch)
+	}
+	if riff.form == formWebP && riff.Dirty() {
+		if riff.vp8x == nil && flags != 0 {
+			if riff.vp8x, riff.err = riff.makeVP8X(); riff.err != nil {
+				return 0
+			}
+		}
+		if riff.vp8x != nil {
+			riff.setVP8XFlags(flags)
+		}
+	}
+	if riff.vp8x != nil && riff.vp8x.raw == nil {
+		riff.all = append([]*chunk{riff.vp8x}, riff.all...)
+	}
+	riff.size = 12
+	for _, ch := range riff.all {
+		riff.size += ch.layout()
+	}
+	return riff.size
+}
+
+// makeVP8X returns a new VP8X chunk for a WebP file in the simple format,
+// taking the canvas size (and alpha flag) from the image bitstream.
+func (riff *RIFF) makeVP8X() (vp8x *chunk, err error) {
+	var (
+		buf           [10]byte
+		width, height uint32
+		flags         byte
+	)
+	if len(riff.chunks) == 0 {
+		return nil, errors.New("RIFF: no WebP image data")
+	}
+	switch image := riff.chunks[0]; image.ctype {
+	case typeVP8:
+		if _, err = image.data.ReadAt(buf[:], 0); err != nil || !bytes.Equal(buf[3:6], []byte{0x9D, 0x01, 0x2A}) {
+			return nil, errors.New("RIFF: invalid VP8 chunk")
+		}
+		width = uint32(binary.LittleEndian.Uint16(buf[6:8]) & 0x3FFF)
+		height = uint32(binary.LittleEndian.Uint16(buf[8:10]) & 0x3FFF)
+	case typeVP8L:
+		if _, err = image.data.ReadAt(buf[:5], 0); err != nil || buf[0] != 0x2F {
+			return nil, errors.New("RIFF: invalid VP8L chunk")
+		}
+		bits := binary.LittleEndian.Uint32(buf[1:5])
+		width = bits&0x3FFF + 1
+		height = bits>>14&0x3FFF + 1
+		if bits&(1<<28) != 0 {
+			flags |= flagAlpha
+		}
+	default:
+		return nil, fmt.Errorf("RIFF: can't add VP8X chunk before %s chunk", image.ctype)
+	}
+	buf = [10]byte{flags}
+	putUint24(buf[4:7], width-1)
+	putUint24(buf[7:10], height-1)
+	return &chunk{ctype: typeVP8X, literal: buf[:]}, nil
+}
+
+// putUint24 stores a 24-bit little-endian integer.
+func putUint24(buf []byte, v uint32) {
+	buf[0], buf[1], buf[2] = byte(v), byte(v>>8), byte(v>>16)
+}
+
+// setVP8XFlags sets the EXIF and XMP flags in the VP8X chunk to match the
+// specified flags, leaving the other flags unchanged.
+func (riff *RIFF) setVP8XFlags(flags byte) {
+	var data = riff.vp8x.literal
+
+	if data == nil {
+		data = make([]byte, riff.vp8x.data.Size())
+		riff.vp8x.data.ReadAt(data, 0)
+	}
+	if newflags := data[0]&^(flagEXIF|flagXMP) | flags; newflags != data[0] {
+		data[0] = newflags
+		riff.vp8x.literal = data
+	}
+}
+
+// Write writes the rendered container to the specified writer.
+func (riff *RIFF) Write(w io.Writer) (count int, err error) {
+	var (
+		n   int
+		buf [12]byte
+	)
+	if riff.err != nil {
+		return 0, riff.err
+	}
+	copy(buf[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(riff.size-8))
+	copy(buf[8:12], riff.form)
+	n, err = w.Write(buf[:])
+	count += n
+	if err != nil {
+		return count, err
+	}
+	for _, ch := range riff.all {
+		n, err = ch.write(w)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	if int(riff.size) != count {
+		panic("actual size different from predicted size")
+	}
+	return count, nil
+}
+
+// EXIF returns the contents of the EXIF chunk, if any.
+func (riff *RIFF) EXIF() metadata.Reader {
+	if riff.exif != nil {
+		return riff.exif.data
+	}
+	return nil
+}
+
+// XMP returns the contents of the XMP chunk, if any.
+func (riff *RIFF) XMP() metadata.Reader {
+	if riff.xmp != nil {
+		return riff.xmp.data
+	}
+	return nil
+}
+
+// SetEXIFContainer sets the contents of the EXIF chunk to those provided by the
+// supplied container.
+func (riff *RIFF) SetEXIFContainer(c containers.Container) {
+	if riff.exif == nil {
+		riff.exif = &chunk{ctype: typeEXIF}
+	}
+	riff.exif.container = c
+}
+
+// SetXMPContainer sets the contents of the XMP chunk to those provided by the
+// supplied container.
+func (riff *RIFF) SetXMPContainer(c containers.Container) {
+	if riff.xmp == nil {
+		riff.xmp = &chunk{ctype: typeXMP}
+	}
+	riff.xmp.container = c
+}
+
+// dirty returns whether the chunk has been changed.
+func (ch *chunk) dirty() bool {
+	if ch == nil || ch.container == nil {
+		return false
+	}
+	return ch.container.Dirty()
+}
+
+// layout computes the rendered size of the chunk, including its header and
+// padding.
+func (ch *chunk) layout() int64 {
+	switch {
+	case ch.literal != nil:
+		ch.rewrite = true
+		ch.csize = int64(len(ch.literal))
+	case ch.container == nil || !ch.container.Dirty() && ch.raw != nil:
+		ch.rewrite = false
+		return ch.raw.Size()
+	default:
+		ch.rewrite = true
+		ch.csize = ch.container.Layout()
+	}
+	return 8 + ch.csize + ch.csize&1
+}
+
+// write writes the chunk to the specified writer.
+func (ch *chunk) write(w io.Writer) (count int, err error) {
+	var (
+		buf [8]byte
+		n   int
+		n64 int64
+	)
+	if !ch.rewrite {
+		ch.raw.Seek(0, io.SeekStart)
+		n64, err = io.Copy(w, ch.raw)
+		return int(n64), err
+	}
+	copy(buf[0:4], ch.ctype)
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(ch.csize))
+	if n, err = w.Write(buf[:]); err != nil {
+		return n, err
+	}
+	count += n
+	if ch.literal != nil {
+		n, err = w.Write(ch.literal)
+	} else {
+		n, err = ch.container.Write(w)
+	}
+	count += n
+	if err != nil {
+		return count, err
+	}
+	if int64(n) != ch.csize {
+		panic("actual size different from predicted size")
+	}
+	if ch.csize&1 != 0 {
+		n, err = w.Write([]byte{0})
+		count += n
+	}
+	return count, err
+}
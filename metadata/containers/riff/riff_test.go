@@ -0,0 +1,118 @@
+package riff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/raw"
+)
+
+// makeChunk returns the bytes of a RIFF chunk with the specified type and
+// data, including any padding.
+func makeChunk(ctype string, data []byte) []byte {
+	var by = make([]byte, 8, len(data)+9)
+	copy(by[0:4], ctype)
+	binary.LittleEndian.PutUint32(by[4:8], uint32(len(data)))
+	by = append(by, data...)
+	if len(data)%2 != 0 {
+		by = append(by, 0)
+	}
+	return by
+}
+
+func makeWebP(chunks ...[]byte) []byte {
+	var by = []byte("RIFF\000\000\000\000WEBP")
+	for _, chunk := range chunks {
+		by = append(by, chunk...)
+	}
+	binary.LittleEndian.PutUint32(by[4:8], uint32(len(by)-8))
+	return by
+}
+
+// testVP8L is a lossless bitstream header for a 3x2 image with alpha.
+var testVP8L = makeChunk("VP8L", []byte{0x2F, 0x02, 0x40, 0x00, 0x10, 0xAA})
+
+func rewrite(t *testing.T, input []byte, exif, xmp []byte) []byte {
+	var (
+		r   RIFF
+		buf bytes.Buffer
+	)
+	if err := r.Read(bytes.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if exif != nil {
+		var c raw.Raw
+		if r.EXIF() != nil {
+			c.Read(r.EXIF())
+		}
+		r.SetEXIFContainer(&c)
+		c.SetData(exif)
+	}
+	if xmp != nil {
+		var c raw.Raw
+		if r.XMP() != nil {
+			c.Read(r.XMP())
+		}
+		r.SetXMPContainer(&c)
+		c.SetData(xmp)
+	}
+	r.Layout()
+	if _, err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadWriteUnchanged(t *testing.T) {
+	var (
+		vp8x  = makeChunk("VP8X", []byte{0x18, 0, 0, 0, 2, 0, 0, 1, 0, 0})
+		input = makeWebP(vp8x, testVP8L, makeChunk("EXIF", []byte("Exif\000\000MMM")))
+		r     RIFF
+	)
+	if err := r.Read(bytes.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if exif, _ := io.ReadAll(r.EXIF()); string(exif) != "MMM" {
+		t.Errorf("EXIF: got %q, expected \"MMM\"", exif)
+	}
+	if r.XMP() != nil {
+		t.Error("XMP: got data, expected nil")
+	}
+	if out := rewrite(t, input, []byte("MMM"), nil); !bytes.Equal(out, input) {
+		t.Error("output differs from input")
+	}
+}
+
+func TestAddToSimple(t *testing.T) {
+	out := rewrite(t, makeWebP(testVP8L), nil, []byte("<x/>"))
+	expected := makeWebP(makeChunk("VP8X", []byte{0x14, 0, 0, 0, 2, 0, 0, 1, 0, 0}), testVP8L, makeChunk("XMP ", []byte("<x/>")))
+	if !bytes.Equal(out, expected) {
+		t.Errorf("got %q, expected %q", out, expected)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	var (
+		input = makeWebP(makeChunk("VP8X", []byte{0x1C, 0, 0, 0, 2, 0, 0, 1, 0, 0}), testVP8L,
+			makeChunk("EXIF", []byte("MM")), makeChunk("XMP ", []byte("<x/>")))
+		expected = makeWebP(makeChunk("VP8X", []byte{0x14, 0, 0, 0, 2, 0, 0, 1, 0, 0}), testVP8L,
+			makeChunk("XMP ", []byte("<y/>")))
+	)
+	if out := rewrite(t, input, []byte{}, []byte("<y/>")); !bytes.Equal(out, expected) {
+		t.Errorf("got %q, expected %q", out, expected)
+	}
+}
+
+func TestAddEXIFBeforeXMP(t *testing.T) {
+	var (
+		input = makeWebP(makeChunk("VP8X", []byte{0x14, 0, 0, 0, 2, 0, 0, 1, 0, 0}), testVP8L,
+			makeChunk("XMP ", []byte("<x/>")))
+		expected = makeWebP(makeChunk("VP8X", []byte{0x1C, 0, 0, 0, 2, 0, 0, 1, 0, 0}), testVP8L,
+			makeChunk("EXIF", []byte("MM")), makeChunk("XMP ", []byte("<x/>")))
+	)
+	if out := rewrite(t, input, []byte("MM"), nil); !bytes.Equal(out, expected) {
+		t.Errorf("got %q, expected %q", out, expected)
+	}
+}
@@ -1,6 +1,7 @@
 package tiff
 
 import (
+	"encoding/binary"
 	"errors"
 	"io"
 	"sort"
@@ -110,11 +111,11 @@ func (ifd *IFD) Layout() int64 {
 			continue
 		}
 		ifd.size += 12
-		if tsz, _ := tag.size(); tsz > 4 {
+		if dsz := tag.dataSize(); dsz != 0 {
 			if ifd.size%2 == 1 {
 				ifd.size++
 			}
-			ifd.size += int64(tsz)
+			ifd.size += int64(dsz)
 		}
 		ifd.tags[j] = tag
 		j++
@@ -160,7 +161,7 @@ func (ifd *IFD) Write(w io.Writer) (count int, err error) {
 		return count, err
 	}
 	for _, tag := range ifd.tags {
-		if tsz, _ := tag.size(); count%2 == 1 && tsz > 4 {
+		if count%2 == 1 && tag.dataSize() != 0 {
 			n, err = w.Write([]byte{0})
 			count += n
 			if err != nil {
@@ -179,6 +180,10 @@ func (ifd *IFD) Write(w io.Writer) (count int, err error) {
 	return count, nil
 }
 
+// Encoding returns the byte order of the IFD.  This is usually the byte order
+// of the entire TIFF block, but some MakerNote IFDs have their own.
+func (ifd *IFD) Encoding() binary.ByteOrder { return ifd.t.enc }
+
 // Tag returns the specified tag from the IFD, or nil if it doesn't exist in the
 // IFD.
 func (ifd *IFD) Tag(id uint16) *Tag {
@@ -253,6 +258,15 @@ func (ifd *IFD) NextIFD() (next *IFD, err error) {
 	return ifd.nextIFD, nil
 }
 
+// RemoveNextIFD removes the "next" IFD after the receiver, along with any IFDs
+// that follow it.
+func (ifd *IFD) RemoveNextIFD() {
+	if ifd.nextIFD != nil || ifd.next != 0 {
+		ifd.dirty = true
+	}
+	ifd.nextIFD, ifd.next = nil, 0
+}
+
 // AddNextIFD adds an IFD as the "next" IFD after the receiver.  If the receiver
 // already has a "next" IFD, the existing one is returned.
 func (ifd *IFD) AddNextIFD() (next *IFD, err error) {
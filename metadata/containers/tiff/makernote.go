@@ -0,0 +1,170 @@
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	tagMake      uint16 = 0x010F
+	tagMakerNote uint16 = 0x927C
+)
+
+// A makerNoteFormat describes the layout of one vendor's MakerNote.  Most
+// MakerNotes are IFDs, sometimes preceded by an identifying header.  Some
+// vendors' IFDs use offsets relative to the start of the MakerNote (or to a
+// TIFF header inside it); those can be moved freely.  Others use offsets
+// relative to the start of the enclosing TIFF block; those need every offset
+// adjusted when the MakerNote moves.
+type makerNoteFormat struct {
+	vendor   string
+	prefix   string
+	ifdStart int  // offset of the IFD in the MakerNote
+	relative bool // offsets are relative to the MakerNote
+	header   int  // offset of an embedded TIFF header, if any
+	orderAt  int  // offset of a byte order mark, if any
+	intel    bool // always little-endian
+}
+
+var makerNoteFormats = []makerNoteFormat{
+	{vendor: "Nikon", prefix: "Nikon\000\002", relative: true, header: 10},
+	{vendor: "Olympus", prefix: "OLYMPUS\000", ifdStart: 12, relative: true, orderAt: 8},
+	{vendor: "OM System", prefix: "OM SYSTEM\000", ifdStart: 16, relative: true, orderAt: 12},
+	{vendor: "Fujifilm", prefix: "FUJIFILM", ifdStart: 12, relative: true, intel: true},
+	{vendor: "Apple", prefix: "Apple iOS\000", ifdStart: 14, relative: true, orderAt: 12},
+	{vendor: "Olympus", prefix: "OLYMP\000", ifdStart: 8},
+	{vendor: "Sony", prefix: "SONY DSC \000\000\000", ifdStart: 12},
+	{vendor: "Sony", prefix: "SONY CAM \000\000\000", ifdStart: 12},
+	{vendor: "Panasonic", prefix: "Panasonic\000\000\000", ifdStart: 12},
+}
+
+// canonMakerNote is the format of Canon MakerNotes, which have no header, and
+// are recognized by the camera make in IFD0.
+var canonMakerNote = makerNoteFormat{vendor: "Canon"}
+
+// makerNoteFormatOf returns the format of the supplied MakerNote, or nil if
+// it isn't a known format.
+func (t *TIFF) makerNoteFormatOf(mn []byte) *makerNoteFormat {
+	for i := range makerNoteFormats {
+		if bytes.HasPrefix(mn, []byte(makerNoteFormats[i].prefix)) {
+			return &makerNoteFormats[i]
+		}
+	}
+	if mk := t.ifd0.Tag(tagMake); mk != nil {
+		if s, err := mk.AsString(); err == nil && strings.HasPrefix(s, "Canon") {
+			return &canonMakerNote
+		}
+	}
+	return nil
+}
+
+// relocateMakerNote returns a copy of the supplied MakerNote, which was at
+// offset from, adjusted to be written at offset to.  It returns an error if
+// the MakerNote is of an unknown format, or if it can't be adjusted safely.
+func (t *TIFF) relocateMakerNote(mn []byte, from, to uint32) (out []byte, err error) {
+	var format = t.makerNoteFormatOf(mn)
+
+	if format == nil {
+		return nil, errors.New("MakerNote of unknown format can't be moved")
+	}
+	out = make([]byte, len(mn))
+	copy(out, mn)
+	if format.relative {
+		return out, nil
+	}
+	dir := format.ifdStart
+	if dir+2 > len(out) {
+		return nil, errors.New("MakerNote IFD is truncated")
+	}
+	count := int(t.enc.Uint16(out[dir:]))
+	if dir+2+12*count > len(out) {
+		return nil, errors.New("MakerNote IFD is truncated")
+	}
+	for i := 0; i < count; i++ {
+		var (
+			entry = out[dir+2+12*i:]
+			unit  uint32
+		)
+		switch t.enc.Uint16(entry[2:4]) {
+		case 1, 2, 6, 7:
+			unit = 1
+		case 3, 8:
+			unit = 2
+		case 4, 9, 11, 13:
+			unit = 4
+		case 5, 10, 12:
+			unit = 8
+		default:
+			return nil, fmt.Errorf("MakerNote tag %x has unknown type", t.enc.Uint16(entry[0:2]))
+		}
+		if unit*t.enc.Uint32(entry[4:8]) <= 4 {
+			continue
+		}
+		// Only offsets pointing within the MakerNote itself can be
+		// adjusted; anything else would be left dangling.
+		offset := t.enc.Uint32(entry[8:12])
+		if offset < from || offset >= from+uint32(len(mn)) {
+			return nil, fmt.Errorf("MakerNote tag %x has data outside the MakerNote", t.enc.Uint16(entry[0:2]))
+		}
+		t.enc.PutUint32(entry[8:12], offset-from+to)
+	}
+	return out, nil
+}
+
+// AsMakerNote parses the MakerNote in the tag.  It returns the name of the
+// camera vendor that wrote it, and the IFD that it contains.  The IFD may only
+// be read, not changed.  AsMakerNote returns an error if the MakerNote is of
+// an unknown format or can't be parsed, or if the tag value has been changed
+// since it was read.
+func (tag *Tag) AsMakerNote() (vendor string, ifd *IFD, err error) {
+	var (
+		mn     []byte
+		format *makerNoteFormat
+		start  int64
+		mt     TIFF
+	)
+	if tag.pinned == nil || tag.reader != tag.pinned {
+		return "", nil, errors.New("tag is not an unchanged MakerNote")
+	}
+	if mn, err = tag.AsUnknown(); err != nil {
+		return "", nil, err
+	}
+	if format = tag.ifd.t.makerNoteFormatOf(mn); format == nil {
+		return "", nil, errors.New("MakerNote of unknown format")
+	}
+	switch {
+	case format.header != 0:
+		if err = mt.Read(io.NewSectionReader(tag.pinned, int64(format.header), int64(len(mn)-format.header))); err != nil {
+			return "", nil, fmt.Errorf("%s MakerNote: %s", format.vendor, err)
+		}
+		return format.vendor, mt.ifd0, nil
+	case !format.relative:
+		mt.r, mt.enc = tag.ifd.t.r, tag.ifd.t.enc
+		start = int64(tag.doff)
+	default:
+		mt.r, mt.enc = tag.pinned, tag.ifd.t.enc
+	}
+	switch {
+	case format.intel:
+		mt.enc = binary.LittleEndian
+	case format.orderAt != 0 && len(mn) >= format.orderAt+2:
+		switch string(mn[format.orderAt : format.orderAt+2]) {
+		case "II":
+			mt.enc = binary.LittleEndian
+		case "MM":
+			mt.enc = binary.BigEndian
+		}
+	}
+	if _, err = mt.r.Seek(start+int64(format.ifdStart), io.SeekStart); err != nil {
+		return "", nil, err
+	}
+	mt.ifd0 = &IFD{t: &mt}
+	if err = mt.ifd0.Read(mt.r); err != nil {
+		return "", nil, fmt.Errorf("%s MakerNote: %s", format.vendor, err)
+	}
+	return format.vendor, mt.ifd0, nil
+}
@@ -52,6 +52,17 @@ func (r *rangelist) add(start, end uint32) error {
 	return nil
 }
 
+// overlaps returns whether the range [start, end) overlaps any range in the
+// rangelist.
+func (r *rangelist) overlaps(start, end uint32) bool {
+	for idx := 0; idx < len(r.r); idx += 2 {
+		if r.r[idx] < end && start < r.r[idx+1] {
+			return true
+		}
+	}
+	return false
+}
+
 // removeTrailer checks to see whether the last range is up against the end of
 // the file.  If so, it removes it from the list, and returns the start of that
 // range, which becomes our new end of file for rendering purposes.
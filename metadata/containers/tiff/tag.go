@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"unicode/utf16"
 	"unicode/utf8"
 
 	"github.com/rothskeller/photo-tools/metadata"
@@ -25,6 +26,18 @@ type Tag struct {
 	reader    metadata.Reader
 	container containers.Container
 	toIFD     *IFD
+	// For a MakerNote tag, pinned is the reader for the original data.  As
+	// long as the tag still has that reader, the data are left at their
+	// original offset (keep is true), or if that isn't possible, relocated
+	// to newoff with vendor-specific offset adjustments.
+	pinned metadata.Reader
+	keep   bool
+	newoff uint32
+	// For a tag whose value is the offset of a block of data elsewhere in
+	// the TIFF (e.g., JPEGInterchangeFormat), pointee is that block of
+	// data.  It is written along with the IFD's data, and the tag value is
+	// set to its offset.
+	pointee metadata.Reader
 }
 
 // Read reads a single tag from the reader.  On entry, the file pointer should
@@ -48,7 +61,7 @@ func (tag *Tag) Read(r metadata.Reader) (err error) {
 		size = 1
 	case 3, 8:
 		size = 2
-	case 4, 9:
+	case 4, 9, 13:
 		size = 4
 	case 5, 10:
 		size = 8
@@ -62,6 +75,14 @@ func (tag *Tag) Read(r metadata.Reader) (err error) {
 		tag.doff = 0
 		return nil
 	}
+	if tag.tag == tagMakerNote {
+		// MakerNotes often contain offsets relative to the start of
+		// the TIFF block, so we leave them where they are rather than
+		// marking their range as available.
+		tag.reader = io.NewSectionReader(r, int64(tag.doff), int64(size))
+		tag.pinned = tag.reader
+		return nil
+	}
 	if size%2 == 1 {
 		tag.ifd.t.ranges.add(tag.doff, tag.doff+size+1)
 	} else {
@@ -188,6 +209,28 @@ func (tag *Tag) AsLongReader() (metadata.Reader, error) {
 	return bytes.NewReader(tag.data), nil
 }
 
+// AsLongs decodes the long integers in the tag.  Signed long integers are
+// returned as their unsigned equivalents.  It returns an error if the tag has
+// the wrong type.
+func (tag *Tag) AsLongs() (longs []uint32, err error) {
+	var by = tag.data
+
+	if tag.ttype != 4 && tag.ttype != 9 {
+		return nil, errors.New("tag type is not LONG or SLONG")
+	}
+	if tag.reader != nil {
+		by = make([]byte, tag.reader.Size())
+		if _, err = tag.reader.ReadAt(by, 0); err != nil {
+			return nil, err
+		}
+	}
+	longs = make([]uint32, len(by)/4)
+	for i := range longs {
+		longs[i] = tag.ifd.t.enc.Uint32(by[4*i:])
+	}
+	return longs, nil
+}
+
 // AsShort decodes the short integer in the tag.  It returns an error if the tag
 // has the wrong type.
 func (tag *Tag) AsShort() (int, error) {
@@ -216,6 +259,30 @@ func (tag *Tag) SetShort(v int) {
 	tag.ifd.dirty = true
 }
 
+// AsUnsigned decodes the first unsigned integer in a SHORT or LONG tag.  Some
+// tags are allowed to have either type.  It returns an error if the tag has
+// the wrong type or is empty.
+func (tag *Tag) AsUnsigned() (uint32, error) {
+	switch tag.ttype {
+	case 3:
+		if len(tag.data) < 2 {
+			return 0, errors.New("tag is empty")
+		}
+		return uint32(tag.ifd.t.enc.Uint16(tag.data)), nil
+	case 4:
+		longs, err := tag.AsLongs()
+		if err != nil {
+			return 0, err
+		}
+		if len(longs) == 0 {
+			return 0, errors.New("tag is empty")
+		}
+		return longs[0], nil
+	default:
+		return 0, errors.New("tag type is not SHORT or LONG")
+	}
+}
+
 // AsString decodes the string in the tag.  It returns an error if the tag has
 // the wrong type or the character encoding can't be guessed.
 func (tag *Tag) AsString() (string, error) {
@@ -251,6 +318,38 @@ func (tag *Tag) SetString(s string) {
 	tag.ifd.dirty = true
 }
 
+// AsUCS2 decodes the string in a Windows XP* tag, which is a BYTE array
+// containing UTF-16LE text (regardless of the byte order of the TIFF block).
+// It returns an error if the tag has the wrong type.
+func (tag *Tag) AsUCS2() (string, error) {
+	by, err := tag.AsBytes()
+	if err != nil {
+		return "", err
+	}
+	var units = make([]uint16, 0, len(by)/2)
+	for i := 0; i+1 < len(by); i += 2 {
+		u := uint16(by[i]) | uint16(by[i+1])<<8
+		if u == 0 { // trailing NUL
+			break
+		}
+		units = append(units, u)
+	}
+	return strings.TrimSpace(string(utf16.Decode(units))), nil
+}
+
+// SetUCS2 sets the value of a Windows XP* tag to the specified string.
+func (tag *Tag) SetUCS2(s string) {
+	if old, err := tag.AsUCS2(); err == nil && old == s {
+		return
+	}
+	var units = append(utf16.Encode([]rune(s)), 0)
+	var encoded = make([]byte, 2*len(units))
+	for i, u := range units {
+		encoded[2*i], encoded[2*i+1] = byte(u), byte(u>>8)
+	}
+	tag.SetBytes(encoded)
+}
+
 // AsRationals decodes the rationals in the tag.  It returns them as an even
 // number of uint32s, alternating numerators and denominators.  It returns an
 // error if the tag has the wrong type.
@@ -306,8 +405,8 @@ func (tag *Tag) AsIFD() (ifd *IFD, err error) {
 	if tag.toIFD != nil {
 		return tag.toIFD, nil
 	}
-	if tag.ttype != 4 {
-		return nil, errors.New("tag type is not LONG")
+	if tag.ttype != 4 && tag.ttype != 13 {
+		return nil, errors.New("tag type is not LONG or IFD")
 	}
 	if len(tag.data) != 4 {
 		return nil, errors.New("tag count is not 1")
@@ -378,6 +477,11 @@ func (tag *Tag) write(w io.Writer, offset uint32) (newoff uint32, count int, err
 	switch {
 	case tag.toIFD != nil:
 		tag.ifd.t.enc.PutUint32(buf[8:12], tag.toIFD.offset)
+	case tag.pointee != nil:
+		tag.ifd.t.enc.PutUint32(buf[8:12], offset)
+		offset += uint32(tag.pointee.Size())
+	case tag.keep:
+		tag.ifd.t.enc.PutUint32(buf[8:12], tag.doff)
 	case size <= 4:
 		if tag.container != nil {
 			panic("not expecting container of size <= 4")
@@ -385,6 +489,7 @@ func (tag *Tag) write(w io.Writer, offset uint32) (newoff uint32, count int, err
 		copy(buf[8:12], tag.data)
 	default:
 		tag.ifd.t.enc.PutUint32(buf[8:12], offset)
+		tag.newoff = offset
 		offset += size
 	}
 	count, err = w.Write(buf[0:12])
@@ -394,10 +499,28 @@ func (tag *Tag) write(w io.Writer, offset uint32) (newoff uint32, count int, err
 // writeData writes the data associated with the tag.  It's a no-op if the data
 // were included in the IFD entry.
 func (tag *Tag) writeData(w io.Writer) (count int, err error) {
+	if tag.pointee != nil {
+		if _, err = tag.pointee.Seek(0, io.SeekStart); err != nil {
+			return 0, err
+		}
+		n64, err := io.Copy(w, tag.pointee)
+		return int(n64), err
+	}
 	size, _ := tag.size()
-	if size <= 4 { // data was embedded in IFD entry, nothing to write
+	if size <= 4 || tag.keep { // data was embedded in IFD entry or left in place, nothing to write
 		return 0, nil
 	}
+	if tag.pinned != nil && tag.reader == tag.pinned {
+		var by = make([]byte, size)
+
+		if _, err = tag.reader.ReadAt(by, 0); err != nil {
+			return 0, err
+		}
+		if by, err = tag.ifd.t.relocateMakerNote(by, tag.doff, tag.newoff); err != nil {
+			return 0, err
+		}
+		return w.Write(by)
+	}
 	if tag.container != nil {
 		if count, err = tag.container.Write(w); err != nil {
 			return count, err
@@ -422,6 +545,18 @@ func (tag *Tag) writeData(w io.Writer) (count int, err error) {
 	return w.Write(tag.data)
 }
 
+// dataSize returns the size of the data written by writeData, i.e., the data
+// associated with the tag that are written after the IFD entries.
+func (tag *Tag) dataSize() uint32 {
+	if tag.pointee != nil {
+		return uint32(tag.pointee.Size())
+	}
+	if size, _ := tag.size(); size > 4 && !tag.keep {
+		return size
+	}
+	return 0
+}
+
 // size computes the size of the encoded tag's data.  It does not include any
 // alignment padding at the end, but it does ensure that the result is a
 // multiple of the underlying data type of the tag.  It returns both the size
@@ -434,7 +569,7 @@ func (tag *Tag) size() (size, count uint32) {
 		unit = 1
 	case 3, 8:
 		unit = 2
-	case 4, 9:
+	case 4, 9, 13:
 		unit = 4
 	case 5, 10:
 		unit = 8
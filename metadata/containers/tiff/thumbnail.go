@@ -0,0 +1,100 @@
+package tiff
+
+import (
+	"errors"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+const (
+	tagCompression                 uint16 = 0x103
+	tagXResolution                 uint16 = 0x11A
+	tagYResolution                 uint16 = 0x11B
+	tagResolutionUnit              uint16 = 0x128
+	tagJPEGInterchangeFormat       uint16 = 0x201
+	tagJPEGInterchangeFormatLength uint16 = 0x202
+)
+
+// Thumbnail returns the JPEG thumbnail image in IFD1 of an EXIF block, or nil
+// if there is none.
+func (t *TIFF) Thumbnail() (thumb metadata.Reader, err error) {
+	var (
+		ifd1   *IFD
+		offset uint32
+		length uint32
+	)
+	if ifd1, err = t.IFD0().NextIFD(); err != nil || ifd1 == nil {
+		return nil, err
+	}
+	otag, ltag := ifd1.Tag(tagJPEGInterchangeFormat), ifd1.Tag(tagJPEGInterchangeFormatLength)
+	if otag == nil || ltag == nil {
+		return nil, nil
+	}
+	if otag.pointee != nil {
+		return otag.pointee, nil
+	}
+	if offset, err = otag.AsUnsigned(); err != nil {
+		return nil, err
+	}
+	if length, err = ltag.AsUnsigned(); err != nil {
+		return nil, err
+	}
+	if length == 0 {
+		return nil, nil
+	}
+	if int64(offset)+int64(length) > t.r.Size() {
+		return nil, errors.New("thumbnail extends past end of EXIF block")
+	}
+	// From now on, the thumbnail is written with IFD1 and the offset is
+	// recomputed whenever the block is laid out.  Its original space is
+	// available for reuse, unless something else is there too.
+	otag.pointee = io.NewSectionReader(t.r, int64(offset), int64(length))
+	if length%2 == 1 {
+		length++
+	}
+	t.ranges.add(offset, offset+length)
+	return otag.pointee, nil
+}
+
+// SetThumbnail sets the JPEG thumbnail image in IFD1 of an EXIF block,
+// creating IFD1 if necessary.  If thumb is nil, IFD1 is removed.
+func (t *TIFF) SetThumbnail(thumb metadata.Reader) (err error) {
+	var ifd1 *IFD
+
+	if _, err = t.Thumbnail(); err != nil {
+		return err
+	}
+	if thumb == nil || thumb.Size() == 0 {
+		t.IFD0().RemoveNextIFD()
+		return nil
+	}
+	if ifd1, err = t.IFD0().NextIFD(); err != nil {
+		return err
+	}
+	if ifd1 == nil {
+		// EXIF requires these tags in IFD1.
+		ifd1, _ = t.IFD0().AddNextIFD()
+		ifd1.AddTag(tagCompression, 3).SetShort(6) // JPEG
+		ifd1.AddTag(tagXResolution, 5).SetRationals([]uint32{72, 1})
+		ifd1.AddTag(tagYResolution, 5).SetRationals([]uint32{72, 1})
+		ifd1.AddTag(tagResolutionUnit, 3).SetShort(2) // inches
+	}
+	ifd1.AddTag(tagJPEGInterchangeFormat, 4).setLong(0)
+	ifd1.AddTag(tagJPEGInterchangeFormatLength, 4).setLong(uint32(thumb.Size()))
+	ifd1.Tag(tagJPEGInterchangeFormat).pointee = thumb
+	ifd1.dirty = true
+	return nil
+}
+
+// setLong sets the tag value to the specified long integer.
+func (tag *Tag) setLong(v uint32) {
+	var encoded [4]byte
+	tag.ifd.t.enc.PutUint32(encoded[:], v)
+	tag.ttype = 4 // LONG
+	tag.data = encoded[:]
+	tag.container = nil
+	tag.reader = nil
+	tag.toIFD = nil
+	tag.ifd.dirty = true
+}
@@ -21,6 +21,7 @@ type TIFF struct {
 	ranges rangelist
 	ifds   []*IFD
 	end    uint32
+	err    error
 }
 
 var _ containers.Container = (*TIFF)(nil) // verify interface compliance
@@ -76,6 +77,15 @@ func (t *TIFF) Dirty() bool {
 // Layout computes the rendered layout of the container, i.e. prepares for a
 // call to Write, and returns what the rendered size of the container will be.
 func (t *TIFF) Layout() int64 {
+	// Get a list of all of the IFDs to be rendered.  Determine whether any
+	// MakerNotes in them can stay where they are.
+	t.ifds = findAllIFDs(nil, t.ifd0)
+	t.err = nil
+	for _, ifd := range t.ifds {
+		for _, tag := range ifd.tags {
+			t.layoutMakerNote(tag)
+		}
+	}
 	// Set the end pointer to the end of the file.  If there's a consumable
 	// range that ends at the end of the file, drop it and set the end
 	// pointer to the start of that range.
@@ -84,9 +94,7 @@ func (t *TIFF) Layout() int64 {
 		t.end = t.ranges.r[len(t.ranges.r)-2]
 		t.ranges.r = t.ranges.r[:len(t.ranges.r)-2]
 	}
-	// Get a list of all of the IFDs to be rendered, in decreasing order by
-	// size.
-	t.ifds = findAllIFDs(nil, t.ifd0)
+	// Sort the IFDs in decreasing order by size.
 	for i := range t.ifds {
 		t.ifds[i].size = t.ifds[i].Layout()
 	}
@@ -110,12 +118,39 @@ func (t *TIFF) Layout() int64 {
 	return int64(t.end)
 }
 
+// layoutMakerNote determines whether an unchanged MakerNote can be left at its
+// original offset.  That's possible unless some other data that is being laid
+// out anew overlapped it.  If it isn't possible, the MakerNote will be moved,
+// and layoutMakerNote verifies that it knows how to adjust the MakerNote's
+// internal offsets to match; it records an error (returned by Write) if not,
+// rather than corrupt the MakerNote.
+func (t *TIFF) layoutMakerNote(tag *Tag) {
+	tag.keep = false
+	if tag.pinned == nil || tag.reader != tag.pinned {
+		return
+	}
+	size := uint32(tag.pinned.Size())
+	if !t.ranges.overlaps(tag.doff, tag.doff+size) {
+		tag.keep = true
+		return
+	}
+	var by = make([]byte, size)
+	if _, err := tag.pinned.ReadAt(by, 0); err != nil {
+		t.err = fmt.Errorf("TIFF: MakerNote: %s", err)
+	} else if _, err = t.relocateMakerNote(by, tag.doff, tag.doff); err != nil {
+		t.err = fmt.Errorf("TIFF: %s", err)
+	}
+}
+
 // Write writes the rendered container to the specified writer.
 func (t *TIFF) Write(w io.Writer) (count int, err error) {
 	var (
 		n   int
 		buf [8]byte
 	)
+	if t.err != nil {
+		return 0, t.err
+	}
 	// Write the TIFF header.
 	if t.enc == binary.BigEndian {
 		copy(buf[:], tiffHeaderBE)
@@ -194,11 +229,17 @@ func (t *TIFF) Write(w io.Writer) (count int, err error) {
 	return count, nil
 }
 
-// IFD0 returns the first IFD in the TIFF-like block.
+// IFD0 returns the first IFD in the TIFF-like block.  If the block was never
+// read (i.e., it is being newly created), it will be written in big-endian
+// byte order.
 func (t *TIFF) IFD0() *IFD {
 	if t.ifd0 == nil {
 		t.ifd0 = &IFD{t: t}
 	}
+	if t.r == nil {
+		t.r = bytes.NewReader(make([]byte, 8))
+		t.enc = binary.BigEndian
+	}
 	return t.ifd0
 }
 
@@ -2,6 +2,7 @@ package tiff
 
 import (
 	"bytes"
+	"encoding/binary"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -292,3 +293,251 @@ func TestRangeCoalesce(t *testing.T) {
 		t.Error("fail")
 	}
 }
+
+var testMakerNoteInput = []byte{
+	/* 0000 */ 0x4D, 0x4D, 0x00, 0x2A, // header, big-endian
+	/* 0004 */ 0x00, 0x00, 0x00, 0x08, // pointer to IFD0
+	/* 0008 */ 0x00, 0x03, // 3 tags in IFD0
+	/* 000A */ 0x01, 0x0F, 0x00, 0x02, 0x00, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x32, // Make, "Canon"
+	/* 0016 */ 0x01, 0x31, 0x00, 0x02, 0x00, 0x00, 0x00, 0x08, 0x00, 0x00, 0x00, 0x38, // Software, "Program"
+	/* 0022 */ 0x87, 0x69, 0x00, 0x04, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x40, // ptr to EXIF IFD
+	/* 002E */ 0x00, 0x00, 0x00, 0x00, // no next pointer
+	/* 0032 */ 'C', 'a', 'n', 'o', 'n', 0x00, // Make data
+	/* 0038 */ 'P', 'r', 'o', 'g', 'r', 'a', 'm', 0x00, // Software data
+	/* 0040 */ 0x00, 0x01, // 1 tag in EXIF IFD
+	/* 0042 */ 0x92, 0x7C, 0x00, 0x07, 0x00, 0x00, 0x00, 0x1A, 0x00, 0x00, 0x00, 0x52, // MakerNote
+	/* 004E */ 0x00, 0x00, 0x00, 0x00, // no next pointer
+	/* 0052 */ 0x00, 0x01, // 1 tag in MakerNote IFD
+	/* 0054 */ 0x00, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00, 0x64, // tag 1, 4 shorts
+	/* 0060 */ 0x00, 0x00, 0x00, 0x00, // no next pointer
+	/* 0064 */ 0x00, 0x01, 0x00, 0x02, 0x00, 0x03, 0x00, 0x04, // tag 1 data
+}
+
+// makerNoteTest returns a copy of testMakerNoteInput with the specified camera
+// make.  If overlap is true, the Software tag's data are moved to overlap the
+// MakerNote, so that the MakerNote can't stay in place.  It then adds a tag to
+// the EXIF IFD, rewrites the file, and returns the result.
+func makerNoteTest(make string, overlap bool) (out []byte, err error) {
+	var (
+		tl  TIFF
+		buf bytes.Buffer
+	)
+	in := append([]byte{}, testMakerNoteInput...)
+	copy(in[0x32:0x37], make)
+	if overlap {
+		in[0x21] = 0x64
+	}
+	if err = tl.Read(bytes.NewReader(in)); err != nil {
+		return nil, err
+	}
+	exif, _ := tl.IFD0().Tag(0x8769).AsIFD()
+	exif.AddTag(0x9003, 2).SetString("2001:02:03 04:05:06")
+	tl.Layout()
+	if _, err = tl.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readMakerNote returns the offset and contents of the MakerNote in the
+// specified file.
+func readMakerNote(t *testing.T, file []byte) (offset uint32, by []byte) {
+	var tl TIFF
+
+	if err := tl.Read(bytes.NewReader(file)); err != nil {
+		t.Fatal(err)
+	}
+	exif, err := tl.IFD0().Tag(0x8769).AsIFD()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := exif.Tag(tagMakerNote)
+	if tag == nil {
+		t.Fatal("no MakerNote")
+	}
+	if by, err = tag.AsUnknown(); err != nil {
+		t.Fatal(err)
+	}
+	return tag.doff, by
+}
+
+func TestMakerNoteKept(t *testing.T) {
+	out, err := makerNoteTest("Canon", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offset, by := readMakerNote(t, out)
+	if offset != 0x52 {
+		t.Errorf("MakerNote moved to %x", offset)
+	}
+	if !bytes.Equal(by, testMakerNoteInput[0x52:]) {
+		spew.Dump(by)
+		t.Error("MakerNote changed")
+	}
+}
+
+func TestMakerNoteRelocated(t *testing.T) {
+	out, err := makerNoteTest("Canon", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offset, by := readMakerNote(t, out)
+	if offset == 0x52 {
+		t.Fatal("MakerNote not moved")
+	}
+	if got := binary.BigEndian.Uint32(by[0x0A:0x0E]); got != offset+0x12 {
+		t.Errorf("MakerNote tag 1 offset is %x, expected %x", got, offset+0x12)
+	}
+	if !bytes.Equal(out[offset+0x12:offset+0x1A], testMakerNoteInput[0x64:0x6C]) {
+		t.Error("MakerNote tag 1 data changed")
+	}
+}
+
+func TestMakerNoteUnknown(t *testing.T) {
+	if _, err := makerNoteTest("Nokia", true); err == nil {
+		t.Error("expected error for unknown MakerNote that must move")
+	}
+	if _, err := makerNoteTest("Nokia", false); err != nil {
+		t.Errorf("unknown MakerNote that needn't move: %s", err)
+	}
+}
+
+func TestAsMakerNote(t *testing.T) {
+	var tl TIFF
+
+	if err := tl.Read(bytes.NewReader(testMakerNoteInput)); err != nil {
+		t.Fatal(err)
+	}
+	exif, _ := tl.IFD0().Tag(0x8769).AsIFD()
+	vendor, ifd, err := exif.Tag(tagMakerNote).AsMakerNote()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vendor != "Canon" {
+		t.Errorf("vendor: got %q, expected \"Canon\"", vendor)
+	}
+	tag := ifd.Tag(1)
+	if tag == nil {
+		t.Fatal("no MakerNote tag 1")
+	}
+	if len(tag.data) != 8 || !bytes.Equal(tag.data, testMakerNoteInput[0x64:0x6C]) {
+		t.Errorf("MakerNote tag 1: got %v", tag.data)
+	}
+	// Reading the MakerNote must not make its space available for reuse.
+	if tl.ranges.overlaps(0x52, 0x6C) {
+		t.Error("MakerNote range marked free")
+	}
+}
+
+func TestAsUnsigned(t *testing.T) {
+	var tl TIFF
+	tl.Read(bytes.NewReader(testInput1))
+	tag := tl.IFD0().AddTag(9, 3)
+	tag.SetShort(40000)
+	if v, err := tag.AsUnsigned(); err != nil {
+		t.Fatalf("short %s", err)
+	} else if v != 40000 {
+		t.Errorf("short wrong value %d", v)
+	}
+	if _, err := tl.IFD0().Tag(1).AsUnsigned(); err == nil {
+		t.Errorf("bytes succeeded")
+	}
+}
+
+func TestAsUCS2(t *testing.T) {
+	var tl TIFF
+	tl.Read(bytes.NewReader(testInput1))
+	tag := tl.IFD0().AddTag(0x9C9B, 1)
+	// UTF-16LE even though the TIFF block is big-endian.
+	tag.SetBytes([]byte{'C', 0, 'a', 0, 'f', 0, 0xE9, 0, 0x3D, 0xD8, 0x00, 0xDE, 0, 0})
+	if s, err := tag.AsUCS2(); err != nil {
+		t.Fatal(err)
+	} else if s != "Café\U0001F600" {
+		t.Errorf("AsUCS2: got %q", s)
+	}
+	tag.SetUCS2("Hi")
+	if by, _ := tag.AsBytes(); !bytes.Equal(by, []byte{'H', 0, 'i', 0, 0, 0}) {
+		t.Errorf("SetUCS2: got % x", by)
+	}
+	if _, err := tl.IFD0().AddTag(9, 3).AsUCS2(); err == nil {
+		t.Errorf("short succeeded")
+	}
+}
+
+var testThumbInput = []byte{
+	/* 0000 */ 0x4D, 0x4D, 0x00, 0x2A, // header, big-endian
+	/* 0004 */ 0x00, 0x00, 0x00, 0x08, // pointer to IFD0
+	/* 0008 */ 0x00, 0x01, // 1 tag in IFD0
+	/* 000A */ 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x04, 0x31, 0x32, 0x33, 0x34, // tag 1, bytes '1234'
+	/* 0016 */ 0x00, 0x00, 0x00, 0x1A, // ptr to IFD1
+	/* 001A */ 0x00, 0x02, // 2 tags in IFD1
+	/* 001C */ 0x02, 0x01, 0x00, 0x04, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x38, // JPEGInterchangeFormat
+	/* 0028 */ 0x02, 0x02, 0x00, 0x04, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x05, // JPEGInterchangeFormatLength
+	/* 0034 */ 0x00, 0x00, 0x00, 0x00, // no next pointer
+	/* 0038 */ 'T', 'H', 'U', 'M', 'B', // thumbnail
+}
+
+// rewriteThumbTest lays out and writes the TIFF block, reads it back, and
+// returns its thumbnail.
+func rewriteThumbTest(t *testing.T, tl *TIFF) (thumb []byte, out *TIFF) {
+	var buf bytes.Buffer
+
+	tl.Layout()
+	if _, err := tl.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out = new(TIFF)
+	if err := out.Read(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	r, err := out.Thumbnail()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != nil {
+		thumb = make([]byte, r.Size())
+		r.ReadAt(thumb, 0)
+	}
+	return thumb, out
+}
+
+func TestThumbnail(t *testing.T) {
+	var tl TIFF
+	if err := tl.Read(bytes.NewReader(testThumbInput)); err != nil {
+		t.Fatal(err)
+	}
+	// Growing IFD0 moves it to the end; the thumbnail must still be found.
+	tl.IFD0().Tag(1).SetString("a much longer string value")
+	if thumb, _ := rewriteThumbTest(t, &tl); string(thumb) != "THUMB" {
+		t.Errorf("unchanged thumbnail: got %q", thumb)
+	}
+	// Reading the thumbnail makes it movable; it must still be found.
+	tl.Read(bytes.NewReader(testThumbInput))
+	tl.Thumbnail()
+	tl.IFD0().Tag(1).SetString("a much longer string value")
+	if thumb, _ := rewriteThumbTest(t, &tl); string(thumb) != "THUMB" {
+		t.Errorf("moved thumbnail: got %q", thumb)
+	}
+	// Replacing the thumbnail updates its length.
+	tl.Read(bytes.NewReader(testThumbInput))
+	if err := tl.SetThumbnail(bytes.NewReader([]byte("NEW THUMBNAIL"))); err != nil {
+		t.Fatal(err)
+	}
+	thumb, out := rewriteThumbTest(t, &tl)
+	if string(thumb) != "NEW THUMBNAIL" {
+		t.Errorf("replaced thumbnail: got %q", thumb)
+	}
+	// Removing the thumbnail removes IFD1.
+	out.SetThumbnail(nil)
+	if thumb, out = rewriteThumbTest(t, out); thumb != nil {
+		t.Errorf("removed thumbnail: got %q", thumb)
+	}
+	// Adding a thumbnail creates IFD1 with the tags EXIF requires.
+	out.SetThumbnail(bytes.NewReader([]byte("THUMB")))
+	if thumb, out = rewriteThumbTest(t, out); string(thumb) != "THUMB" {
+		t.Errorf("added thumbnail: got %q", thumb)
+	}
+	if ifd1, _ := out.IFD0().NextIFD(); ifd1 == nil || ifd1.Tag(tagCompression) == nil {
+		t.Error("added thumbnail: IFD1 missing Compression tag")
+	}
+}
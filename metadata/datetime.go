@@ -105,14 +105,60 @@ func (dt DateTime) AsTime() time.Time {
 	if tval == "" {
 		tval = "00:00:00"
 	}
+	if dt.subsec != "" {
+		tval += "." + dt.subsec
+	}
 	if dt.zone != "" {
-		t, _ := time.Parse("2006-01-02T15:04:05-07:00", fmt.Sprintf("%sT%s.%s%s", dt.date, tval, dt.subsec, dt.zone))
+		t, _ := time.Parse("2006-01-02T15:04:05Z07:00", fmt.Sprintf("%sT%s%s", dt.date, tval, dt.zone))
 		return t
 	}
-	t, _ := time.ParseInLocation("2006-01-02T15:04:05", fmt.Sprintf("%sT%s.%s", dt.date, tval, dt.subsec), time.Local)
+	t, _ := time.ParseInLocation("2006-01-02T15:04:05", fmt.Sprintf("%sT%s", dt.date, tval), time.Local)
 	return t
 }
 
+// Add returns the DateTime shifted by the specified duration, rounded to whole
+// seconds.  Its time zone (if any) and subseconds are unchanged.  A DateTime
+// without a time is returned unchanged.
+func (dt DateTime) Add(d time.Duration) (result DateTime) {
+	if dt.date == "" || dt.time == "" {
+		return dt
+	}
+	t, _ := time.Parse("2006-01-02T15:04:05", fmt.Sprintf("%sT%s", dt.date, dt.time))
+	t = t.Add(d.Round(time.Second))
+	result = dt
+	result.date = t.Format("2006-01-02")
+	result.time = t.Format("15:04:05")
+	return result
+}
+
+// HasZone returns whether the DateTime includes a time zone.
+func (dt DateTime) HasZone() bool {
+	return dt.zone != ""
+}
+
+// InLocation returns the DateTime with its time zone set to the offset in
+// effect in the specified location at that date and time.  If the DateTime has
+// no time zone, its date and time are taken to be local time in the location,
+// and only the time zone is added.  Otherwise, its date and time are converted
+// to local time in the location.
+func (dt DateTime) InLocation(loc *time.Location) (result DateTime) {
+	var t time.Time
+
+	if dt.date == "" {
+		return dt
+	}
+	if dt.zone == "" {
+		t, _ = time.ParseInLocation("2006-01-02T15:04:05", fmt.Sprintf("%sT%s", dt.date, dt.time), loc)
+	} else {
+		t = dt.AsTime().In(loc)
+	}
+	result.date = t.Format("2006-01-02")
+	result.time = t.Format("15:04:05")
+	result.subsec = dt.subsec
+	result.zone = t.Format("Z07:00")
+	return result
+}
+
 // ParseEXIF parses a date and time as represented in EXIF metadata.  It returns
 // ErrParseDateTime if the input is invalid.
 func (dt *DateTime) ParseEXIF(datetime, subsec, offset string) error {
@@ -2,6 +2,7 @@ package metadata
 
 import (
 	"testing"
+	"time"
 )
 
 func TestDateTime_Parse(t *testing.T) {
@@ -144,3 +145,130 @@ func TestDateTime_String(t *testing.T) {
 		})
 	}
 }
+
+func TestDateTime_AsTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		receiver DateTime
+		want     time.Time
+	}{
+		{
+			"empty",
+			DateTime{},
+			time.Time{},
+		},
+		{
+			"zone",
+			DateTime{"2022-03-04", "05:06:07", "", "-08:00"},
+			time.Date(2022, 3, 4, 13, 6, 7, 0, time.UTC),
+		},
+		{
+			"utc",
+			DateTime{"2022-03-04", "05:06:07", "", "Z"},
+			time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC),
+		},
+		{
+			"subsec",
+			DateTime{"2022-03-04", "05:06:07", "25", "Z"},
+			time.Date(2022, 3, 4, 5, 6, 7, 250000000, time.UTC),
+		},
+		{
+			"local",
+			DateTime{"2022-03-04", "05:06:07", "", ""},
+			time.Date(2022, 3, 4, 5, 6, 7, 0, time.Local),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.receiver.AsTime(); !got.Equal(tt.want) {
+				t.Errorf("DateTime.AsTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateTime_InLocation(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		receiver DateTime
+		want     DateTime
+	}{
+		{
+			"empty",
+			DateTime{},
+			DateTime{},
+		},
+		{
+			"local winter",
+			DateTime{"2022-01-04", "05:06:07", "25", ""},
+			DateTime{"2022-01-04", "05:06:07", "25", "-08:00"},
+		},
+		{
+			"local summer",
+			DateTime{"2022-07-04", "05:06:07", "", ""},
+			DateTime{"2022-07-04", "05:06:07", "", "-07:00"},
+		},
+		{
+			"utc",
+			DateTime{"2022-07-04", "05:06:07", "", "Z"},
+			DateTime{"2022-07-03", "22:06:07", "", "-07:00"},
+		},
+		{
+			"zone",
+			DateTime{"2022-03-13", "09:30:00", "", "-05:00"},
+			DateTime{"2022-03-13", "07:30:00", "", "-07:00"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.receiver.InLocation(la); !got.Equal(tt.want) {
+				t.Errorf("DateTime.InLocation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateTime_Add(t *testing.T) {
+	tests := []struct {
+		name     string
+		receiver DateTime
+		arg      time.Duration
+		want     DateTime
+	}{
+		{
+			"empty",
+			DateTime{},
+			time.Hour,
+			DateTime{},
+		},
+		{
+			"forward",
+			DateTime{"2022-03-04", "23:59:30", "25", "-08:00"},
+			90 * time.Second,
+			DateTime{"2022-03-05", "00:01:00", "25", "-08:00"},
+		},
+		{
+			"backward rounded",
+			DateTime{"2022-03-04", "05:06:07", "", ""},
+			-1600 * time.Millisecond,
+			DateTime{"2022-03-04", "05:06:05", "", ""},
+		},
+		{
+			"date only",
+			DateTime{"2022-03-04", "", "", ""},
+			time.Hour,
+			DateTime{"2022-03-04", "", "", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.receiver.Add(tt.arg); !got.Equal(tt.want) {
+				t.Errorf("DateTime.Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dimensions are the pixel dimensions of an image.
+type Dimensions struct {
+	Width  int
+	Height int
+}
+
+// ErrParseDimensions is the error returned when a string cannot be parsed into
+// dimensions.
+var ErrParseDimensions = errors.New("invalid dimensions: must be WIDTHxHEIGHT")
+
+// Parse sets the value from the input string, which has the form WIDTHxHEIGHT.
+func (d *Dimensions) Parse(s string) (err error) {
+	*d = Dimensions{}
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	if !ok {
+		return ErrParseDimensions
+	}
+	if d.Width, err = strconv.Atoi(strings.TrimSpace(w)); err != nil || d.Width <= 0 {
+		*d = Dimensions{}
+		return ErrParseDimensions
+	}
+	if d.Height, err = strconv.Atoi(strings.TrimSpace(h)); err != nil || d.Height <= 0 {
+		*d = Dimensions{}
+		return ErrParseDimensions
+	}
+	return nil
+}
+
+// String returns the value in string form, suitable for input to Parse.
+func (d Dimensions) String() string {
+	if d.Empty() {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", d.Width, d.Height)
+}
+
+// Empty returns true if the dimensions are not known.
+func (d Dimensions) Empty() bool { return d.Width == 0 || d.Height == 0 }
@@ -0,0 +1,22 @@
+package metadata
+
+import "testing"
+
+func TestDimensions(t *testing.T) {
+	var d Dimensions
+	if err := d.Parse("4000 x 3000"); err != nil {
+		t.Fatalf("d.Parse failed: %s", err)
+	}
+	if d.Width != 4000 || d.Height != 3000 {
+		t.Errorf("d.Parse result is wrong: %+v", d)
+	}
+	if s := d.String(); s != "4000x3000" {
+		t.Errorf("d.String is wrong: %s", s)
+	}
+	if err := d.Parse("4000"); err == nil || !d.Empty() {
+		t.Errorf("d.Parse of width only succeeded")
+	}
+	if err := d.Parse("0x3000"); err == nil {
+		t.Errorf("d.Parse of zero width succeeded")
+	}
+}
@@ -0,0 +1,165 @@
+package metadata
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FaceRegion describes a region of an image that contains a person's face.
+// The area of the region follows the conventions of the Metadata Working
+// Group's region schema:  X and Y are the coordinates of the center of the
+// region, and W and H are its width and height.  When Unit is "normalized",
+// they are fractions of the image width and height; when Unit is "pixel", they
+// are pixel counts.  A region with zero width and height has no known area.
+type FaceRegion struct {
+	Name string
+	X    float64
+	Y    float64
+	W    float64
+	H    float64
+	Unit string
+	// AppliedToW and AppliedToH are the dimensions of the image, in
+	// pixels, to which the region applies.  They are zero if not known.
+	AppliedToW int
+	AppliedToH int
+}
+
+// ErrParseFaceRegion is the error returned when a string cannot be parsed into
+// a face region.
+var ErrParseFaceRegion = errors.New("invalid face region: must be name, optionally followed by @ x, y, w, h")
+
+// Parse sets the value from the input string.  The input is a name, optionally
+// followed by an at sign and the normalized coordinates of the region's
+// center, width, and height, separated by commas.
+func (fr *FaceRegion) Parse(s string) (err error) {
+	*fr = FaceRegion{}
+	name, area, hasArea := strings.Cut(s, "@")
+	if fr.Name = strings.TrimSpace(name); fr.Name == "" {
+		return ErrParseFaceRegion
+	}
+	if !hasArea {
+		return nil
+	}
+	parts := strings.Split(area, ",")
+	if len(parts) != 4 {
+		return ErrParseFaceRegion
+	}
+	var coords [4]float64
+	for i, part := range parts {
+		if coords[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64); err != nil || coords[i] < 0 || coords[i] > 1 {
+			return ErrParseFaceRegion
+		}
+	}
+	if coords[2] == 0 || coords[3] == 0 {
+		return ErrParseFaceRegion
+	}
+	fr.X, fr.Y, fr.W, fr.H, fr.Unit = coords[0], coords[1], coords[2], coords[3], "normalized"
+	return nil
+}
+
+// String returns the value in string form, suitable for input to Parse.
+func (fr FaceRegion) String() string {
+	if !fr.HasArea() {
+		return fr.Name
+	}
+	n := fr.Normalized()
+	if n.Unit != "normalized" {
+		// We can't express pixel coordinates without knowing the image
+		// size, so we show only the name.
+		return fr.Name
+	}
+	var sb strings.Builder
+	sb.WriteString(fr.Name)
+	sb.WriteString(" @ ")
+	for i, v := range []float64{n.X, n.Y, n.W, n.H} {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.FormatFloat(math.Round(v*10000)/10000, 'f', -1, 64))
+	}
+	return sb.String()
+}
+
+// Empty returns true if the face region has no data.
+func (fr FaceRegion) Empty() bool { return fr.Name == "" && !fr.HasArea() }
+
+// HasArea returns true if the face region has a known area.
+func (fr FaceRegion) HasArea() bool { return fr.W != 0 && fr.H != 0 }
+
+// Normalized returns a copy of the face region with its area expressed in
+// normalized units.  If its area is in pixels and the dimensions of the image
+// are not known, the copy is unchanged.
+func (fr FaceRegion) Normalized() FaceRegion {
+	if fr.Unit != "pixel" || fr.AppliedToW == 0 || fr.AppliedToH == 0 {
+		return fr
+	}
+	fr.X /= float64(fr.AppliedToW)
+	fr.W /= float64(fr.AppliedToW)
+	fr.Y /= float64(fr.AppliedToH)
+	fr.H /= float64(fr.AppliedToH)
+	fr.Unit = "normalized"
+	return fr
+}
+
+// Equivalent returns true if the receiver has the same name and area as the
+// argument.  Areas within 0.0005 of each other (in normalized units) are
+// considered equivalent; this handles rounding in conversions between the
+// different region schemas.
+func (fr FaceRegion) Equivalent(other FaceRegion) bool {
+	if fr.Name != other.Name || fr.HasArea() != other.HasArea() {
+		return false
+	}
+	if !fr.HasArea() {
+		return true
+	}
+	a, b := fr.Normalized(), other.Normalized()
+	if a.Unit != b.Unit {
+		return false
+	}
+	tolerance := 0.0005
+	if a.Unit != "normalized" {
+		tolerance = 0.5
+	}
+	return math.Abs(a.X-b.X) <= tolerance && math.Abs(a.Y-b.Y) <= tolerance &&
+		math.Abs(a.W-b.W) <= tolerance && math.Abs(a.H-b.H) <= tolerance
+}
+
+// Transformed returns a copy of the face region, adjusted for a lossless
+// transformation of the image data as indicated by o.  w and h are the
+// dimensions of the image before the transformation; tw and th are the
+// dimensions of the portion of it, at its top left, that was kept by the
+// transformation.
+func (fr FaceRegion) Transformed(o Orientation, w, h, tw, th int) FaceRegion {
+	if !fr.HasArea() {
+		return fr
+	}
+	if fr.Unit == "pixel" && fr.AppliedToW != 0 && fr.AppliedToH != 0 {
+		w, h = fr.AppliedToW, fr.AppliedToH
+	}
+	if fr.Unit != "pixel" {
+		fr.X, fr.W = fr.X*float64(w), fr.W*float64(w)
+		fr.Y, fr.H = fr.Y*float64(h), fr.H*float64(h)
+	}
+	if o > Rotate270 {
+		o = Rotate0
+	}
+	steps := orientationSteps[o]
+	if steps.flip {
+		fr.X = float64(tw) - fr.X
+	}
+	for i := 0; i < steps.turns; i++ {
+		fr.X, fr.Y = float64(th)-fr.Y, fr.X
+		fr.W, fr.H = fr.H, fr.W
+		tw, th = th, tw
+	}
+	if fr.Unit != "pixel" {
+		fr.X, fr.W = fr.X/float64(tw), fr.W/float64(tw)
+		fr.Y, fr.H = fr.Y/float64(th), fr.H/float64(th)
+	}
+	if fr.Unit == "pixel" || fr.AppliedToW != 0 {
+		fr.AppliedToW, fr.AppliedToH = tw, th
+	}
+	return fr
+}
@@ -0,0 +1,48 @@
+package metadata
+
+import "testing"
+
+func TestFaceRegion(t *testing.T) {
+	var fr FaceRegion
+	if err := fr.Parse("Jane Doe @ 0.5, 0.4, 0.1, 0.2"); err != nil {
+		t.Fatalf("fr.Parse failed: %s", err)
+	}
+	if fr.Name != "Jane Doe" || fr.X != 0.5 || fr.Y != 0.4 || fr.W != 0.1 || fr.H != 0.2 || fr.Unit != "normalized" {
+		t.Errorf("fr.Parse result is wrong: %+v", fr)
+	}
+	if s := fr.String(); s != "Jane Doe @ 0.5, 0.4, 0.1, 0.2" {
+		t.Errorf("fr.String is wrong: %s", s)
+	}
+	pixels := FaceRegion{Name: "Jane Doe", X: 500, Y: 200.1, W: 100, H: 100, Unit: "pixel", AppliedToW: 1000, AppliedToH: 500}
+	if !fr.Equivalent(pixels) {
+		t.Errorf("pixel region is not equivalent: %s", pixels.String())
+	}
+	if err := fr.Parse("Jane Doe"); err != nil || fr.HasArea() {
+		t.Errorf("fr.Parse of name only failed")
+	}
+	if err := fr.Parse("Jane Doe @ 0.5, 0.4"); err == nil {
+		t.Errorf("fr.Parse of partial area succeeded")
+	}
+}
+
+func TestFaceRegionTransformed(t *testing.T) {
+	fr := FaceRegion{Name: "Jane Doe", X: 0.25, Y: 0.5, W: 0.1, H: 0.2, Unit: "normalized"}
+	want := FaceRegion{Name: "Jane Doe", X: 0.5, Y: 0.25, W: 0.2, H: 0.1, Unit: "normalized"}
+	if got := fr.Transformed(Rotate90, 100, 80, 100, 80); !got.Equivalent(want) {
+		t.Errorf("Rotate90: got %s", got.String())
+	}
+	want = FaceRegion{Name: "Jane Doe", X: 0.75, Y: 0.5, W: 0.1, H: 0.2, Unit: "normalized"}
+	if got := fr.Transformed(FlipX, 100, 80, 100, 80); !got.Equivalent(want) {
+		t.Errorf("FlipX: got %s", got.String())
+	}
+	// Trimming 4 pixels from the right edge shifts a mirrored region left.
+	want = FaceRegion{Name: "Jane Doe", X: 71.0 / 96.0, Y: 0.5, W: 10.0 / 96.0, H: 0.2, Unit: "normalized"}
+	if got := fr.Transformed(FlipX, 100, 80, 96, 80); !got.Equivalent(want) {
+		t.Errorf("FlipX trimmed: got %s", got.String())
+	}
+	pixels := FaceRegion{Name: "Jane Doe", X: 25, Y: 40, W: 10, H: 16, Unit: "pixel", AppliedToW: 100, AppliedToH: 80}
+	want = FaceRegion{Name: "Jane Doe", X: 40, Y: 75, W: 16, H: 10, Unit: "pixel", AppliedToW: 80, AppliedToH: 100}
+	if got := pixels.Transformed(Rotate270, 100, 80, 100, 80); got != want {
+		t.Errorf("Rotate270 pixels: got %+v", got)
+	}
+}
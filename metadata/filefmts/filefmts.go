@@ -10,8 +10,14 @@ import (
 	"path/filepath"
 
 	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/filefmts/gif"
+	"github.com/rothskeller/photo-tools/metadata/filefmts/heif"
 	"github.com/rothskeller/photo-tools/metadata/filefmts/jpeg"
+	"github.com/rothskeller/photo-tools/metadata/filefmts/png"
+	"github.com/rothskeller/photo-tools/metadata/filefmts/quicktime"
+	"github.com/rothskeller/photo-tools/metadata/filefmts/raw"
 	"github.com/rothskeller/photo-tools/metadata/filefmts/tiff"
+	"github.com/rothskeller/photo-tools/metadata/filefmts/webp"
 	"github.com/rothskeller/photo-tools/metadata/filefmts/xmp"
 )
 
@@ -27,6 +33,40 @@ type FileFormat interface {
 	Save(out io.Writer) error
 }
 
+// SidecarFormat is an interface satisfied by file format handlers that save
+// changed metadata to a sidecar file rather than to the file itself.
+type SidecarFormat interface {
+	FileFormat
+	// Sidecar returns the name of the sidecar file to which Save writes.
+	Sidecar() string
+}
+
+// TransformableFormat is an interface satisfied by file format handlers that
+// can losslessly rotate and flip the image data in the file.
+type TransformableFormat interface {
+	FileFormat
+	// Transform losslessly transforms the image data so that the image
+	// looks the way it would have been displayed with the specified
+	// orientation, and updates the metadata to match.  It returns the
+	// original dimensions of the image and the dimensions of the part of it
+	// that was kept, for use with metadata.FaceRegion.Transformed.
+	Transform(o metadata.Orientation) (orig, kept metadata.Dimensions, err error)
+}
+
+// ThumbnailFormat is an interface satisfied by file format handlers that can
+// carry an embedded thumbnail image.
+type ThumbnailFormat interface {
+	FileFormat
+	// Thumbnail returns the embedded thumbnail image, as a JPEG file, or
+	// nil if there is none.
+	Thumbnail() (metadata.Reader, error)
+	// RegenerateThumbnail replaces the embedded thumbnail image with one
+	// rendered from the main image.
+	RegenerateThumbnail() error
+	// RemoveThumbnail removes the embedded thumbnail image.
+	RemoveThumbnail() error
+}
+
 // HandlerForName returns a file format handler appropriate for the type of the
 // specified file, or nil if there is no handler for the file type.  It returns
 // an error if the file cannot be read, or if the handler for its type finds a
@@ -53,11 +93,41 @@ func HandlerFor(fh *os.File) (f FileFormat, err error) {
 	} else if f != nil {
 		return f, nil
 	}
+	if f, err := png.Read(reader{fh}); err != nil {
+		return nil, fmt.Errorf("%s: %s", fh.Name(), err)
+	} else if f != nil {
+		return f, nil
+	}
+	if f, err := webp.Read(reader{fh}); err != nil {
+		return nil, fmt.Errorf("%s: %s", fh.Name(), err)
+	} else if f != nil {
+		return f, nil
+	}
+	if f, err := gif.Read(reader{fh}); err != nil {
+		return nil, fmt.Errorf("%s: %s", fh.Name(), err)
+	} else if f != nil {
+		return f, nil
+	}
+	if f, err := heif.Read(reader{fh}); err != nil {
+		return nil, fmt.Errorf("%s: %s", fh.Name(), err)
+	} else if f != nil {
+		return f, nil
+	}
+	if f, err := quicktime.Read(reader{fh}); err != nil {
+		return nil, fmt.Errorf("%s: %s", fh.Name(), err)
+	} else if f != nil {
+		return f, nil
+	}
 	if f, err := xmp.Read(reader{fh}); err != nil {
 		return nil, fmt.Errorf("%s: %s", fh.Name(), err)
 	} else if f != nil {
 		return f, nil
 	}
+	if f, err := raw.Read(reader{fh}, SidecarName(fh.Name())); err != nil {
+		return nil, fmt.Errorf("%s: %s", fh.Name(), err)
+	} else if f != nil {
+		return f, nil
+	}
 	if f, err := tiff.Read(reader{fh}); err != nil {
 		return nil, fmt.Errorf("%s: %s", fh.Name(), err)
 	} else if f != nil {
@@ -67,12 +137,17 @@ func HandlerFor(fh *os.File) (f FileFormat, err error) {
 }
 
 // Save saves the file represented by the handler to the specified file name.
+// If the handler is a SidecarFormat, it saves to the handler's sidecar file
+// instead.
 func Save(f FileFormat, file string) (err error) {
 	var (
 		tempfn string
 		ofh    *os.File
 		out    *bufio.Writer
 	)
+	if sf, ok := f.(SidecarFormat); ok {
+		file = sf.Sidecar()
+	}
 	tempfn = filepath.Dir(file) + "/." + filepath.Base(file) + ".TEMP"
 	if ofh, err = os.Create(tempfn); err != nil {
 		return err
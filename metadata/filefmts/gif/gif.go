@@ -0,0 +1,79 @@
+// Package gif contains the file format handler for GIF files.
+package gif
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/gif"
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
+)
+
+// GIF is a GIF file handler.
+type GIF struct {
+	container *gif.GIF
+	xmpRDF    *rdf.Packet
+	providers multi.Provider
+}
+
+// Read reads the provided file.  It returns nil, nil, if the file is not a GIF
+// file.  It returns an error if the file is a GIF file but ill-formed, or if a
+// read error occurs.  It returns a GIF file handler for the file if it is read
+// successfully.
+func Read(r metadata.Reader) (gh *GIF, err error) {
+	var buf [6]byte
+
+	if _, err = r.ReadAt(buf[:], 0); err == io.EOF {
+		return nil, nil // can't read a signature, assume it's not GIF
+	} else if err != nil {
+		return nil, err
+	} else if string(buf[:]) != "GIF87a" && string(buf[:]) != "GIF89a" {
+		return nil, nil // not a GIF file
+	}
+	gh = new(GIF)
+	gh.container = new(gif.GIF)
+	if err = gh.container.Read(r); err != nil {
+		return nil, err
+	}
+	if err = gh.readXMPBlock(); err != nil {
+		return nil, err
+	}
+	return gh, nil
+}
+
+// Provider returns the metadata.Provider for the GIF file.
+func (gh *GIF) Provider() metadata.Provider { return gh.providers }
+
+func (gh *GIF) readXMPBlock() (err error) {
+	var (
+		xmpBlock    metadata.Reader
+		xmpProvider *xmp.Provider
+	)
+	gh.xmpRDF = rdf.New()
+	if xmpBlock = gh.container.XMP(); xmpBlock != nil {
+		if err = gh.xmpRDF.Read(xmpBlock); err != nil {
+			return fmt.Errorf("XMP: %s", err)
+		}
+	}
+	gh.container.SetXMPContainer(gh.xmpRDF)
+	if xmpProvider, err = xmp.New(gh.xmpRDF); err != nil {
+		return err
+	}
+	gh.providers = append(gh.providers, xmpProvider)
+	return nil
+}
+
+// Dirty returns whether the metadata from the file have been changed since they
+// were read (and therefore need to be saved).
+func (gh *GIF) Dirty() bool { return gh.container.Dirty() }
+
+// Save writes the entire file to the supplied writer, including all revised
+// metadata.
+func (gh *GIF) Save(out io.Writer) (err error) {
+	gh.container.Layout()
+	_, err = gh.container.Write(out)
+	return err
+}
@@ -0,0 +1,178 @@
+// Package heif contains the file format handler for HEIF (and HEIC and AVIF)
+// files.
+package heif
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/isobmff"
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+	"github.com/rothskeller/photo-tools/metadata/providers/exififd"
+	"github.com/rothskeller/photo-tools/metadata/providers/gpsifd"
+	"github.com/rothskeller/photo-tools/metadata/providers/jpegifd0"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
+	"github.com/rothskeller/photo-tools/metadata/providers/xp"
+)
+
+const (
+	tagEXIFIFD uint16 = 0x8769
+	tagGPSIFD  uint16 = 0x8825
+)
+
+// heifBrands is the set of file type brands that identify HEIF files.
+var heifBrands = map[string]bool{
+	"mif1": true, "msf1": true, "heic": true, "heix": true, "heim": true,
+	"heis": true, "hevc": true, "hevx": true, "avif": true, "avis": true,
+}
+
+// HEIF is a HEIF file handler.
+type HEIF struct {
+	container *isobmff.ISOBMFF
+	exifTIFF  *tiff.TIFF
+	xmpRDF    *rdf.Packet
+	providers multi.Provider
+}
+
+// Read reads the provided file.  It returns nil, nil, if the file is not a HEIF
+// file.  It returns an error if the file is a HEIF file but ill-formed, or if a
+// read error occurs.  It returns a HEIF file handler for the file if it is read
+// successfully.
+func Read(r metadata.Reader) (hh *HEIF, err error) {
+	var xpProvider *xp.Provider
+
+	if !isHEIF(r) {
+		return nil, nil
+	}
+	hh = new(HEIF)
+	hh.container = new(isobmff.ISOBMFF)
+	if err = hh.container.Read(r); err != nil {
+		return nil, err
+	}
+	if err = hh.readEXIFItem(); err != nil {
+		return nil, err
+	}
+	if err = hh.readXMPItem(); err != nil {
+		return nil, err
+	}
+	// The Windows XP* tags come last, so that they have the lowest
+	// priority.
+	if xpProvider, err = xp.New(hh.exifTIFF.IFD0(), hh.providers); err != nil {
+		return nil, err
+	}
+	hh.providers = append(hh.providers, xpProvider)
+	return hh, nil
+}
+
+// isHEIF returns whether the file starts with a file type box naming one of
+// the HEIF brands.
+func isHEIF(r metadata.Reader) bool {
+	var buf [64]byte
+
+	n, _ := r.ReadAt(buf[:], 0)
+	if n < 16 || string(buf[4:8]) != "ftyp" {
+		return false
+	}
+	size := int(buf[0])<<24 | int(buf[1])<<16 | int(buf[2])<<8 | int(buf[3])
+	if size > n {
+		size = n
+	}
+	if heifBrands[string(buf[8:12])] {
+		return true
+	}
+	for i := 16; i+4 <= size; i += 4 {
+		if heifBrands[string(buf[i:i+4])] {
+			return true
+		}
+	}
+	return false
+}
+
+// Provider returns the metadata.Provider for the HEIF file.
+func (hh *HEIF) Provider() metadata.Provider { return hh.providers }
+
+func (hh *HEIF) readEXIFItem() (err error) {
+	var (
+		exifItem         metadata.Reader
+		ifd0             *tiff.IFD
+		exifIFD          *tiff.IFD
+		gpsIFD           *tiff.IFD
+		jpegIFD0Provider *jpegifd0.Provider
+		exifIFDProvider  *exififd.Provider
+		gpsIFDProvider   *gpsifd.Provider
+	)
+	hh.exifTIFF = new(tiff.TIFF)
+	if exifItem = hh.container.EXIF(); exifItem != nil {
+		if err = hh.exifTIFF.Read(exifItem); err != nil {
+			return fmt.Errorf("Exif item: %s", err)
+		}
+	}
+	hh.container.SetEXIFContainer(hh.exifTIFF)
+	// HEIF images are oriented by the irot and imir properties rather than
+	// the EXIF Orientation tag, so IFD0 gets the same treatment as it does
+	// in a JPEG file.
+	ifd0 = hh.exifTIFF.IFD0()
+	if jpegIFD0Provider, err = jpegifd0.New(ifd0); err != nil {
+		return err
+	}
+	hh.providers = append(hh.providers, jpegIFD0Provider)
+	if tag := ifd0.Tag(tagEXIFIFD); tag != nil {
+		if exifIFD, err = tag.AsIFD(); err != nil {
+			return fmt.Errorf("EXIF IFD: %s", err)
+		}
+	} else {
+		tag = ifd0.AddTag(tagEXIFIFD, 4)
+		exifIFD, _ = tag.AddIFD()
+	}
+	if exifIFDProvider, err = exififd.New(exifIFD, hh.exifTIFF.Encoding()); err != nil {
+		return err
+	}
+	hh.providers = append(hh.providers, exifIFDProvider)
+	if tag := ifd0.Tag(tagGPSIFD); tag != nil {
+		if gpsIFD, err = tag.AsIFD(); err != nil {
+			return fmt.Errorf("GPS IFD: %s", err)
+		}
+	} else {
+		tag = ifd0.AddTag(tagGPSIFD, 4)
+		gpsIFD, _ = tag.AddIFD()
+	}
+	if gpsIFDProvider, err = gpsifd.New(gpsIFD); err != nil {
+		return err
+	}
+	hh.providers = append(hh.providers, gpsIFDProvider)
+	return nil
+}
+
+func (hh *HEIF) readXMPItem() (err error) {
+	var (
+		xmpItem     metadata.Reader
+		xmpProvider *xmp.Provider
+	)
+	hh.xmpRDF = rdf.New()
+	if xmpItem = hh.container.XMP(); xmpItem != nil {
+		if err = hh.xmpRDF.Read(xmpItem); err != nil {
+			return fmt.Errorf("XMP: %s", err)
+		}
+	}
+	hh.container.SetXMPContainer(hh.xmpRDF)
+	if xmpProvider, err = xmp.New(hh.xmpRDF); err != nil {
+		return err
+	}
+	hh.providers = append(hh.providers, xmpProvider)
+	return nil
+}
+
+// Dirty returns whether the metadata from the file have been changed since they
+// were read (and therefore need to be saved).
+func (hh *HEIF) Dirty() bool { return hh.container.Dirty() }
+
+// Save writes the entire file to the supplied writer, including all revised
+// metadata.
+func (hh *HEIF) Save(out io.Writer) (err error) {
+	hh.container.Layout()
+	_, err = hh.container.Write(out)
+	return err
+}
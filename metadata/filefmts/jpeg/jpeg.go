@@ -2,8 +2,11 @@
 package jpeg
 
 import (
+	"bytes"
+	"crypto/md5"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/rothskeller/photo-tools/metadata"
 	"github.com/rothskeller/photo-tools/metadata/containers/iim"
@@ -18,7 +21,7 @@ import (
 	"github.com/rothskeller/photo-tools/metadata/providers/jpegifd0"
 	"github.com/rothskeller/photo-tools/metadata/providers/multi"
 	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
-	"github.com/rothskeller/photo-tools/metadata/providers/xmpext"
+	"github.com/rothskeller/photo-tools/metadata/providers/xp"
 )
 
 const (
@@ -26,14 +29,20 @@ const (
 	tagGPSIFD  uint16 = 0x8825
 	psirIDIIM  uint16 = 0x404
 	psirIDHash uint16 = 0x425
+	nsXMPNote         = "http://ns.adobe.com/xmp/note/"
+	pfxXMPNote        = "xmpNote"
 )
 
+var nameHasExtendedXMP = rdf.Name{Namespace: nsXMPNote, Name: "HasExtendedXMP"}
+
 // JPEG is a JPEG file handler.
 type JPEG struct {
 	container *jpeg.JPEG
 	exifTIFF  *tiff.TIFF
 	psirBlock *photoshop.Photoshop
 	iim       *iim.IIM
+	iptc      *iptc.Provider
+	exifIFD   *exififd.Provider
 	xmpRDF    *rdf.Packet
 	providers multi.Provider
 }
@@ -43,7 +52,10 @@ type JPEG struct {
 // read error occurs.  It returns a JPEG file handler for the file if it is read
 // successfully.
 func Read(r metadata.Reader) (jh *JPEG, err error) {
-	var buf [2]byte
+	var (
+		buf        [2]byte
+		xpProvider *xp.Provider
+	)
 
 	if _, err = r.ReadAt(buf[0:2], 0); err == io.EOF {
 		return nil, nil // can't read a signature, assume it's not JPEG
@@ -66,6 +78,12 @@ func Read(r metadata.Reader) (jh *JPEG, err error) {
 	if err = jh.readPSIRSegment(); err != nil {
 		return nil, err
 	}
+	// The Windows XP* tags come last, so that they have the lowest
+	// priority.
+	if xpProvider, err = xp.New(jh.exifTIFF.IFD0(), jh.providers); err != nil {
+		return nil, err
+	}
+	jh.providers = append(jh.providers, xpProvider)
 	return jh, nil
 }
 
@@ -105,6 +123,7 @@ func (jh *JPEG) readEXIFSegment() (err error) {
 	if exifIFDProvider, err = exififd.New(exifIFD, jh.exifTIFF.Encoding()); err != nil {
 		return err
 	}
+	jh.exifIFD = exifIFDProvider
 	jh.providers = append(jh.providers, exifIFDProvider)
 	if tag := jpegIFD0.Tag(tagGPSIFD); tag != nil {
 		if gpsIFD, err = tag.AsIFD(); err != nil {
@@ -146,17 +165,17 @@ func (jh *JPEG) readPSIRSegment() (err error) {
 	if iptcProvider, err = iptc.New(jh.iim); err != nil {
 		return err
 	}
+	jh.iptc = iptcProvider
 	jh.providers = append(jh.providers, iptcProvider)
 	return nil
 }
 
 func (jh *JPEG) readXMPSegments() (err error) {
 	var (
-		xmpSeg metadata.Reader
-		// xmpExtSeg      metadata.Reader
-		xmpExtRDF      *rdf.Packet
-		xmpProvider    *xmp.Provider
-		xmpExtProvider *xmpext.Provider
+		xmpSeg      metadata.Reader
+		xmpExtSeg   metadata.Reader
+		xmpExtRDF   *rdf.Packet
+		xmpProvider *xmp.Provider
 	)
 	jh.xmpRDF = rdf.New()
 	if xmpSeg = jh.container.XMP(); xmpSeg != nil {
@@ -164,31 +183,95 @@ func (jh *JPEG) readXMPSegments() (err error) {
 			return fmt.Errorf("XMP: %s", err)
 		}
 	}
+	jh.xmpRDF.RegisterNamespace(pfxXMPNote, nsXMPNote)
+	// If the standard XMP packet refers to an extended XMP packet, merge
+	// the two.  An extended XMP packet that is missing or doesn't match
+	// the GUID in the standard packet is ignored, as the XMP specification
+	// requires.
+	if guid, ok := jh.xmpRDF.Property(nameHasExtendedXMP).Value.(string); ok {
+		if xmpExtSeg = jh.container.XMPext(guid); xmpExtSeg != nil {
+			xmpExtRDF = rdf.New()
+			if err = xmpExtRDF.Read(xmpExtSeg); err != nil {
+				return fmt.Errorf("XMPExt: %s", err)
+			}
+			jh.xmpRDF.Merge(xmpExtRDF)
+		}
+	}
 	jh.container.SetXMPContainer(jh.xmpRDF)
 	if xmpProvider, err = xmp.New(jh.xmpRDF); err != nil {
 		return err
 	}
 	jh.providers = append(jh.providers, xmpProvider)
-	xmpExtRDF = rdf.New()
-	// if xmpExtSeg = jh.container.XMPext(); xmpExtSeg != nil {
-	// 	if err = xmpExtRDF.Read(xmpExtSeg); err != nil {
-	// 		return fmt.Errorf("XMPExt: %s", err)
-	// 	}
-	// }
-	if xmpExtProvider, err = xmpext.New(xmpExtRDF); err != nil {
-		return err
+	return nil
+}
+
+// layoutXMP splits the XMP packet into standard and extended packets, if it
+// is too large to fit in a single JPEG segment, and gives them to the
+// container.  The GUID of the extended packet is the MD5 digest of its
+// rendering, in upper-case hex, as the XMP specification requires.
+func (jh *JPEG) layoutXMP() (err error) {
+	var (
+		std, ext *rdf.Packet
+		guid     string
+		buf      bytes.Buffer
+	)
+	std, ext = jh.xmpRDF.Split(jpeg.MaxXMPSize, nameHasExtendedXMP, rdf.Value{Value: strings.Repeat("0", 32)})
+	if ext != nil {
+		ext.Layout()
+		if _, err = ext.Write(&buf); err != nil {
+			return fmt.Errorf("XMPExt: %s", err)
+		}
+		guid = fmt.Sprintf("%X", md5.Sum(buf.Bytes()))
+		std.SetProperty(nameHasExtendedXMP, rdf.Value{Value: guid})
+		jh.container.SetXMPextContainer(guid, ext)
+	} else {
+		jh.container.SetXMPextContainer("", nil)
 	}
-	jh.providers = append(jh.providers, xmpExtProvider)
+	jh.container.SetXMPContainer(std)
 	return nil
 }
 
+// Transform losslessly transforms the image data so that the image looks the
+// way it would have been displayed with the specified orientation.  The
+// metadata are updated to match:  the orientation is reset, the image
+// dimensions and face regions are adjusted, and the EXIF thumbnail (if any) is
+// regenerated, or removed if it can't be.  It returns the original
+// dimensions of the image and the dimensions of the part of it that was kept.
+func (jh *JPEG) Transform(o metadata.Orientation) (orig, kept metadata.Dimensions, err error) {
+	var result metadata.Dimensions
+
+	if orig, kept, result, err = jh.container.Transform(o); err != nil {
+		return orig, kept, err
+	}
+	if faces := jh.providers.Faces(); len(faces) != 0 {
+		for i := range faces {
+			faces[i] = faces[i].Transformed(o, orig.Width, orig.Height, kept.Width, kept.Height)
+		}
+		if err = jh.providers.SetFaces(faces); err != nil && err != metadata.ErrNotSupported {
+			return orig, kept, err
+		}
+	}
+	jh.exifIFD.SetDimensions(result)
+	if err = jh.providers.SetOrientation(metadata.Rotate0); err != nil && err != metadata.ErrNotSupported {
+		return orig, kept, err
+	}
+	if thumb, err := jh.Thumbnail(); err == nil && thumb != nil {
+		if err = jh.RegenerateThumbnail(); err != nil {
+			return orig, kept, jh.RemoveThumbnail()
+		}
+	}
+	return orig, kept, nil
+}
+
 // Dirty returns whether the metadata from the file have been changed since they
 // were read (and therefore need to be saved).
 func (jh *JPEG) Dirty() bool { return jh.container.Dirty() }
 
 // Save writes the entire file to the supplied writer, including all revised
-// metadata.
+// metadata.  IPTC metadata that were read in a legacy character set are
+// rewritten in UTF-8.
 func (jh *JPEG) Save(out io.Writer) (err error) {
+	jh.iptc.Transcode()
 	if !jh.iim.Empty() {
 		var (
 			hashRaw  *raw.Raw
@@ -203,6 +286,9 @@ func (jh *JPEG) Save(out io.Writer) (err error) {
 		jh.iim.SetHashContainer(hashRaw)
 		hashRaw.SetData(make([]byte, 16)) // give it correct size
 	}
+	if err = jh.layoutXMP(); err != nil {
+		return err
+	}
 	jh.container.Layout()
 	_, err = jh.container.Write(out)
 	return err
@@ -0,0 +1,101 @@
+package jpeg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	stdjpeg "image/jpeg"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// Maximum dimensions of a thumbnail image, as displayed.  These are the
+// dimensions recommended by the EXIF specification.
+const (
+	thumbWidth  = 160
+	thumbHeight = 120
+)
+
+// Thumbnail returns the EXIF thumbnail image, as a JPEG file, or nil if there
+// is none.
+func (jh *JPEG) Thumbnail() (metadata.Reader, error) {
+	return jh.exifTIFF.Thumbnail()
+}
+
+// RegenerateThumbnail replaces the EXIF thumbnail image with one rendered from
+// the main image.  The EXIF thumbnail shares the orientation of the main
+// image, so it is rendered from the image data as stored, but sized so that
+// it fits within 160x120 when displayed with that orientation.
+func (jh *JPEG) RegenerateThumbnail() (err error) {
+	var (
+		data  metadata.Reader
+		img   image.Image
+		buf   bytes.Buffer
+		maxW  = thumbWidth
+		maxH  = thumbHeight
+		thumb image.Image
+	)
+	if data, err = jh.container.ImageData(); err != nil {
+		return err
+	}
+	if img, err = stdjpeg.Decode(data); err != nil {
+		return fmt.Errorf("decoding image: %s", err)
+	}
+	if o := jh.providers.Orientation(); o >= metadata.Rotate90FlipX && o <= metadata.Rotate270 {
+		maxW, maxH = maxH, maxW
+	}
+	thumb = shrinkImage(img, maxW, maxH)
+	if err = stdjpeg.Encode(&buf, thumb, &stdjpeg.Options{Quality: 85}); err != nil {
+		return fmt.Errorf("encoding thumbnail: %s", err)
+	}
+	return jh.exifTIFF.SetThumbnail(bytes.NewReader(buf.Bytes()))
+}
+
+// RemoveThumbnail removes the EXIF thumbnail image.
+func (jh *JPEG) RemoveThumbnail() error {
+	return jh.exifTIFF.SetThumbnail(nil)
+}
+
+// shrinkImage returns a copy of the image, scaled down (preserving its aspect
+// ratio) to fit within maxW by maxH.  Each pixel of the result is the average
+// of the source pixels it covers.  Images that already fit are not enlarged.
+func shrinkImage(img image.Image, maxW, maxH int) image.Image {
+	var (
+		b    = img.Bounds()
+		w, h = b.Dx(), b.Dy()
+		tw   = w
+		th   = h
+	)
+	if tw > maxW {
+		tw, th = maxW, h*maxW/w
+	}
+	if th > maxH {
+		tw, th = w*maxH/h, maxH
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+	out := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := b.Min.Y+ty*h/th, b.Min.Y+(ty+1)*h/th
+		for tx := 0; tx < tw; tx++ {
+			var r, g, bl, n uint64
+
+			x0, x1 := b.Min.X+tx*w/tw, b.Min.X+(tx+1)*w/tw
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, _ := img.At(x, y).RGBA()
+					r, g, bl, n = r+uint64(pr), g+uint64(pg), bl+uint64(pb), n+1
+				}
+			}
+			out.SetRGBA(tx, ty, color.RGBA{
+				R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(bl / n >> 8), A: 0xFF,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,301 @@
+package filefmts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/filefmts/xmp"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+	"github.com/rothskeller/photo-tools/metadata/providers/readonly"
+)
+
+// Precedence determines whether the image file or its sidecar files supply a
+// metadata value when both have one.
+type Precedence int
+
+// Values for Precedence:
+const (
+	// ImageFirst gives precedence to the metadata in the image file.
+	ImageFirst Precedence = iota
+	// SidecarFirst gives precedence to the metadata in the sidecar files,
+	// as Lightroom and darktable do.
+	SidecarFirst
+)
+
+// WritePolicy determines which files get written when metadata change.
+type WritePolicy int
+
+// Values for WritePolicy:
+const (
+	// WriteAll writes changes to the image file and to all of its sidecar
+	// files.  No sidecar file is created if there isn't one already.
+	WriteAll WritePolicy = iota
+	// WriteSidecar writes changes only to the sidecar files, leaving the
+	// image file untouched.  A sidecar file is created if there isn't one
+	// already.
+	WriteSidecar
+)
+
+// A MediaFile is a single logical media file, comprising an image (or other
+// media) file and any XMP sidecar files that accompany it.  Its provider
+// merges the metadata from all of them, and its Save method writes changes to
+// them according to its write policy.
+type MediaFile struct {
+	path     string
+	fh       *os.File
+	image    FileFormat
+	sidecars []*sidecar
+	provider multi.Provider
+}
+
+// A sidecar is one XMP sidecar file of a MediaFile.
+type sidecar struct {
+	path    string
+	handler *xmp.XMP
+}
+
+// OpenMediaFile opens the specified media file and any XMP sidecar files that
+// accompany it, using the specified precedence and write policy.  It returns
+// nil, nil if there is no handler for the media file's type.  It returns an
+// error if any of the files cannot be read, or if their handlers find a problem
+// with them.
+//
+// Handlers that always save to a sidecar file (i.e., camera raw handlers)
+// manage their own sidecar files; for them, the precedence and write policy
+// are ignored.  Likewise, an XMP file is never given sidecar files of its own.
+func OpenMediaFile(path string, precedence Precedence, policy WritePolicy) (mf *MediaFile, err error) {
+	var imageProvider metadata.Provider
+
+	mf = &MediaFile{path: path}
+	if mf.fh, err = os.Open(path); err != nil {
+		return nil, err
+	}
+	if mf.image, err = HandlerFor(mf.fh); err != nil || mf.image == nil {
+		mf.fh.Close()
+		return nil, err
+	}
+	if _, ok := mf.image.(SidecarFormat); ok {
+		mf.provider = multi.Provider{mf.image.Provider()}
+		return mf, nil
+	}
+	if _, ok := mf.image.(*xmp.XMP); ok {
+		mf.provider = multi.Provider{mf.image.Provider()}
+		return mf, nil
+	}
+	for _, name := range SidecarNames(path) {
+		var sc = sidecar{path: name}
+
+		if sc.handler, err = readSidecar(name); err != nil {
+			mf.fh.Close()
+			return nil, err
+		}
+		mf.sidecars = append(mf.sidecars, &sc)
+	}
+	if len(mf.sidecars) == 0 && policy == WriteSidecar {
+		var sc = sidecar{path: path + ".xmp"}
+
+		if sc.handler, err = xmp.New(); err != nil {
+			mf.fh.Close()
+			return nil, err
+		}
+		mf.sidecars = append(mf.sidecars, &sc)
+	}
+	imageProvider = mf.image.Provider()
+	if policy == WriteSidecar {
+		imageProvider = readonly.New(imageProvider)
+	}
+	if precedence == ImageFirst {
+		mf.provider = append(mf.provider, imageProvider)
+	}
+	for _, sc := range mf.sidecars {
+		mf.provider = append(mf.provider, sc.handler.Provider())
+	}
+	if precedence == SidecarFirst {
+		mf.provider = append(mf.provider, imageProvider)
+	}
+	return mf, nil
+}
+
+// readSidecar reads the specified XMP sidecar file.
+func readSidecar(name string) (h *xmp.XMP, err error) {
+	var fh *os.File
+
+	if fh, err = os.Open(name); err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	if h, err = xmp.Read(reader{fh}); err != nil {
+		return nil, fmt.Errorf("%s: %s", name, err)
+	}
+	if h == nil {
+		return nil, fmt.Errorf("%s: not an XMP file", name)
+	}
+	return h, nil
+}
+
+// SidecarNames returns the names of the existing XMP sidecar files for the
+// specified media file.  It recognizes both the "NAME.EXT.xmp" convention used
+// by darktable and digiKam, and the "NAME.xmp" convention used by Lightroom
+// and other Adobe software, with either case of extension.  A "NAME.xmp" file
+// is recognized only if no other file shares the media file's NAME (e.g., the
+// JPEG of a RAW+JPEG pair), since otherwise it isn't clear which file it
+// belongs to.
+func SidecarNames(path string) (names []string) {
+	var (
+		base       = path[:len(path)-len(filepath.Ext(path))]
+		candidates = []string{path + ".xmp", path + ".XMP"}
+		infos      []os.FileInfo
+	)
+	if !sharesBaseName(path) {
+		candidates = append(candidates, base+".xmp", base+".XMP")
+	}
+NAMES:
+	for _, name := range candidates {
+		info, err := os.Stat(name)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		// On case-insensitive file systems, the same file may be
+		// found under more than one name.
+		for _, seen := range infos {
+			if os.SameFile(info, seen) {
+				continue NAMES
+			}
+		}
+		infos = append(infos, info)
+		names = append(names, name)
+	}
+	return names
+}
+
+// SidecarName returns the name of the XMP sidecar file to which changes to the
+// specified media file should be written.  It is the first of its existing
+// sidecar files, or "NAME.EXT.xmp" if it has none.
+func SidecarName(path string) string {
+	if names := SidecarNames(path); len(names) != 0 {
+		return names[0]
+	}
+	return path + ".xmp"
+}
+
+// sharesBaseName returns whether some other file in the same directory as the
+// specified media file has the same name up to its extension, not counting XMP
+// sidecar files.
+func sharesBaseName(path string) bool {
+	var (
+		dir   = filepath.Dir(path)
+		file  = filepath.Base(path)
+		base  = file[:len(file)-len(filepath.Ext(file))]
+		names []string
+		err   error
+	)
+	if names, err = filepath.Glob(filepath.Join(dir, globEscaper.Replace(base)) + ".*"); err != nil {
+		return false
+	}
+	for _, name := range names {
+		name = filepath.Base(name)
+		if name == file || strings.EqualFold(filepath.Ext(name), ".xmp") {
+			continue
+		}
+		if name[:len(name)-len(filepath.Ext(name))] == base {
+			return true
+		}
+	}
+	return false
+}
+
+// globEscaper escapes the characters that are special in glob patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`)
+
+// Path returns the path of the media file.
+func (mf *MediaFile) Path() string { return mf.path }
+
+// Handler returns the file format handler for the media file itself.
+func (mf *MediaFile) Handler() FileFormat { return mf.image }
+
+// Sidecars returns the paths of the XMP sidecar files of the media file,
+// including any that will be created when it is saved.  For handlers that
+// manage their own sidecar file, that is the one returned.
+func (mf *MediaFile) Sidecars() (paths []string) {
+	if sf, ok := mf.image.(SidecarFormat); ok {
+		paths = append(paths, sf.Sidecar())
+	}
+	for _, sc := range mf.sidecars {
+		paths = append(paths, sc.path)
+	}
+	return paths
+}
+
+// Provider returns the metadata.Provider for the media file.
+func (mf *MediaFile) Provider() metadata.Provider { return mf.provider }
+
+// Dirty returns whether the metadata of the media file have been changed since
+// they were read (and therefore need to be saved).
+func (mf *MediaFile) Dirty() bool {
+	if mf.image.Dirty() {
+		return true
+	}
+	for _, sc := range mf.sidecars {
+		if sc.handler.Dirty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Transform losslessly transforms the image data of the media file, as
+// described for TransformableFormat, and makes the same adjustments to the
+// orientation and face regions in its sidecar files:  the orientation is reset
+// and each face region is transformed.  It returns metadata.ErrNotSupported if
+// the media file's handler can't do it.
+func (mf *MediaFile) Transform(o metadata.Orientation) (err error) {
+	var orig, kept metadata.Dimensions
+
+	th, ok := mf.image.(TransformableFormat)
+	if !ok {
+		return metadata.ErrNotSupported
+	}
+	if orig, kept, err = th.Transform(o); err != nil {
+		return err
+	}
+	for _, sc := range mf.sidecars {
+		sp := sc.handler.Provider()
+		if so := sp.Orientation(); so != 0 && so != metadata.Rotate0 {
+			if err = sp.SetOrientation(metadata.Rotate0); err != nil {
+				return fmt.Errorf("%s: %s", sc.path, err)
+			}
+		}
+		if faces := sp.Faces(); len(faces) != 0 {
+			for i := range faces {
+				faces[i] = faces[i].Transformed(o, orig.Width, orig.Height, kept.Width, kept.Height)
+			}
+			if err = sp.SetFaces(faces); err != nil {
+				return fmt.Errorf("%s: %s", sc.path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Close closes the media file.  The MediaFile can't be used after that.
+func (mf *MediaFile) Close() error { return mf.fh.Close() }
+
+// Save writes each of the files of the media file that has changed.
+func (mf *MediaFile) Save() (err error) {
+	if mf.image.Dirty() {
+		if err = Save(mf.image, mf.path); err != nil {
+			return err
+		}
+	}
+	for _, sc := range mf.sidecars {
+		if sc.handler.Dirty() {
+			if err = Save(sc.handler, sc.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+package filefmts
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/filefmts/xmp"
+)
+
+// makeJPEG writes a JPEG file with the specified caption (if any).
+func makeJPEG(t *testing.T, path, caption string) {
+	var buf bytes.Buffer
+
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if caption == "" {
+		return
+	}
+	h, err := HandlerForName(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Provider().SetCaption(caption); err != nil {
+		t.Fatal(err)
+	}
+	if err = Save(h, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// makeXMP writes an XMP sidecar file with the specified caption.
+func makeXMP(t *testing.T, path, caption string) {
+	h, err := xmp.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Provider().SetCaption(caption); err != nil {
+		t.Fatal(err)
+	}
+	if err = Save(h, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readTitle returns the title recorded in the specified file alone.
+func readTitle(t *testing.T, path string) string {
+	h, err := HandlerForName(path)
+	if err != nil || h == nil {
+		t.Fatalf("%s: %v, %v", path, h, err)
+	}
+	return h.Provider().Title()
+}
+
+// openMediaFile opens a media file, failing the test if that fails.
+func openMediaFile(t *testing.T, path string, precedence Precedence, policy WritePolicy) *MediaFile {
+	mf, err := OpenMediaFile(path, precedence, policy)
+	if err != nil || mf == nil {
+		t.Fatalf("OpenMediaFile: %v, %v", mf, err)
+	}
+	t.Cleanup(func() { mf.Close() })
+	return mf
+}
+
+func TestPrecedence(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		path = filepath.Join(dir, "IMG_0001.jpg")
+	)
+	makeJPEG(t, path, "from image")
+	makeXMP(t, path+".xmp", "from sidecar")
+	if got := openMediaFile(t, path, ImageFirst, WriteAll).Provider().Caption(); got != "from image" {
+		t.Errorf("ImageFirst: got %q", got)
+	}
+	if got := openMediaFile(t, path, SidecarFirst, WriteAll).Provider().Caption(); got != "from sidecar" {
+		t.Errorf("SidecarFirst: got %q", got)
+	}
+}
+
+func TestWriteAll(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		path = filepath.Join(dir, "IMG_0001.jpg")
+	)
+	makeJPEG(t, path, "from image")
+	makeXMP(t, filepath.Join(dir, "IMG_0001.xmp"), "from sidecar")
+	mf := openMediaFile(t, path, ImageFirst, WriteAll)
+	if err := mf.Provider().SetTitle("New Title"); err != nil {
+		t.Fatal(err)
+	}
+	if !mf.Dirty() {
+		t.Fatal("not dirty after change")
+	}
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTitle(t, path); got != "New Title" {
+		t.Errorf("image: got %q", got)
+	}
+	if got := readTitle(t, filepath.Join(dir, "IMG_0001.xmp")); got != "New Title" {
+		t.Errorf("sidecar: got %q", got)
+	}
+}
+
+func TestWriteSidecar(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		path = filepath.Join(dir, "IMG_0001.jpg")
+	)
+	makeJPEG(t, path, "from image")
+	orig, _ := os.ReadFile(path)
+	mf := openMediaFile(t, path, SidecarFirst, WriteSidecar)
+	if got := mf.Sidecars(); !reflect.DeepEqual(got, []string{path + ".xmp"}) {
+		t.Errorf("sidecars: got %v", got)
+	}
+	if err := mf.Provider().SetTitle("New Title"); err != nil {
+		t.Fatal(err)
+	}
+	if got := mf.Provider().Title(); got != "New Title" {
+		t.Errorf("title: got %q", got)
+	}
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if by, _ := os.ReadFile(path); !bytes.Equal(by, orig) {
+		t.Error("image file changed")
+	}
+	if got := readTitle(t, path+".xmp"); got != "New Title" {
+		t.Errorf("sidecar: got %q", got)
+	}
+	if got := openMediaFile(t, path, SidecarFirst, WriteSidecar).Provider().Caption(); got != "from image" {
+		t.Errorf("caption: got %q", got)
+	}
+}
+
+func TestTransformFaces(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		path  = filepath.Join(dir, "IMG_0001.jpg")
+		buf   bytes.Buffer
+		faces = []metadata.FaceRegion{{
+			Name: "Face", X: 0.25, Y: 0.5, W: 0.1, H: 0.2,
+			Unit: "normalized", AppliedToW: 32, AppliedToH: 16,
+		}}
+	)
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 32, 16)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{path, path + ".xmp"} {
+		var h FileFormat
+		var err error
+
+		if name == path {
+			h, err = HandlerForName(name)
+		} else {
+			h, err = xmp.New()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = h.Provider().SetFaces(faces); err != nil {
+			t.Fatal(err)
+		}
+		if err = Save(h, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mf := openMediaFile(t, path, ImageFirst, WriteAll)
+	if err := mf.Transform(metadata.Rotate90); err != nil {
+		t.Fatal(err)
+	}
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{path, path + ".xmp"} {
+		h, err := HandlerForName(name)
+		if err != nil || h == nil {
+			t.Fatalf("%s: %v, %v", name, h, err)
+		}
+		got := h.Provider().Faces()
+		if len(got) != 1 {
+			t.Fatalf("%s: got %v", name, got)
+		}
+		if got[0].AppliedToW != 16 || got[0].AppliedToH != 32 {
+			t.Errorf("%s: got dimensions %dx%d, want 16x32", name, got[0].AppliedToW, got[0].AppliedToH)
+		}
+		if want := faces[0].Transformed(metadata.Rotate90, 32, 16, 32, 16); !got[0].Equivalent(want) {
+			t.Errorf("%s: got %v, want %v", name, got[0], want)
+		}
+	}
+}
+
+// TestTransformSidecarFaces checks that the face regions in a sidecar file are
+// transformed on their own, whether they differ from those in the image or the
+// image has none.
+func TestTransformSidecarFaces(t *testing.T) {
+	var (
+		imageFaces = []metadata.FaceRegion{{
+			Name: "Image Face", X: 0.25, Y: 0.5, W: 0.1, H: 0.2,
+			Unit: "normalized", AppliedToW: 32, AppliedToH: 16,
+		}}
+		sidecarFaces = []metadata.FaceRegion{{
+			Name: "Sidecar Face", X: 0.6, Y: 0.1, W: 0.3, H: 0.4,
+			Unit: "normalized", AppliedToW: 32, AppliedToH: 16,
+		}}
+	)
+	for _, test := range []struct {
+		name  string
+		image []metadata.FaceRegion
+	}{
+		{"different faces", imageFaces},
+		{"no image faces", nil},
+	} {
+		var (
+			path = filepath.Join(t.TempDir(), "IMG_0001.jpg")
+			buf  bytes.Buffer
+		)
+		if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 32, 16)), nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if test.image != nil {
+			h, err := HandlerForName(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = h.Provider().SetFaces(test.image); err != nil {
+				t.Fatal(err)
+			}
+			if err = Save(h, path); err != nil {
+				t.Fatal(err)
+			}
+		}
+		h, err := xmp.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = h.Provider().SetFaces(sidecarFaces); err != nil {
+			t.Fatal(err)
+		}
+		if err = h.Provider().SetOrientation(metadata.Rotate90); err != nil {
+			t.Fatal(err)
+		}
+		if err = Save(h, path+".xmp"); err != nil {
+			t.Fatal(err)
+		}
+		mf := openMediaFile(t, path, SidecarFirst, WriteAll)
+		if err = mf.Transform(metadata.Rotate90); err != nil {
+			t.Fatal(err)
+		}
+		if err = mf.Save(); err != nil {
+			t.Fatal(err)
+		}
+		var rh FileFormat
+		for _, check := range []struct {
+			path  string
+			faces []metadata.FaceRegion
+		}{
+			{path, test.image},
+			{path + ".xmp", sidecarFaces},
+		} {
+			if rh, err = HandlerForName(check.path); err != nil || rh == nil {
+				t.Fatalf("%s: %s: %v, %v", test.name, check.path, rh, err)
+			}
+			got := rh.Provider().Faces()
+			if len(got) != len(check.faces) {
+				t.Errorf("%s: %s: got %v, want %v", test.name, check.path, got, check.faces)
+				continue
+			}
+			for i := range got {
+				if want := check.faces[i].Transformed(metadata.Rotate90, 32, 16, 32, 16); !got[i].Equivalent(want) {
+					t.Errorf("%s: %s: got %v, want %v", test.name, check.path, got[i], want)
+				}
+			}
+		}
+		if o := rh.Provider().Orientation(); o != 0 && o != metadata.Rotate0 {
+			t.Errorf("%s: sidecar orientation: got %v", test.name, o)
+		}
+	}
+}
+
+func TestTransformThumbnail(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), "IMG_0001.jpg")
+		buf  bytes.Buffer
+	)
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 32, 16)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h, err := HandlerForName(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = h.(ThumbnailFormat).RegenerateThumbnail(); err != nil {
+		t.Fatal(err)
+	}
+	if err = Save(h, path); err != nil {
+		t.Fatal(err)
+	}
+	mf := openMediaFile(t, path, ImageFirst, WriteAll)
+	if err = mf.Transform(metadata.Rotate90); err != nil {
+		t.Fatal(err)
+	}
+	if err = mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if h, err = HandlerForName(path); err != nil {
+		t.Fatal(err)
+	}
+	thumb, err := h.(ThumbnailFormat).Thumbnail()
+	if err != nil || thumb == nil {
+		t.Fatalf("Thumbnail: %v, %v", thumb, err)
+	}
+	cfg, err := jpeg.DecodeConfig(thumb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != 16 || cfg.Height != 32 {
+		t.Errorf("got thumbnail %dx%d, want 16x32", cfg.Width, cfg.Height)
+	}
+}
+
+func TestSidecarNames(t *testing.T) {
+	var dir = t.TempDir()
+
+	touch := func(name string) {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	touch("A.jpg")
+	touch("A.xmp")
+	touch("B.cr2")
+	touch("B.jpg")
+	touch("B.xmp")
+	touch("B.cr2.xmp")
+	touch("C.nef")
+	if got, want := SidecarNames(filepath.Join(dir, "A.jpg")), []string{filepath.Join(dir, "A.xmp")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("A.jpg: got %v, want %v", got, want)
+	}
+	if got := SidecarNames(filepath.Join(dir, "B.jpg")); len(got) != 0 {
+		t.Errorf("B.jpg: got %v, want none", got)
+	}
+	if got, want := SidecarName(filepath.Join(dir, "B.cr2")), filepath.Join(dir, "B.cr2.xmp"); got != want {
+		t.Errorf("B.cr2: got %s, want %s", got, want)
+	}
+	if got, want := SidecarName(filepath.Join(dir, "C.nef")), filepath.Join(dir, "C.nef.xmp"); got != want {
+		t.Errorf("C.nef: got %s, want %s", got, want)
+	}
+}
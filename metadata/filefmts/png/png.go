@@ -0,0 +1,156 @@
+// Package png contains the file format handler for PNG files.
+package png
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/png"
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+	"github.com/rothskeller/photo-tools/metadata/providers/exififd"
+	"github.com/rothskeller/photo-tools/metadata/providers/gpsifd"
+	"github.com/rothskeller/photo-tools/metadata/providers/jpegifd0"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
+	"github.com/rothskeller/photo-tools/metadata/providers/xp"
+)
+
+const (
+	tagEXIFIFD uint16 = 0x8769
+	tagGPSIFD  uint16 = 0x8825
+)
+
+var pngSignature = []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+
+// PNG is a PNG file handler.
+type PNG struct {
+	container *png.PNG
+	exifTIFF  *tiff.TIFF
+	xmpRDF    *rdf.Packet
+	providers multi.Provider
+}
+
+// Read reads the provided file.  It returns nil, nil, if the file is not a PNG
+// file.  It returns an error if the file is a PNG file but ill-formed, or if a
+// read error occurs.  It returns a PNG file handler for the file if it is read
+// successfully.
+func Read(r metadata.Reader) (ph *PNG, err error) {
+	var (
+		buf        [8]byte
+		xpProvider *xp.Provider
+	)
+
+	if _, err = r.ReadAt(buf[:], 0); err == io.EOF {
+		return nil, nil // can't read a signature, assume it's not PNG
+	} else if err != nil {
+		return nil, err
+	} else if !bytes.Equal(buf[:], pngSignature) {
+		return nil, nil // not a PNG file
+	}
+	ph = new(PNG)
+	ph.container = new(png.PNG)
+	if err = ph.container.Read(r); err != nil {
+		return nil, err
+	}
+	if err = ph.readEXIFChunk(); err != nil {
+		return nil, err
+	}
+	if err = ph.readXMPChunk(); err != nil {
+		return nil, err
+	}
+	// The Windows XP* tags come last, so that they have the lowest
+	// priority.
+	if xpProvider, err = xp.New(ph.exifTIFF.IFD0(), ph.providers); err != nil {
+		return nil, err
+	}
+	ph.providers = append(ph.providers, xpProvider)
+	return ph, nil
+}
+
+// Provider returns the metadata.Provider for the PNG file.
+func (ph *PNG) Provider() metadata.Provider { return ph.providers }
+
+func (ph *PNG) readEXIFChunk() (err error) {
+	var (
+		exifChunk        metadata.Reader
+		ifd0             *tiff.IFD
+		exifIFD          *tiff.IFD
+		gpsIFD           *tiff.IFD
+		jpegIFD0Provider *jpegifd0.Provider
+		exifIFDProvider  *exififd.Provider
+		gpsIFDProvider   *gpsifd.Provider
+	)
+	ph.exifTIFF = new(tiff.TIFF)
+	if exifChunk = ph.container.EXIF(); exifChunk != nil {
+		if err = ph.exifTIFF.Read(exifChunk); err != nil {
+			return fmt.Errorf("eXIf chunk: %s", err)
+		}
+	}
+	ph.container.SetEXIFContainer(ph.exifTIFF)
+	// The IFD0 of a PNG eXIf chunk has the same semantics as that of a JPEG
+	// EXIF segment, so we use the same provider for it.
+	ifd0 = ph.exifTIFF.IFD0()
+	if jpegIFD0Provider, err = jpegifd0.New(ifd0); err != nil {
+		return err
+	}
+	ph.providers = append(ph.providers, jpegIFD0Provider)
+	if tag := ifd0.Tag(tagEXIFIFD); tag != nil {
+		if exifIFD, err = tag.AsIFD(); err != nil {
+			return fmt.Errorf("EXIF IFD: %s", err)
+		}
+	} else {
+		tag = ifd0.AddTag(tagEXIFIFD, 4)
+		exifIFD, _ = tag.AddIFD()
+	}
+	if exifIFDProvider, err = exififd.New(exifIFD, ph.exifTIFF.Encoding()); err != nil {
+		return err
+	}
+	ph.providers = append(ph.providers, exifIFDProvider)
+	if tag := ifd0.Tag(tagGPSIFD); tag != nil {
+		if gpsIFD, err = tag.AsIFD(); err != nil {
+			return fmt.Errorf("GPS IFD: %s", err)
+		}
+	} else {
+		tag = ifd0.AddTag(tagGPSIFD, 4)
+		gpsIFD, _ = tag.AddIFD()
+	}
+	if gpsIFDProvider, err = gpsifd.New(gpsIFD); err != nil {
+		return err
+	}
+	ph.providers = append(ph.providers, gpsIFDProvider)
+	return nil
+}
+
+func (ph *PNG) readXMPChunk() (err error) {
+	var (
+		xmpChunk    metadata.Reader
+		xmpProvider *xmp.Provider
+	)
+	ph.xmpRDF = rdf.New()
+	if xmpChunk = ph.container.XMP(); xmpChunk != nil {
+		if err = ph.xmpRDF.Read(xmpChunk); err != nil {
+			return fmt.Errorf("XMP: %s", err)
+		}
+	}
+	ph.container.SetXMPContainer(ph.xmpRDF)
+	if xmpProvider, err = xmp.New(ph.xmpRDF); err != nil {
+		return err
+	}
+	ph.providers = append(ph.providers, xmpProvider)
+	return nil
+}
+
+// Dirty returns whether the metadata from the file have been changed since they
+// were read (and therefore need to be saved).
+func (ph *PNG) Dirty() bool { return ph.container.Dirty() }
+
+// Save writes the entire file to the supplied writer, including all revised
+// metadata.
+func (ph *PNG) Save(out io.Writer) (err error) {
+	ph.container.Layout()
+	_, err = ph.container.Write(out)
+	return err
+}
@@ -0,0 +1,146 @@
+// Package quicktime contains the file format handler for QuickTime and MP4
+// movie files.
+package quicktime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/isobmff"
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+	"github.com/rothskeller/photo-tools/metadata/providers/quicktime"
+	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
+)
+
+// firstBoxTypes is the set of box types that can start a QuickTime movie file
+// that has no file type box.
+var firstBoxTypes = map[string]bool{
+	"moov": true, "mdat": true, "wide": true, "free": true, "skip": true, "pnot": true,
+}
+
+// movieBrands is the set of file type brands that identify QuickTime and MP4
+// movie files.  (Brands starting with "3gp" or "3g2" also identify movies.)
+var movieBrands = map[string]bool{
+	"qt  ": true, "mp41": true, "mp42": true, "mp71": true, "M4V ": true,
+	"M4VH": true, "M4VP": true, "avc1": true, "f4v ": true, "MSNV": true,
+	"XAVC": true, "dash": true,
+}
+
+// isoBrands is the set of generic ISO base media file format brands.  Since
+// other ISO-based formats (e.g. Canon CR3 raw files) list them as compatible
+// brands, they identify a movie file only when they are the major brand.
+var isoBrands = map[string]bool{
+	"isom": true, "iso2": true, "iso3": true, "iso4": true, "iso5": true,
+	"iso6": true, "iso7": true, "iso8": true, "iso9": true,
+}
+
+// QuickTime is a QuickTime or MP4 movie file handler.
+type QuickTime struct {
+	container *isobmff.ISOBMFF
+	xmpRDF    *rdf.Packet
+	providers multi.Provider
+}
+
+// Read reads the provided file.  It returns nil, nil, if the file is not a
+// movie file.  It returns an error if the file is a movie file but ill-formed,
+// or if a read error occurs.  It returns a QuickTime file handler for the file
+// if it is read successfully.
+//
+// Note that HEIF files also start with a file type box, so this handler must
+// be tried after the HEIF handler.
+func Read(r metadata.Reader) (qh *QuickTime, err error) {
+	var qtProvider *quicktime.Provider
+
+	if !isMovie(r) {
+		return nil, nil
+	}
+	qh = new(QuickTime)
+	qh.container = new(isobmff.ISOBMFF)
+	if err = qh.container.Read(r); err != nil {
+		return nil, err
+	}
+	if !qh.container.HasMovie() {
+		return nil, errors.New("QuickTime: no moov box")
+	}
+	if qtProvider, err = quicktime.New(qh.container); err != nil {
+		return nil, err
+	}
+	qh.providers = append(qh.providers, qtProvider)
+	if err = qh.readXMP(); err != nil {
+		return nil, err
+	}
+	return qh, nil
+}
+
+// isMovie returns whether the file starts with a file type box naming one of
+// the movie brands, or with one of the boxes that can start a QuickTime file
+// without one.
+func isMovie(r metadata.Reader) bool {
+	var buf [64]byte
+
+	n, _ := r.ReadAt(buf[:], 0)
+	if n < 8 {
+		return false
+	}
+	if string(buf[4:8]) != "ftyp" {
+		return firstBoxTypes[string(buf[4:8])]
+	}
+	if n < 12 {
+		return false
+	}
+	size := int(buf[0])<<24 | int(buf[1])<<16 | int(buf[2])<<8 | int(buf[3])
+	if size > n {
+		size = n
+	}
+	if isMovieBrand(string(buf[8:12])) || isoBrands[string(buf[8:12])] {
+		return true
+	}
+	for i := 16; i+4 <= size; i += 4 {
+		if isMovieBrand(string(buf[i : i+4])) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMovieBrand returns whether the file type brand identifies a movie file.
+func isMovieBrand(brand string) bool {
+	return movieBrands[brand] || brand[:3] == "3gp" || brand[:3] == "3g2"
+}
+
+// Provider returns the metadata.Provider for the movie file.
+func (qh *QuickTime) Provider() metadata.Provider { return qh.providers }
+
+func (qh *QuickTime) readXMP() (err error) {
+	var (
+		xmpBox      metadata.Reader
+		xmpProvider *xmp.Provider
+	)
+	qh.xmpRDF = rdf.New()
+	if xmpBox = qh.container.XMP(); xmpBox != nil {
+		if err = qh.xmpRDF.Read(xmpBox); err != nil {
+			return fmt.Errorf("XMP: %s", err)
+		}
+	}
+	qh.container.SetXMPContainer(qh.xmpRDF)
+	if xmpProvider, err = xmp.New(qh.xmpRDF); err != nil {
+		return err
+	}
+	qh.providers = append(qh.providers, xmpProvider)
+	return nil
+}
+
+// Dirty returns whether the metadata from the file have been changed since they
+// were read (and therefore need to be saved).
+func (qh *QuickTime) Dirty() bool { return qh.container.Dirty() }
+
+// Save writes the entire file to the supplied writer, including all revised
+// metadata.
+func (qh *QuickTime) Save(out io.Writer) (err error) {
+	qh.container.Layout()
+	_, err = qh.container.Write(out)
+	return err
+}
@@ -0,0 +1,366 @@
+package raw
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+)
+
+// provider is the metadata.Provider for a raw file.  It passes everything
+// through to the underlying providers, except that the first change to any
+// field first seeds the sidecar with the embedded values of all fields, and
+// then stops consulting the embedded values of the fields the sidecar holds.
+// That way, the sidecar is authoritative from then on, and clearing a field
+// in it doesn't reveal the embedded value.
+type provider struct {
+	metadata.Provider
+	h      *Raw
+	seeded bool
+}
+
+// seed copies the embedded values of all fields that the sidecar can hold into
+// the sidecar, unless that has already been done (or the sidecar already
+// existed).
+func (p *provider) seed() (err error) {
+	var e = p.h.embedded
+
+	if p.seeded {
+		return nil
+	}
+	p.seeded = true
+	p.Provider = multi.Provider{p.h.sidecarXMP, fixedFields{p: e}}
+	return copyFields(p.h.sidecarXMP, e)
+}
+
+// copyFields copies the values of all writable fields from one provider to
+// another.  Empty values are not copied.
+func copyFields(to, from metadata.Provider) (err error) {
+	if v := from.CaptionAlt(); !v.Empty() {
+		err = setErr(err, to.SetCaptionAlt(v))
+	}
+	if v := from.Copyright(); v != "" {
+		err = setErr(err, to.SetCopyright(v))
+	}
+	if v := from.Creator(); v != "" {
+		err = setErr(err, to.SetCreator(v))
+	}
+	if v := from.Credit(); v != "" {
+		err = setErr(err, to.SetCredit(v))
+	}
+	if v := from.DateTime(); !v.Empty() {
+		err = setErr(err, to.SetDateTime(v))
+	}
+	if v := from.Faces(); len(v) != 0 {
+		err = setErr(err, to.SetFaces(v))
+	}
+	if v := from.GPS(); !v.Empty() {
+		err = setErr(err, to.SetGPS(v))
+	}
+	if v := from.Groups(); len(v) != 0 {
+		err = setErr(err, to.SetGroups(v))
+	}
+	if v := from.Keywords(); len(v) != 0 {
+		err = setErr(err, to.SetKeywords(v))
+	}
+	if v := from.Label(); v != "" {
+		err = setErr(err, to.SetLabel(v))
+	}
+	if v := from.License(); v != "" {
+		err = setErr(err, to.SetLicense(v))
+	}
+	if v := from.Location(); !v.Empty() {
+		err = setErr(err, to.SetLocation(v))
+	}
+	if v := from.LocationsShown(); len(v) != 0 {
+		err = setErr(err, to.SetLocationsShown(v))
+	}
+	if v := from.Orientation(); v != 0 {
+		err = setErr(err, to.SetOrientation(v))
+	}
+	if v := from.People(); len(v) != 0 {
+		err = setErr(err, to.SetPeople(v))
+	}
+	if v := from.Places(); len(v) != 0 {
+		err = setErr(err, to.SetPlaces(v))
+	}
+	if v := from.Rating(); v != 0 {
+		err = setErr(err, to.SetRating(v))
+	}
+	if v := from.Source(); v != "" {
+		err = setErr(err, to.SetSource(v))
+	}
+	if v := from.TitleAlt(); !v.Empty() {
+		err = setErr(err, to.SetTitleAlt(v))
+	}
+	if v := from.Topics(); len(v) != 0 {
+		err = setErr(err, to.SetTopics(v))
+	}
+	if v := from.UsageTerms(); v != "" {
+		err = setErr(err, to.SetUsageTerms(v))
+	}
+	return err
+}
+
+// setErr returns the first of two errors, ignoring metadata.ErrNotSupported.
+func setErr(err, nerr error) error {
+	if err != nil || nerr == metadata.ErrNotSupported {
+		return err
+	}
+	return nerr
+}
+
+// SetCaption sets the value of the Caption field.
+func (p *provider) SetCaption(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetCaption(value)
+}
+
+// SetCaptionAlt sets the value of the CaptionAlt field.
+func (p *provider) SetCaptionAlt(value metadata.AltString) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetCaptionAlt(value)
+}
+
+// SetCopyright sets the value of the Copyright field.
+func (p *provider) SetCopyright(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetCopyright(value)
+}
+
+// SetCreator sets the value of the Creator field.
+func (p *provider) SetCreator(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetCreator(value)
+}
+
+// SetCredit sets the value of the Credit field.
+func (p *provider) SetCredit(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetCredit(value)
+}
+
+// SetDateTime sets the value of the DateTime field.
+func (p *provider) SetDateTime(value metadata.DateTime) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetDateTime(value)
+}
+
+// SetFaces sets the values of the Faces field.
+func (p *provider) SetFaces(values []metadata.FaceRegion) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetFaces(values)
+}
+
+// SetGPS sets the value of the GPS field.
+func (p *provider) SetGPS(value metadata.GPSCoords) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetGPS(value)
+}
+
+// SetGroups sets the values of the Groups field.
+func (p *provider) SetGroups(values []metadata.HierValue) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetGroups(values)
+}
+
+// SetKeywords sets the values of the Keywords field.
+func (p *provider) SetKeywords(values []metadata.HierValue) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetKeywords(values)
+}
+
+// SetLabel sets the value of the Label field.
+func (p *provider) SetLabel(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetLabel(value)
+}
+
+// SetLicense sets the value of the License field.
+func (p *provider) SetLicense(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetLicense(value)
+}
+
+// SetLocation sets the values of the Location field.
+func (p *provider) SetLocation(values metadata.Location) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetLocation(values)
+}
+
+// SetLocationsShown sets the values of the LocationsShown field.
+func (p *provider) SetLocationsShown(values []metadata.Location) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetLocationsShown(values)
+}
+
+// SetOrientation sets the value of the Orientation field.
+func (p *provider) SetOrientation(value metadata.Orientation) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetOrientation(value)
+}
+
+// SetPeople sets the values of the People field.
+func (p *provider) SetPeople(values []string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetPeople(values)
+}
+
+// SetPlaces sets the values of the Places field.
+func (p *provider) SetPlaces(values []metadata.HierValue) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetPlaces(values)
+}
+
+// SetRating sets the value of the Rating field.
+func (p *provider) SetRating(value int) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetRating(value)
+}
+
+// SetSource sets the value of the Source field.
+func (p *provider) SetSource(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetSource(value)
+}
+
+// SetTitle sets the value of the Title field.
+func (p *provider) SetTitle(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetTitle(value)
+}
+
+// SetTitleAlt sets the value of the TitleAlt field.
+func (p *provider) SetTitleAlt(value metadata.AltString) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetTitleAlt(value)
+}
+
+// SetTopics sets the values of the Topics field.
+func (p *provider) SetTopics(values []metadata.HierValue) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetTopics(values)
+}
+
+// SetUsageTerms sets the value of the UsageTerms field.
+func (p *provider) SetUsageTerms(value string) error {
+	if err := p.seed(); err != nil {
+		return err
+	}
+	return p.Provider.SetUsageTerms(value)
+}
+
+// fixedFields presents only the read-only fields of the provider it wraps,
+// i.e., the ones that the sidecar can't hold.
+type fixedFields struct {
+	metadata.BaseProvider
+	p metadata.Provider
+}
+
+// ProviderName is the name for the provider, for debug purposes.
+func (f fixedFields) ProviderName() string { return f.p.ProviderName() + " (read-only fields)" }
+
+// Camera returns the value of the Camera field.
+func (f fixedFields) Camera() string { return f.p.Camera() }
+
+// CameraTags returns a list of tag names for the Camera field, and a parallel
+// list of values held by those tags.
+func (f fixedFields) CameraTags() ([]string, []string) { return f.p.CameraTags() }
+
+// Dimensions returns the value of the Dimensions field.
+func (f fixedFields) Dimensions() metadata.Dimensions { return f.p.Dimensions() }
+
+// DimensionsTags returns a list of tag names for the Dimensions field, and a
+// parallel list of values held by those tags.
+func (f fixedFields) DimensionsTags() ([]string, []metadata.Dimensions) { return f.p.DimensionsTags() }
+
+// ExposureTime returns the value of the ExposureTime field.
+func (f fixedFields) ExposureTime() float64 { return f.p.ExposureTime() }
+
+// ExposureTimeTags returns a list of tag names for the ExposureTime field, and
+// a parallel list of values held by those tags.
+func (f fixedFields) ExposureTimeTags() ([]string, []float64) { return f.p.ExposureTimeTags() }
+
+// FNumber returns the value of the FNumber field.
+func (f fixedFields) FNumber() float64 { return f.p.FNumber() }
+
+// FNumberTags returns a list of tag names for the FNumber field, and a parallel
+// list of values held by those tags.
+func (f fixedFields) FNumberTags() ([]string, []float64) { return f.p.FNumberTags() }
+
+// FocalLength returns the value of the FocalLength field.
+func (f fixedFields) FocalLength() float64 { return f.p.FocalLength() }
+
+// FocalLengthTags returns a list of tag names for the FocalLength field, and a
+// parallel list of values held by those tags.
+func (f fixedFields) FocalLengthTags() ([]string, []float64) { return f.p.FocalLengthTags() }
+
+// GPSDateTime returns the value of the GPSDateTime field.
+func (f fixedFields) GPSDateTime() metadata.DateTime { return f.p.GPSDateTime() }
+
+// GPSDateTimeTags returns a list of tag names for the GPSDateTime field, and a
+// parallel list of values held by those tags.
+func (f fixedFields) GPSDateTimeTags() ([]string, []metadata.DateTime) { return f.p.GPSDateTimeTags() }
+
+// ISO returns the value of the ISO field.
+func (f fixedFields) ISO() int { return f.p.ISO() }
+
+// ISOTags returns a list of tag names for the ISO field, and a parallel list
+// of values held by those tags.
+func (f fixedFields) ISOTags() ([]string, []int) { return f.p.ISOTags() }
+
+// Lens returns the value of the Lens field.
+func (f fixedFields) Lens() string { return f.p.Lens() }
+
+// LensTags returns a list of tag names for the Lens field, and a parallel list
+// of values held by those tags.
+func (f fixedFields) LensTags() ([]string, []string) { return f.p.LensTags() }
+
+// Serial returns the value of the Serial field.
+func (f fixedFields) Serial() string { return f.p.Serial() }
+
+// SerialTags returns a list of tag names for the Serial field, and a parallel
+// list of values held by those tags.
+func (f fixedFields) SerialTags() ([]string, []string) { return f.p.SerialTags() }
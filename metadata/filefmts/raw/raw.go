@@ -0,0 +1,203 @@
+// Package raw contains the file format handler for TIFF-based camera raw files
+// (CR2, NEF, ARW, DNG, etc.).  Since we can't safely rewrite proprietary raw
+// files, the metadata in the raw file itself are read-only; all changes are
+// written to an XMP sidecar file instead.
+package raw
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+	"github.com/rothskeller/photo-tools/metadata/providers/exififd"
+	"github.com/rothskeller/photo-tools/metadata/providers/gpsifd"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+	"github.com/rothskeller/photo-tools/metadata/providers/readonly"
+	"github.com/rothskeller/photo-tools/metadata/providers/tiffifd0"
+	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
+	"github.com/rothskeller/photo-tools/metadata/providers/xp"
+)
+
+const (
+	tagSubIFDs    uint16 = 0x014A
+	tagXMP        uint16 = 0x02BC
+	tagEXIFIFD    uint16 = 0x8769
+	tagGPSIFD     uint16 = 0x8825
+	tagDNGVersion uint16 = 0xC612
+	cr2Magic             = "CR\x02\x00"
+)
+
+var tiffHeaderLE = []byte{0x49, 0x49, 0x2A, 0x00}
+var tiffHeaderBE = []byte{0x4D, 0x4D, 0x00, 0x2A}
+
+// Raw is a camera raw file handler.
+type Raw struct {
+	container  *tiff.TIFF
+	tiffIFD0   *tiff.IFD
+	embedded   multi.Provider
+	sidecar    string
+	sidecarRDF *rdf.Packet
+	sidecarXMP *xmp.Provider
+	provider   *provider
+}
+
+// Read reads the provided file, whose XMP sidecar file name is given.  It
+// returns nil, nil, if the file is not a TIFF-based camera raw file.  It
+// returns an error if the file is a raw file but ill-formed, or if a read error
+// occurs, or if its XMP sidecar file exists but can't be read.  It returns a
+// Raw file handler for the file if it is read successfully.
+func Read(r metadata.Reader, sidecar string) (h *Raw, err error) {
+	var buf [12]byte
+
+	if _, err = r.ReadAt(buf[:], 0); err == io.EOF {
+		return nil, nil // can't read a signature, assume it's not raw
+	} else if err != nil {
+		return nil, err
+	} else if !bytes.Equal(buf[0:4], tiffHeaderBE) && !bytes.Equal(buf[0:4], tiffHeaderLE) {
+		return nil, nil // not a TIFF-based file
+	}
+	h = new(Raw)
+	h.container = new(tiff.TIFF)
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err = h.container.Read(r); err != nil {
+		return nil, err
+	}
+	h.tiffIFD0 = h.container.IFD0()
+	if string(buf[8:12]) != cr2Magic && h.tiffIFD0.Tag(tagDNGVersion) == nil && h.tiffIFD0.Tag(tagSubIFDs) == nil {
+		// It's an ordinary TIFF file, not a raw file.
+		if _, err = r.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if err = h.readEmbedded(); err != nil {
+		return nil, err
+	}
+	if err = h.readSidecar(sidecar); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// readEmbedded creates the providers for the metadata embedded in the raw
+// file.  Unlike the TIFF handler, it doesn't create any IFDs that are missing,
+// since the raw file will never be written.
+func (h *Raw) readEmbedded() (err error) {
+	var (
+		tiffIFD0Provider *tiffifd0.Provider
+		exifIFDProvider  *exififd.Provider
+		gpsIFDProvider   *gpsifd.Provider
+		xmpProvider      *xmp.Provider
+		xpProvider       *xp.Provider
+	)
+	if tiffIFD0Provider, err = tiffifd0.New(h.tiffIFD0); err != nil {
+		return err
+	}
+	h.embedded = append(h.embedded, tiffIFD0Provider)
+	if tag := h.tiffIFD0.Tag(tagEXIFIFD); tag != nil {
+		var exifIFD *tiff.IFD
+
+		if exifIFD, err = tag.AsIFD(); err != nil {
+			return fmt.Errorf("EXIF IFD: %s", err)
+		}
+		if exifIFDProvider, err = exififd.New(exifIFD, h.container.Encoding()); err != nil {
+			return err
+		}
+		h.embedded = append(h.embedded, exifIFDProvider)
+	}
+	if tag := h.tiffIFD0.Tag(tagGPSIFD); tag != nil {
+		var gpsIFD *tiff.IFD
+
+		if gpsIFD, err = tag.AsIFD(); err != nil {
+			return fmt.Errorf("GPS IFD: %s", err)
+		}
+		if gpsIFDProvider, err = gpsifd.New(gpsIFD); err != nil {
+			return err
+		}
+		h.embedded = append(h.embedded, gpsIFDProvider)
+	}
+	if tag := h.tiffIFD0.Tag(tagXMP); tag != nil {
+		var (
+			r      metadata.Reader
+			xmpRDF = rdf.New()
+		)
+		if r, err = tag.AsUnknownReader(); err != nil {
+			if r, err = tag.AsBytesReader(); err != nil {
+				return fmt.Errorf("XMP: %s", err)
+			}
+		}
+		if err = xmpRDF.Read(r); err != nil {
+			return fmt.Errorf("XMP: %s", err)
+		}
+		if xmpProvider, err = xmp.New(xmpRDF); err != nil {
+			return err
+		}
+		h.embedded = append(h.embedded, xmpProvider)
+	}
+	// The Windows XP* tags have the same semantics as in JPEG files, and
+	// the lowest priority.
+	if xpProvider, err = xp.New(h.tiffIFD0, h.embedded); err != nil {
+		return err
+	}
+	h.embedded = append(h.embedded, xpProvider)
+	return nil
+}
+
+// readSidecar reads the XMP sidecar file for the raw file, if it exists, and
+// creates the provider for it.  The sidecar provider comes first, so that its
+// values take precedence over those embedded in the raw file.  An existing
+// sidecar file is authoritative for all of the fields it can hold, as it is
+// for Lightroom and darktable; the embedded values of those fields are
+// ignored.  Otherwise, they are shown until the first change, when they are
+// copied into the new sidecar file.
+func (h *Raw) readSidecar(sidecar string) (err error) {
+	var (
+		by     []byte
+		exists bool
+	)
+	h.sidecar = sidecar
+	h.sidecarRDF = rdf.New()
+	if by, err = os.ReadFile(h.sidecar); err == nil {
+		if err = h.sidecarRDF.Read(bytes.NewReader(by)); err != nil {
+			return fmt.Errorf("%s: %s", h.sidecar, err)
+		}
+		exists = true
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if h.sidecarXMP, err = xmp.New(h.sidecarRDF); err != nil {
+		return fmt.Errorf("%s: %s", h.sidecar, err)
+	}
+	h.provider = &provider{h: h}
+	if exists {
+		h.provider.Provider = multi.Provider{h.sidecarXMP, fixedFields{p: h.embedded}}
+		h.provider.seeded = true
+	} else {
+		h.provider.Provider = multi.Provider{h.sidecarXMP, readonly.New(h.embedded)}
+	}
+	return nil
+}
+
+// Provider returns the metadata.Provider for the raw file.
+func (h *Raw) Provider() metadata.Provider { return h.provider }
+
+// Sidecar returns the name of the XMP sidecar file to which changes to the
+// raw file's metadata are saved.
+func (h *Raw) Sidecar() string { return h.sidecar }
+
+// Dirty returns whether the metadata from the file have been changed since they
+// were read (and therefore need to be saved).  Only the sidecar can change.
+func (h *Raw) Dirty() bool { return h.sidecarRDF.Dirty() }
+
+// Save writes the XMP sidecar file, including all revised metadata, to the
+// supplied writer.  The raw file itself is never rewritten.
+func (h *Raw) Save(out io.Writer) (err error) {
+	_, err = h.sidecarRDF.Write(out)
+	return err
+}
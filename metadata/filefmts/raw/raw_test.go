@@ -0,0 +1,257 @@
+package raw
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
+)
+
+const tagImageDescription uint16 = 0x010E
+
+// A testTag is a tag to be placed in IFD0 of a test file.  If subIFD is true,
+// the tag is a LONG pointing to an empty IFD.
+type testTag struct {
+	id     uint16
+	ttype  uint16
+	count  uint32
+	data   []byte
+	subIFD bool
+}
+
+// makeTIFF returns a little-endian TIFF file with the specified tags in IFD0.
+// If cr2 is true, the header carries the CR2 magic number.
+func makeTIFF(cr2 bool, tags ...testTag) []byte {
+	var (
+		buf     bytes.Buffer
+		le      = binary.LittleEndian
+		ifd0    = uint32(8)
+		dataOff uint32
+		data    bytes.Buffer
+	)
+	if cr2 {
+		ifd0 = 16
+	}
+	dataOff = ifd0 + 2 + 12*uint32(len(tags)) + 4
+	subOff := dataOff
+	for _, tag := range tags {
+		if len(tag.data) > 4 {
+			subOff += uint32(len(tag.data) + len(tag.data)%2)
+		}
+	}
+	buf.WriteString("II*\x00")
+	binary.Write(&buf, le, ifd0)
+	if cr2 {
+		buf.WriteString(cr2Magic)
+		buf.Write([]byte{0, 0, 0, 0})
+	}
+	binary.Write(&buf, le, uint16(len(tags)))
+	for _, tag := range tags {
+		binary.Write(&buf, le, tag.id)
+		binary.Write(&buf, le, tag.ttype)
+		binary.Write(&buf, le, tag.count)
+		switch {
+		case tag.subIFD:
+			binary.Write(&buf, le, subOff)
+		case len(tag.data) > 4:
+			binary.Write(&buf, le, dataOff+uint32(data.Len()))
+			data.Write(tag.data)
+			if len(tag.data)%2 != 0 {
+				data.WriteByte(0)
+			}
+		default:
+			var value [4]byte
+			copy(value[:], tag.data)
+			buf.Write(value[:])
+		}
+	}
+	buf.Write([]byte{0, 0, 0, 0}) // no next IFD
+	buf.Write(data.Bytes())
+	buf.Write([]byte{0, 0, 0, 0, 0, 0}) // empty IFD for SubIFDs
+	return buf.Bytes()
+}
+
+// description returns an ImageDescription tag with the specified value.
+func description(s string) testTag {
+	return testTag{id: tagImageDescription, ttype: 2, count: uint32(len(s) + 1), data: append([]byte(s), 0)}
+}
+
+// dngVersion returns a DNGVersion tag.
+func dngVersion() testTag {
+	return testTag{id: tagDNGVersion, ttype: 1, count: 4, data: []byte{1, 4, 0, 0}}
+}
+
+// writeSidecar writes an XMP sidecar file with the specified caption.
+func writeSidecar(t *testing.T, name, caption string) {
+	packet := rdf.New()
+	p, err := xmp.New(packet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.SetCaption(caption); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err = packet.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// save saves the handler to its sidecar file.
+func save(t *testing.T, h *Raw) {
+	var buf bytes.Buffer
+	if err := h.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(h.Sidecar(), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetection(t *testing.T) {
+	var tests = []struct {
+		name string
+		file []byte
+		raw  bool
+	}{
+		{"CR2", makeTIFF(true, description("x")), true},
+		{"DNG", makeTIFF(false, description("x"), dngVersion()), true},
+		{"SubIFDs", makeTIFF(false, description("x"), testTag{id: tagSubIFDs, ttype: 4, count: 1, subIFD: true}), true},
+		{"plain TIFF", makeTIFF(false, description("x")), false},
+		{"not TIFF", []byte("not a TIFF file at all"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := Read(bytes.NewReader(tt.file), filepath.Join(t.TempDir(), "x.xmp"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (h != nil) != tt.raw {
+				t.Errorf("got raw %v, want %v", h != nil, tt.raw)
+			}
+		})
+	}
+}
+
+func TestSaveWritesOnlySidecar(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		rawName = filepath.Join(dir, "IMG_0001.CR2")
+		sidecar = rawName + ".xmp"
+		orig    = makeTIFF(true, description("embedded"))
+	)
+	if err := os.WriteFile(rawName, orig, 0644); err != nil {
+		t.Fatal(err)
+	}
+	fh, err := os.Open(rawName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fh.Close()
+	info, _ := fh.Stat()
+	h, err := Read(fileReader{fh, info.Size()}, sidecar)
+	if err != nil || h == nil {
+		t.Fatalf("Read: %v, %v", h, err)
+	}
+	if err = h.Provider().SetCaption("changed"); err != nil {
+		t.Fatal(err)
+	}
+	if !h.Dirty() {
+		t.Fatal("not dirty after change")
+	}
+	save(t, h)
+	if by, _ := os.ReadFile(rawName); !bytes.Equal(by, orig) {
+		t.Error("raw file changed")
+	}
+	h2, err := Read(bytes.NewReader(orig), sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := h2.Provider().Caption(); got != "changed" {
+		t.Errorf("caption after reread: got %q", got)
+	}
+}
+
+func TestSidecarPrecedence(t *testing.T) {
+	var (
+		sidecar = filepath.Join(t.TempDir(), "IMG_0001.dng.xmp")
+		file    = makeTIFF(false, description("embedded"), dngVersion())
+	)
+	h, err := Read(bytes.NewReader(file), sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Provider().Caption(); got != "embedded" {
+		t.Errorf("without sidecar: got %q", got)
+	}
+	writeSidecar(t, sidecar, "sidecar")
+	if h, err = Read(bytes.NewReader(file), sidecar); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Provider().Caption(); got != "sidecar" {
+		t.Errorf("with sidecar: got %q", got)
+	}
+}
+
+func TestClearStaysCleared(t *testing.T) {
+	var (
+		sidecar = filepath.Join(t.TempDir(), "IMG_0001.dng.xmp")
+		file    = makeTIFF(false, description("embedded"), dngVersion())
+	)
+	h, err := Read(bytes.NewReader(file), sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Provider().SetCaption(""); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Provider().Caption(); got != "" {
+		t.Errorf("after clear: got %q", got)
+	}
+	save(t, h)
+	if h, err = Read(bytes.NewReader(file), sidecar); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Provider().Caption(); got != "" {
+		t.Errorf("after reread: got %q", got)
+	}
+}
+
+func TestFirstChangeSeedsSidecar(t *testing.T) {
+	var (
+		sidecar = filepath.Join(t.TempDir(), "IMG_0001.dng.xmp")
+		file    = makeTIFF(false, description("embedded"), dngVersion())
+	)
+	h, err := Read(bytes.NewReader(file), sidecar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Provider().SetRating(3); err != nil {
+		t.Fatal(err)
+	}
+	save(t, h)
+	if h, err = Read(bytes.NewReader(file), sidecar); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.sidecarXMP.Caption(); got != "embedded" {
+		t.Errorf("sidecar caption: got %q", got)
+	}
+	if got := h.Provider().Rating(); got != 3 {
+		t.Errorf("rating: got %d", got)
+	}
+}
+
+// fileReader adapts an *os.File to the metadata.Reader interface.
+type fileReader struct {
+	*os.File
+	size int64
+}
+
+func (r fileReader) Size() int64 { return r.size }
@@ -19,6 +19,7 @@ import (
 	"github.com/rothskeller/photo-tools/metadata/providers/multi"
 	"github.com/rothskeller/photo-tools/metadata/providers/tiffifd0"
 	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
+	"github.com/rothskeller/photo-tools/metadata/providers/xp"
 )
 
 const (
@@ -37,6 +38,7 @@ type TIFF struct {
 	tiffIFD0  *tiff.IFD
 	psirBlock *photoshop.Photoshop
 	psirIIM   *iim.IIM
+	iptc      []*iptc.Provider
 	providers multi.Provider
 }
 
@@ -48,7 +50,10 @@ var tiffHeaderBE = []byte{0x4D, 0x4D, 0x00, 0x2A}
 // read error occurs.  It returns a TIFF file handler for the file if it is read
 // successfully.
 func Read(r metadata.Reader) (h *TIFF, err error) {
-	var buf [4]byte
+	var (
+		buf        [4]byte
+		xpProvider *xp.Provider
+	)
 
 	if _, err = r.ReadAt(buf[0:4], 0); err == io.EOF {
 		return nil, nil // can't read a signature, assume it's not TIFF
@@ -80,6 +85,12 @@ func Read(r metadata.Reader) (h *TIFF, err error) {
 	if err = h.readPSIRIFD(); err != nil {
 		return nil, err
 	}
+	// The Windows XP* tags come last, so that they have the lowest
+	// priority.
+	if xpProvider, err = xp.New(h.tiffIFD0, h.providers); err != nil {
+		return nil, err
+	}
+	h.providers = append(h.providers, xpProvider)
 	return h, nil
 }
 
@@ -152,6 +163,7 @@ func (h *TIFF) readIPTCTag() (err error) {
 	if iptcProvider, err = iptc.New(iimc); err != nil {
 		return err
 	}
+	h.iptc = append(h.iptc, iptcProvider)
 	h.providers = append(h.providers, iptcProvider)
 	return nil
 }
@@ -190,6 +202,7 @@ func (h *TIFF) readPSIRIFD() (err error) {
 	if psirIPTCProvider, err = iptc.New(h.psirIIM); err != nil {
 		return fmt.Errorf("PSIR: %s", err)
 	}
+	h.iptc = append(h.iptc, psirIPTCProvider)
 	h.providers = append(h.providers, psirIPTCProvider)
 
 	return nil
@@ -240,8 +253,12 @@ func (h *TIFF) readGPSIFD() (err error) {
 func (h *TIFF) Dirty() bool { return h.container.Dirty() }
 
 // Save writes the entire file to the supplied writer, including all revised
-// metadata.
+// metadata.  IPTC metadata that were read in a legacy character set are
+// rewritten in UTF-8.
 func (h *TIFF) Save(out io.Writer) (err error) {
+	for _, p := range h.iptc {
+		p.Transcode()
+	}
 	if !h.psirIIM.Empty() {
 		var (
 			hashRaw  *raw.Raw
@@ -0,0 +1,153 @@
+// Package webp contains the file format handler for WebP files.
+package webp
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+	"github.com/rothskeller/photo-tools/metadata/containers/riff"
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+	"github.com/rothskeller/photo-tools/metadata/providers/exififd"
+	"github.com/rothskeller/photo-tools/metadata/providers/gpsifd"
+	"github.com/rothskeller/photo-tools/metadata/providers/jpegifd0"
+	"github.com/rothskeller/photo-tools/metadata/providers/multi"
+	"github.com/rothskeller/photo-tools/metadata/providers/xmp"
+	"github.com/rothskeller/photo-tools/metadata/providers/xp"
+)
+
+const (
+	tagEXIFIFD uint16 = 0x8769
+	tagGPSIFD  uint16 = 0x8825
+)
+
+// WebP is a WebP file handler.
+type WebP struct {
+	container *riff.RIFF
+	exifTIFF  *tiff.TIFF
+	xmpRDF    *rdf.Packet
+	providers multi.Provider
+}
+
+// Read reads the provided file.  It returns nil, nil, if the file is not a
+// WebP file.  It returns an error if the file is a WebP file but ill-formed, or
+// if a read error occurs.  It returns a WebP file handler for the file if it is
+// read successfully.
+func Read(r metadata.Reader) (wh *WebP, err error) {
+	var (
+		buf        [12]byte
+		xpProvider *xp.Provider
+	)
+
+	if _, err = r.ReadAt(buf[:], 0); err == io.EOF {
+		return nil, nil // can't read a header, assume it's not WebP
+	} else if err != nil {
+		return nil, err
+	} else if string(buf[0:4]) != "RIFF" || string(buf[8:12]) != "WEBP" {
+		return nil, nil // not a WebP file
+	}
+	wh = new(WebP)
+	wh.container = new(riff.RIFF)
+	if err = wh.container.Read(r); err != nil {
+		return nil, err
+	}
+	if err = wh.readEXIFChunk(); err != nil {
+		return nil, err
+	}
+	if err = wh.readXMPChunk(); err != nil {
+		return nil, err
+	}
+	// The Windows XP* tags come last, so that they have the lowest
+	// priority.
+	if xpProvider, err = xp.New(wh.exifTIFF.IFD0(), wh.providers); err != nil {
+		return nil, err
+	}
+	wh.providers = append(wh.providers, xpProvider)
+	return wh, nil
+}
+
+// Provider returns the metadata.Provider for the WebP file.
+func (wh *WebP) Provider() metadata.Provider { return wh.providers }
+
+func (wh *WebP) readEXIFChunk() (err error) {
+	var (
+		exifChunk        metadata.Reader
+		ifd0             *tiff.IFD
+		exifIFD          *tiff.IFD
+		gpsIFD           *tiff.IFD
+		jpegIFD0Provider *jpegifd0.Provider
+		exifIFDProvider  *exififd.Provider
+		gpsIFDProvider   *gpsifd.Provider
+	)
+	wh.exifTIFF = new(tiff.TIFF)
+	if exifChunk = wh.container.EXIF(); exifChunk != nil {
+		if err = wh.exifTIFF.Read(exifChunk); err != nil {
+			return fmt.Errorf("EXIF chunk: %s", err)
+		}
+	}
+	wh.container.SetEXIFContainer(wh.exifTIFF)
+	// The IFD0 of a WebP EXIF chunk has the same semantics as that of a JPEG
+	// EXIF segment, so we use the same provider for it.
+	ifd0 = wh.exifTIFF.IFD0()
+	if jpegIFD0Provider, err = jpegifd0.New(ifd0); err != nil {
+		return err
+	}
+	wh.providers = append(wh.providers, jpegIFD0Provider)
+	if tag := ifd0.Tag(tagEXIFIFD); tag != nil {
+		if exifIFD, err = tag.AsIFD(); err != nil {
+			return fmt.Errorf("EXIF IFD: %s", err)
+		}
+	} else {
+		tag = ifd0.AddTag(tagEXIFIFD, 4)
+		exifIFD, _ = tag.AddIFD()
+	}
+	if exifIFDProvider, err = exififd.New(exifIFD, wh.exifTIFF.Encoding()); err != nil {
+		return err
+	}
+	wh.providers = append(wh.providers, exifIFDProvider)
+	if tag := ifd0.Tag(tagGPSIFD); tag != nil {
+		if gpsIFD, err = tag.AsIFD(); err != nil {
+			return fmt.Errorf("GPS IFD: %s", err)
+		}
+	} else {
+		tag = ifd0.AddTag(tagGPSIFD, 4)
+		gpsIFD, _ = tag.AddIFD()
+	}
+	if gpsIFDProvider, err = gpsifd.New(gpsIFD); err != nil {
+		return err
+	}
+	wh.providers = append(wh.providers, gpsIFDProvider)
+	return nil
+}
+
+func (wh *WebP) readXMPChunk() (err error) {
+	var (
+		xmpChunk    metadata.Reader
+		xmpProvider *xmp.Provider
+	)
+	wh.xmpRDF = rdf.New()
+	if xmpChunk = wh.container.XMP(); xmpChunk != nil {
+		if err = wh.xmpRDF.Read(xmpChunk); err != nil {
+			return fmt.Errorf("XMP: %s", err)
+		}
+	}
+	wh.container.SetXMPContainer(wh.xmpRDF)
+	if xmpProvider, err = xmp.New(wh.xmpRDF); err != nil {
+		return err
+	}
+	wh.providers = append(wh.providers, xmpProvider)
+	return nil
+}
+
+// Dirty returns whether the metadata from the file have been changed since they
+// were read (and therefore need to be saved).
+func (wh *WebP) Dirty() bool { return wh.container.Dirty() }
+
+// Save writes the entire file to the supplied writer, including all revised
+// metadata.
+func (wh *WebP) Save(out io.Writer) (err error) {
+	wh.container.Layout()
+	_, err = wh.container.Write(out)
+	return err
+}
@@ -56,6 +56,17 @@ func Read(r metadata.Reader) (h *XMP, err error) {
 	return h, nil
 }
 
+// New returns a handler for a new, empty XMP file, such as a sidecar file
+// that doesn't exist yet.
+func New() (h *XMP, err error) {
+	h = new(XMP)
+	h.rdf = rdf.New()
+	if h.provider, err = xmp.New(h.rdf); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
+	return h, nil
+}
+
 // Provider returns the metadata.Provider for the XMP file.
 func (h *XMP) Provider() metadata.Provider { return h.provider }
 
@@ -59,7 +59,12 @@ ERROR:
 // FixedFloatFromFloat returns the FixedFloat that most precisely represents the
 // supplied floating point number.
 func FixedFloatFromFloat(v float64) (f FixedFloat) {
-	f = FixedFloat(v*10000000.0 + 5.0)
+	// Round away from zero; the division truncates toward zero.
+	if v < 0 {
+		f = FixedFloat(v*10000000.0 - 5.0)
+	} else {
+		f = FixedFloat(v*10000000.0 + 5.0)
+	}
 	f /= 10
 	return f
 }
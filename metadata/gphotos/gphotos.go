@@ -0,0 +1,201 @@
+// Package gphotos reads the JSON sidecar files that accompany media downloaded
+// from Google Photos via Google Takeout, and finds the sidecar for a media
+// file.
+package gphotos
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// A Sidecar contains the metadata from a Google Photos JSON sidecar file.
+type Sidecar struct {
+	// Title is the title of the photo.  Google Photos sets it to the
+	// original file name unless it has been changed.
+	Title string
+	// Description is the photo description (i.e., caption).
+	Description string
+	// TakenTime is the time the photo was taken, or zero if unknown.
+	TakenTime time.Time
+	// GPS is the location of the photo, if known.
+	GPS metadata.GPSCoords
+	// People is the list of names of the people in the photo.
+	People []string
+}
+
+// sidecarJSON is the structure of a Google Photos JSON sidecar file.
+type sidecarJSON struct {
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	PhotoTakenTime struct {
+		Timestamp string `json:"timestamp"`
+	} `json:"photoTakenTime"`
+	GeoData     geoDataJSON `json:"geoData"`
+	GeoDataExif geoDataJSON `json:"geoDataExif"`
+	People      []struct {
+		Name string `json:"name"`
+	} `json:"people"`
+}
+
+// geoDataJSON is the structure of a location in a Google Photos JSON sidecar
+// file.  Google Photos uses 0, 0 for an unknown location.
+type geoDataJSON struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitude"`
+}
+
+// ErrNotSidecar is the error returned when a file is not a Google Photos JSON
+// sidecar.
+var ErrNotSidecar = errors.New("not a Google Photos JSON sidecar")
+
+// ReadFile reads the named Google Photos JSON sidecar file.
+func ReadFile(name string) (s *Sidecar, err error) {
+	var fh *os.File
+
+	if fh, err = os.Open(name); err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return Read(fh)
+}
+
+// Read reads a Google Photos JSON sidecar file.
+func Read(r io.Reader) (s *Sidecar, err error) {
+	var sj sidecarJSON
+
+	if err = json.NewDecoder(r).Decode(&sj); err != nil {
+		return nil, ErrNotSidecar
+	}
+	if sj.Title == "" && sj.PhotoTakenTime.Timestamp == "" {
+		return nil, ErrNotSidecar
+	}
+	s = &Sidecar{Title: strings.TrimSpace(sj.Title), Description: strings.TrimSpace(sj.Description)}
+	if sj.PhotoTakenTime.Timestamp != "" {
+		secs, err := strconv.ParseInt(sj.PhotoTakenTime.Timestamp, 10, 64)
+		if err != nil {
+			return nil, ErrNotSidecar
+		}
+		if secs != 0 {
+			s.TakenTime = time.Unix(secs, 0).UTC()
+		}
+	}
+	for _, geo := range []geoDataJSON{sj.GeoData, sj.GeoDataExif} {
+		if geo.Latitude != 0 && geo.Longitude != 0 {
+			s.GPS.SetLatitude(geo.Latitude)
+			s.GPS.SetLongitude(geo.Longitude)
+			if geo.Altitude != 0 {
+				s.GPS.SetAltitude(geo.Altitude)
+			}
+			break
+		}
+	}
+	for _, person := range sj.People {
+		if name := strings.TrimSpace(person.Name); name != "" {
+			s.People = append(s.People, name)
+		}
+	}
+	return s, nil
+}
+
+// DateTime returns the time the photo was taken, as a metadata.DateTime in
+// UTC.  It is empty if the time is unknown.
+func (s *Sidecar) DateTime() (dt metadata.DateTime) {
+	if !s.TakenTime.IsZero() {
+		dt.Parse(s.TakenTime.Format("2006-01-02T15:04:05Z"))
+	}
+	return dt
+}
+
+// Find returns the path of the Google Photos JSON sidecar file for the named
+// media file, or an empty string if there isn't one.
+func Find(media string) (sidecar string, err error) {
+	var (
+		dir   = filepath.Dir(media)
+		names []string
+	)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	if name := Match(filepath.Base(media), names); name != "" {
+		return filepath.Join(dir, name), nil
+	}
+	return "", nil
+}
+
+// The sidecar for a media file is usually named by adding ".json" (or, in
+// newer exports, ".supplemental-metadata.json") to the media file name.  But
+// there are several quirks:
+//   - The sidecar name, less ".json", is truncated to 46 characters, so the
+//     suffix can be partially or entirely cut off, as can the end of the
+//     media file name.
+//   - When an album has several files of the same name, the second and later
+//     are named "IMG_1234(1).jpg", etc.  Their sidecars are named
+//     "IMG_1234.jpg(1).json", etc.
+//   - Edited copies of files, named "IMG_1234-edited.jpg", share the sidecar
+//     of the original.
+//   - Occasionally the media file extension is omitted from the sidecar name.
+const (
+	supplementalSuffix = ".supplemental-metadata"
+	editedSuffix       = "-edited"
+	truncatedLength    = 46
+)
+
+// dupSuffixRE matches the suffix added to the names of duplicate files.
+var dupSuffixRE = regexp.MustCompile(`^(.*)(\(\d+\))$`)
+
+// Match returns the name of the Google Photos JSON sidecar for the media file
+// with the specified base name, from among the specified sidecar base names.
+// It returns an empty string if none of them match.  If several match, the
+// longest (i.e., least truncated) is returned.
+func Match(media string, sidecars []string) (sidecar string) {
+	ext := filepath.Ext(media)
+	stem := strings.TrimSuffix(strings.TrimSuffix(media, ext), editedSuffix)
+	name, dup := stem+ext, ""
+	if m := dupSuffixRE.FindStringSubmatch(stem); m != nil {
+		name, dup = m[1]+ext, m[2]
+	}
+	for _, sc := range sidecars {
+		s := sc[:len(sc)-len(filepath.Ext(sc))]
+		if len(sc) <= len(sidecar) {
+			continue
+		}
+		if matchSidecar(s, stem+ext, "") || (dup != "" && matchSidecar(s, name, dup)) {
+			sidecar = sc
+		}
+	}
+	return sidecar
+}
+
+// matchSidecar returns whether the sidecar name (less ".json") is for the
+// media file name, with the specified duplicate suffix.
+func matchSidecar(s, name, dup string) bool {
+	if dup != "" {
+		if !strings.HasSuffix(s, dup) {
+			return false
+		}
+		s = s[:len(s)-len(dup)]
+	}
+	switch {
+	case s == name, s == name+supplementalSuffix, s == strings.TrimSuffix(name, filepath.Ext(name)):
+		return true
+	case len(s) >= truncatedLength && strings.HasPrefix(name+supplementalSuffix, s):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package gphotos
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSidecar = `{
+  "title": "IMG_1234.jpg",
+  "description": "  Lunch at the pier  ",
+  "imageViews": "3",
+  "creationTime": { "timestamp": "1654100000", "formatted": "Jun 1, 2022, 4:13:20 PM UTC" },
+  "photoTakenTime": { "timestamp": "1654077600", "formatted": "Jun 1, 2022, 10:00:00 AM UTC" },
+  "geoData": { "latitude": 0.0, "longitude": 0.0, "altitude": 0.0, "latitudeSpan": 0.0, "longitudeSpan": 0.0 },
+  "geoDataExif": { "latitude": 37.4, "longitude": -122.1, "altitude": 12.5, "latitudeSpan": 0.0, "longitudeSpan": 0.0 },
+  "people": [ { "name": "Alice Smith" }, { "name": " " }, { "name": "Bob Jones" } ],
+  "url": "https://photos.google.com/photo/xyz"
+}`
+
+func TestRead(t *testing.T) {
+	s, err := Read(strings.NewReader(testSidecar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Title != "IMG_1234.jpg" || s.Description != "Lunch at the pier" {
+		t.Errorf("title/description: got %q, %q", s.Title, s.Description)
+	}
+	if !s.TakenTime.Equal(time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("taken time: got %s", s.TakenTime)
+	}
+	if dt := s.DateTime().String(); dt != "2022-06-01T10:00:00Z" {
+		t.Errorf("DateTime: got %s", dt)
+	}
+	if s.GPS.Latitude() != 37.4 || s.GPS.Longitude() != -122.1 || s.GPS.Altitude() != 12.5 {
+		t.Errorf("GPS: got %s", s.GPS)
+	}
+	if len(s.People) != 2 || s.People[0] != "Alice Smith" || s.People[1] != "Bob Jones" {
+		t.Errorf("people: got %q", s.People)
+	}
+	if _, err := Read(strings.NewReader(`{"locations": []}`)); err != ErrNotSidecar {
+		t.Errorf("not a sidecar: got %v", err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	sidecars := []string{
+		"IMG_1234.jpg.json",
+		"IMG_1234.jpg(1).json",
+		"IMG_5678.HEIC.supplemental-metadata.json",
+		"PXL_20220601_100000123.MP.jpg.supplemental-met.json",
+		"Screenshot_20220601-100000_Some Very Long App .json",
+		"Screenshot_20220601-100000_Some Very Long App (2).json",
+		"IMG_9.json",
+		"metadata.json",
+	}
+	tests := []struct{ media, sidecar string }{
+		{"IMG_1234.jpg", "IMG_1234.jpg.json"},
+		{"IMG_1234-edited.jpg", "IMG_1234.jpg.json"},
+		{"IMG_1234(1).jpg", "IMG_1234.jpg(1).json"},
+		{"IMG_1234(2).jpg", ""},
+		{"IMG_5678.HEIC", "IMG_5678.HEIC.supplemental-metadata.json"},
+		{"PXL_20220601_100000123.MP.jpg", "PXL_20220601_100000123.MP.jpg.supplemental-met.json"},
+		{"Screenshot_20220601-100000_Some Very Long App Name.png", "Screenshot_20220601-100000_Some Very Long App .json"},
+		{"Screenshot_20220601-100000_Some Very Long App Name(2).png", "Screenshot_20220601-100000_Some Very Long App (2).json"},
+		{"IMG_9.mp4", "IMG_9.json"},
+		{"IMG_98.jpg", ""},
+		{"IMG_123.jpg", ""},
+	}
+	for _, test := range tests {
+		if got := Match(test.media, sidecars); got != test.sidecar {
+			t.Errorf("%s: got %q, expected %q", test.media, got, test.sidecar)
+		}
+	}
+}
@@ -393,6 +393,111 @@ func toXMPAltitude(f FixedFloat) (ref, alt string) {
 	return
 }
 
+// ParseISO6709 parses a set of GPS coordinates as represented in ISO 6709
+// form (e.g., "+37.3354-122.0199+060.960/"), as used in QuickTime metadata.  It
+// returns ErrParseGPSCoords if the input is invalid.
+func (gc *GPSCoords) ParseISO6709(s string) (err error) {
+	var parts []string
+
+	*gc = GPSCoords{}
+	if idx := strings.Index(s, "CRS"); idx >= 0 {
+		s = s[:idx]
+	}
+	s = strings.TrimSuffix(strings.TrimSpace(s), "/")
+	if s == "" {
+		return nil
+	}
+	for len(s) != 0 {
+		if s[0] != '+' && s[0] != '-' {
+			return ErrParseGPSCoords
+		}
+		idx := strings.IndexAny(s[1:], "+-") + 1
+		if idx == 0 {
+			idx = len(s)
+		}
+		parts = append(parts, s[:idx])
+		s = s[idx:]
+	}
+	if len(parts) < 2 || len(parts) > 3 {
+		return ErrParseGPSCoords
+	}
+	if gc.latitude, err = fromISO6709Angle(parts[0], 2); err != nil {
+		return err
+	}
+	if gc.longitude, err = fromISO6709Angle(parts[1], 3); err != nil {
+		return err
+	}
+	if len(parts) == 3 {
+		if gc.altitude, err = ParseFixedFloat(strings.TrimPrefix(parts[2], "+")); err != nil {
+			return ErrParseGPSCoords
+		}
+	}
+	return nil
+}
+
+// fromISO6709Angle parses one angle of an ISO 6709 representation, which has
+// the specified number of degree digits (2 for latitude, 3 for longitude).
+func fromISO6709Angle(s string, ddigits int) (f FixedFloat, err error) {
+	var (
+		neg   = s[0] == '-'
+		whole string
+		frac  string
+		d     FixedFloat
+		m     FixedFloat
+	)
+	s = s[1:]
+	if idx := strings.IndexByte(s, '.'); idx >= 0 {
+		whole, frac = s[:idx], s[idx:]
+	} else {
+		whole = s
+	}
+	// The number of digits in the whole part tells whether the angle is
+	// expressed in degrees, degrees and minutes, or degrees, minutes, and
+	// seconds.  The fractional part applies to the last unit given.
+	switch len(whole) {
+	case ddigits:
+		f, err = ParseFixedFloat(whole + frac)
+	case ddigits + 2:
+		if d, err = ParseFixedFloat(whole[:ddigits]); err == nil {
+			m, err = ParseFixedFloat(whole[ddigits:] + frac)
+			f = d + m/60
+		}
+	case ddigits + 4:
+		var sec FixedFloat
+		if d, err = ParseFixedFloat(whole[:ddigits]); err == nil {
+			if m, err = ParseFixedFloat(whole[ddigits : ddigits+2]); err == nil {
+				sec, err = ParseFixedFloat(whole[ddigits+2:] + frac)
+				f = d + m/60 + sec/3600
+			}
+		}
+	default:
+		return 0, ErrParseGPSCoords
+	}
+	if err != nil {
+		return 0, ErrParseGPSCoords
+	}
+	if neg {
+		f = -f
+	}
+	return f, nil
+}
+
+// AsISO6709 renders a set of GPS coordinates in ISO 6709 form, as used in
+// QuickTime metadata.
+func (gc GPSCoords) AsISO6709() string {
+	var sb strings.Builder
+
+	if gc.Empty() {
+		return ""
+	}
+	fmt.Fprintf(&sb, "%+010.6f%+011.6f", gc.latitude.AsFloat64(), gc.longitude.AsFloat64())
+	if gc.HasAltitude() {
+		fmt.Fprintf(&sb, "%+.3f", gc.altitude.AsFloat64())
+	}
+	sb.WriteByte('/')
+	return sb.String()
+}
+
 // Empty returns true if the value contains no data.
 func (gc GPSCoords) Empty() bool {
 	return gc.latitude == 0 || gc.longitude == 0
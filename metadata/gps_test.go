@@ -26,3 +26,31 @@ func TestGPS(t *testing.T) {
 		t.Errorf("result is wrong: %s", gc2.String())
 	}
 }
+
+func TestGPSISO6709(t *testing.T) {
+	var gc, gc2 GPSCoords
+	if err := gc.ParseISO6709("+37.3354-122.0199+060.960/"); err != nil {
+		t.Fatalf("gc.ParseISO6709 failed")
+	}
+	if gc.String() != "37.3354, -122.0199, 200ft" {
+		t.Errorf("result is wrong: %s", gc.String())
+	}
+	if s := gc.AsISO6709(); s != "+37.335400-122.019900+60.960/" {
+		t.Errorf("gc.AsISO6709 is wrong: %s", s)
+	}
+	if err := gc2.ParseISO6709("+3720.124-12201.194/"); err != nil {
+		t.Fatalf("gc2.ParseISO6709 failed")
+	}
+	if gc2.String() != "37.3354, -122.0199" {
+		t.Errorf("result is wrong: %s", gc2.String())
+	}
+}
+
+func TestGPSFromFloat(t *testing.T) {
+	var gc GPSCoords
+	gc.SetLatitude(-33.5)
+	gc.SetLongitude(-122)
+	if gc.String() != "-33.5, -122" {
+		t.Errorf("result is wrong: %s", gc.String())
+	}
+}
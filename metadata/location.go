@@ -93,3 +93,24 @@ func (loc Location) Equal(other Location) bool {
 		loc.City == other.City &&
 		loc.Sublocation == other.Sublocation
 }
+
+// CongruentTo returns true if the location is congruent to the specified place
+// value, i.e., the country name, state, city, and sublocation components of the
+// location (those that are not empty) appear as components of the place value,
+// in the same order but possibly interspersed with other components.  An empty
+// location is congruent to any place.
+func (loc Location) CongruentTo(place HierValue) bool {
+	var parts []string
+	for _, part := range []string{loc.CountryName, loc.State, loc.City, loc.Sublocation} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	for len(parts) != 0 && len(place) != 0 {
+		if parts[0] == place[0] {
+			parts = parts[1:]
+		}
+		place = place[1:]
+	}
+	return len(parts) == 0
+}
@@ -0,0 +1,22 @@
+package metadata
+
+import "testing"
+
+func TestLocationCongruentTo(t *testing.T) {
+	var loc Location
+	if err := loc.Parse("JP / Japan / Yamanashi / Fujikawaguchiko / Lake Kawaguchi"); err != nil {
+		t.Fatalf("loc.Parse failed: %s", err)
+	}
+	if !loc.CongruentTo(HierValue{"Japan", "Yamanashi", "Fujikawaguchiko", "Lake Kawaguchi", "North Shore"}) {
+		t.Errorf("location not congruent to place that contains it")
+	}
+	if !loc.CongruentTo(HierValue{"Japan", "Chubu", "Yamanashi", "Fujikawaguchiko", "Lake Kawaguchi"}) {
+		t.Errorf("location not congruent to place with interspersed component")
+	}
+	if loc.CongruentTo(HierValue{"Japan", "Yamanashi", "Mt. Fuji"}) {
+		t.Errorf("location congruent to different place")
+	}
+	if !(Location{}).CongruentTo(HierValue{"Japan"}) {
+		t.Errorf("empty location not congruent")
+	}
+}
@@ -32,6 +32,13 @@ type Provider interface {
 	// ProviderName is the name for the provider, for debug purposes.
 	ProviderName() string
 
+	// Camera returns the value of the Camera field.  This field is
+	// read-only.
+	Camera() (value string)
+	// CameraTags returns a list of tag names for the Camera field, and a
+	// parallel list of values held by those tags.
+	CameraTags() (tags []string, values []string)
+
 	// Caption returns the value of the Caption field.
 	Caption() (value string)
 	// CaptionTags returns a list of tag names for the Caption field, and a
@@ -39,6 +46,19 @@ type Provider interface {
 	CaptionTags() (tags []string, values [][]string)
 	// SetCaption sets the value of the Caption field.
 	SetCaption(value string) error
+	// CaptionAlt returns the language alternatives of the Caption field.
+	// The first alternative is the default, i.e., the value of Caption.
+	CaptionAlt() (value AltString)
+	// SetCaptionAlt sets the language alternatives of the Caption field.
+	SetCaptionAlt(value AltString) error
+
+	// Copyright returns the value of the Copyright field.
+	Copyright() (value string)
+	// CopyrightTags returns a list of tag names for the Copyright field, and a
+	// parallel list of values held by those tags.
+	CopyrightTags() (tags []string, values [][]string)
+	// SetCopyright sets the value of the Copyright field.
+	SetCopyright(value string) error
 
 	// Creator returns the value of the Creator field.
 	Creator() (value string)
@@ -48,6 +68,14 @@ type Provider interface {
 	// SetCreator sets the value of the Creator field.
 	SetCreator(value string) error
 
+	// Credit returns the value of the Credit field.
+	Credit() (value string)
+	// CreditTags returns a list of tag names for the Credit field, and a
+	// parallel list of values held by those tags.
+	CreditTags() (tags []string, values [][]string)
+	// SetCredit sets the value of the Credit field.
+	SetCredit(value string) error
+
 	// DateTime returns the value of the DateTime field.
 	DateTime() (value DateTime)
 	// DateTimeTags returns a list of tag names for the DateTime field, and
@@ -56,13 +84,42 @@ type Provider interface {
 	// SetDateTime sets the value of the DateTime field.
 	SetDateTime(value DateTime) error
 
-	// Faces returns the values of the Faces field.
-	Faces() (values []string)
+	// Dimensions returns the value of the Dimensions field, i.e., the pixel
+	// dimensions of the image.  This field is read-only.
+	Dimensions() (value Dimensions)
+	// DimensionsTags returns a list of tag names for the Dimensions field,
+	// and a parallel list of values held by those tags.
+	DimensionsTags() (tags []string, values []Dimensions)
+
+	// ExposureTime returns the value of the ExposureTime field, i.e., the
+	// exposure time in seconds.  This field is read-only.
+	ExposureTime() (value float64)
+	// ExposureTimeTags returns a list of tag names for the ExposureTime field,
+	// and a parallel list of values held by those tags.
+	ExposureTimeTags() (tags []string, values []float64)
+
+	// Faces returns the values of the Faces field, i.e., the face regions
+	// in the image.
+	Faces() (values []FaceRegion)
 	// FacesTags returns a list of tag names for the Faces field, and a
 	// parallel list of values held by those tags.
-	FacesTags() (tags []string, values [][]string)
+	FacesTags() (tags []string, values [][]FaceRegion)
 	// SetFaces sets the values of the Faces field.
-	SetFaces(values []string) error
+	SetFaces(values []FaceRegion) error
+
+	// FNumber returns the value of the FNumber field, i.e., the f-number of the
+	// aperture.  This field is read-only.
+	FNumber() (value float64)
+	// FNumberTags returns a list of tag names for the FNumber field, and a
+	// parallel list of values held by those tags.
+	FNumberTags() (tags []string, values []float64)
+
+	// FocalLength returns the value of the FocalLength field, i.e., the focal
+	// length of the lens in millimeters.  This field is read-only.
+	FocalLength() (value float64)
+	// FocalLengthTags returns a list of tag names for the FocalLength field,
+	// and a parallel list of values held by those tags.
+	FocalLengthTags() (tags []string, values []float64)
 
 	// GPS returns the values of the GPS field.
 	GPS() (value GPSCoords)
@@ -72,6 +129,14 @@ type Provider interface {
 	// SetGPS sets the values of the GPS field.
 	SetGPS(value GPSCoords) error
 
+	// GPSDateTime returns the value of the GPSDateTime field, i.e., the UTC
+	// date and time recorded by the GPS receiver.  This field is
+	// read-only.
+	GPSDateTime() (value DateTime)
+	// GPSDateTimeTags returns a list of tag names for the GPSDateTime
+	// field, and a parallel list of values held by those tags.
+	GPSDateTimeTags() (tags []string, values []DateTime)
+
 	// Groups returns the values of the Groups field.
 	Groups() (values []HierValue)
 	// GroupsTags returns a list of tag names for the Groups field, and a
@@ -80,6 +145,13 @@ type Provider interface {
 	// SetGroups sets the values of the Groups field.
 	SetGroups(values []HierValue) error
 
+	// ISO returns the value of the ISO field, i.e., the ISO speed rating.
+	// This field is read-only.
+	ISO() (value int)
+	// ISOTags returns a list of tag names for the ISO field, and a
+	// parallel list of values held by those tags.
+	ISOTags() (tags []string, values []int)
+
 	// Keywords returns the values of the Keywords field.
 	Keywords() (values []HierValue)
 	// KeywordsTags returns a list of tag names for the Keywords field, and
@@ -88,6 +160,29 @@ type Provider interface {
 	// SetKeywords sets the values of the Keywords field.
 	SetKeywords(values []HierValue) error
 
+	// Label returns the value of the Label field, i.e., the color label
+	// assigned to the media.
+	Label() (value string)
+	// LabelTags returns a list of tag names for the Label field, and a
+	// parallel list of values held by those tags.
+	LabelTags() (tags []string, values []string)
+	// SetLabel sets the value of the Label field.
+	SetLabel(value string) error
+
+	// Lens returns the value of the Lens field.  This field is read-only.
+	Lens() (value string)
+	// LensTags returns a list of tag names for the Lens field, and a
+	// parallel list of values held by those tags.
+	LensTags() (tags []string, values []string)
+
+	// License returns the value of the License field.
+	License() (value string)
+	// LicenseTags returns a list of tag names for the License field, and a
+	// parallel list of values held by those tags.
+	LicenseTags() (tags []string, values [][]string)
+	// SetLicense sets the value of the License field.
+	SetLicense(value string) error
+
 	// Location returns the value of the Location field.
 	Location() (value Location)
 	// LocationTags returns a list of tag names for the Location field, and
@@ -96,6 +191,17 @@ type Provider interface {
 	// SetLocation sets the value of the Location field.
 	SetLocation(values Location) error
 
+	// LocationsShown returns the values of the LocationsShown field, i.e.,
+	// the locations depicted in the media (as opposed to the Location
+	// field, which is where the media were captured).
+	LocationsShown() (values []Location)
+	// LocationsShownTags returns a list of tag names for the
+	// LocationsShown field, and a parallel list of values held by those
+	// tags.
+	LocationsShownTags() (tags []string, values [][]Location)
+	// SetLocationsShown sets the values of the LocationsShown field.
+	SetLocationsShown(values []Location) error
+
 	// Orientation returns the value of the Orientation field.
 	Orientation() (value Orientation)
 	// OrientationTags returns a list of tag names for the Orientation
@@ -120,6 +226,30 @@ type Provider interface {
 	// SetPlaces sets the values of the Places field.
 	SetPlaces(values []HierValue) error
 
+	// Rating returns the value of the Rating field:  1 to 5 stars, -1 for
+	// rejected media, or 0 for unrated media.
+	Rating() (value int)
+	// RatingTags returns a list of tag names for the Rating field, and a
+	// parallel list of values held by those tags.
+	RatingTags() (tags []string, values []int)
+	// SetRating sets the value of the Rating field.
+	SetRating(value int) error
+
+	// Serial returns the value of the Serial field, i.e., the serial number
+	// of the camera body.  This field is read-only.
+	Serial() (value string)
+	// SerialTags returns a list of tag names for the Serial field, and a
+	// parallel list of values held by those tags.
+	SerialTags() (tags []string, values []string)
+
+	// Source returns the value of the Source field.
+	Source() (value string)
+	// SourceTags returns a list of tag names for the Source field, and a
+	// parallel list of values held by those tags.
+	SourceTags() (tags []string, values [][]string)
+	// SetSource sets the value of the Source field.
+	SetSource(value string) error
+
 	// Title returns the value of the Title field.
 	Title() (value string)
 	// TitleTags returns a list of tag names for the Title field, and a
@@ -127,6 +257,11 @@ type Provider interface {
 	TitleTags() (tags []string, values [][]string)
 	// SetTitle sets the values of the Title field.
 	SetTitle(value string) error
+	// TitleAlt returns the language alternatives of the Title field.  The
+	// first alternative is the default, i.e., the value of Title.
+	TitleAlt() (value AltString)
+	// SetTitleAlt sets the language alternatives of the Title field.
+	SetTitleAlt(value AltString) error
 
 	// Topics returns the values of the Topics field.
 	Topics() (values []HierValue)
@@ -135,6 +270,14 @@ type Provider interface {
 	TopicsTags() (tags []string, values [][]HierValue)
 	// SetTopics sets the values of the Topics field.
 	SetTopics(values []HierValue) error
+
+	// UsageTerms returns the value of the UsageTerms field.
+	UsageTerms() (value string)
+	// UsageTermsTags returns a list of tag names for the UsageTerms field, and a
+	// parallel list of values held by those tags.
+	UsageTermsTags() (tags []string, values [][]string)
+	// SetUsageTerms sets the value of the UsageTerms field.
+	SetUsageTerms(value string) error
 }
 
 // ErrNotSupported is the error returned from a SetXXX function when the
@@ -147,6 +290,13 @@ var ErrNotSupported = errors.New("unsupported metadata field")
 // the concrete implementation.
 type BaseProvider struct{}
 
+// Camera returns the value of the Camera field.
+func (p BaseProvider) Camera() string { return "" }
+
+// CameraTags returns a list of tag names for the Camera field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) CameraTags() ([]string, []string) { return nil, nil }
+
 // Caption returns the value of the Caption field.
 func (p BaseProvider) Caption() string { return "" }
 
@@ -157,6 +307,22 @@ func (p BaseProvider) CaptionTags() ([]string, [][]string) { return nil, nil }
 // SetCaption sets the value of the Caption field.
 func (p BaseProvider) SetCaption(value string) error { return ErrNotSupported }
 
+// CaptionAlt returns the language alternatives of the Caption field.
+func (p BaseProvider) CaptionAlt() AltString { return nil }
+
+// SetCaptionAlt sets the language alternatives of the Caption field.
+func (p BaseProvider) SetCaptionAlt(value AltString) error { return ErrNotSupported }
+
+// Copyright returns the value of the Copyright field.
+func (p BaseProvider) Copyright() string { return "" }
+
+// CopyrightTags returns a list of tag names for the Copyright field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) CopyrightTags() ([]string, [][]string) { return nil, nil }
+
+// SetCopyright sets the value of the Copyright field.
+func (p BaseProvider) SetCopyright(value string) error { return ErrNotSupported }
+
 // Creator returns the value of the Creator field.
 func (p BaseProvider) Creator() string { return "" }
 
@@ -167,6 +333,16 @@ func (p BaseProvider) CreatorTags() ([]string, [][]string) { return nil, nil }
 // SetCreator sets the value of the Creator field.
 func (p BaseProvider) SetCreator(value string) error { return ErrNotSupported }
 
+// Credit returns the value of the Credit field.
+func (p BaseProvider) Credit() string { return "" }
+
+// CreditTags returns a list of tag names for the Credit field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) CreditTags() ([]string, [][]string) { return nil, nil }
+
+// SetCredit sets the value of the Credit field.
+func (p BaseProvider) SetCredit(value string) error { return ErrNotSupported }
+
 // DateTime returns the value of the DateTime field.
 func (p BaseProvider) DateTime() DateTime { return DateTime{} }
 
@@ -177,15 +353,43 @@ func (p BaseProvider) DateTimeTags() ([]string, []DateTime) { return nil, nil }
 // SetDateTime sets the value of the DateTime field.
 func (p BaseProvider) SetDateTime(value DateTime) error { return ErrNotSupported }
 
+// Dimensions returns the value of the Dimensions field.
+func (p BaseProvider) Dimensions() Dimensions { return Dimensions{} }
+
+// DimensionsTags returns a list of tag names for the Dimensions field,
+// and a parallel list of values held by those tags.
+func (p BaseProvider) DimensionsTags() ([]string, []Dimensions) { return nil, nil }
+
+// ExposureTime returns the value of the ExposureTime field.
+func (p BaseProvider) ExposureTime() float64 { return 0 }
+
+// ExposureTimeTags returns a list of tag names for the ExposureTime field,
+// and a parallel list of values held by those tags.
+func (p BaseProvider) ExposureTimeTags() ([]string, []float64) { return nil, nil }
+
 // Faces returns the values of the Faces field.
-func (p BaseProvider) Faces() []string { return nil }
+func (p BaseProvider) Faces() []FaceRegion { return nil }
 
 // FacesTags returns a list of tag names for the Faces field, and a
 // parallel list of values held by those tags.
-func (p BaseProvider) FacesTags() ([]string, [][]string) { return nil, nil }
+func (p BaseProvider) FacesTags() ([]string, [][]FaceRegion) { return nil, nil }
 
 // SetFaces sets the values of the Faces field.
-func (p BaseProvider) SetFaces(values []string) error { return ErrNotSupported }
+func (p BaseProvider) SetFaces(values []FaceRegion) error { return ErrNotSupported }
+
+// FNumber returns the value of the FNumber field.
+func (p BaseProvider) FNumber() float64 { return 0 }
+
+// FNumberTags returns a list of tag names for the FNumber field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) FNumberTags() ([]string, []float64) { return nil, nil }
+
+// FocalLength returns the value of the FocalLength field.
+func (p BaseProvider) FocalLength() float64 { return 0 }
+
+// FocalLengthTags returns a list of tag names for the FocalLength field,
+// and a parallel list of values held by those tags.
+func (p BaseProvider) FocalLengthTags() ([]string, []float64) { return nil, nil }
 
 // GPS returns the values of the GPS field.
 func (p BaseProvider) GPS() GPSCoords { return GPSCoords{} }
@@ -197,6 +401,13 @@ func (p BaseProvider) GPSTags() ([]string, []GPSCoords) { return nil, nil }
 // SetGPS sets the values of the GPS field.
 func (p BaseProvider) SetGPS(value GPSCoords) error { return ErrNotSupported }
 
+// GPSDateTime returns the value of the GPSDateTime field.
+func (p BaseProvider) GPSDateTime() DateTime { return DateTime{} }
+
+// GPSDateTimeTags returns a list of tag names for the GPSDateTime field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) GPSDateTimeTags() ([]string, []DateTime) { return nil, nil }
+
 // Groups returns the values of the Groups field.
 func (p BaseProvider) Groups() []HierValue { return nil }
 
@@ -207,6 +418,13 @@ func (p BaseProvider) GroupsTags() ([]string, [][]HierValue) { return nil, nil }
 // SetGroups sets the values of the Groups field.
 func (p BaseProvider) SetGroups(values []HierValue) error { return ErrNotSupported }
 
+// ISO returns the value of the ISO field.
+func (p BaseProvider) ISO() int { return 0 }
+
+// ISOTags returns a list of tag names for the ISO field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) ISOTags() ([]string, []int) { return nil, nil }
+
 // Keywords returns the values of the Keywords field.
 func (p BaseProvider) Keywords() []HierValue { return nil }
 
@@ -217,6 +435,33 @@ func (p BaseProvider) KeywordsTags() ([]string, [][]HierValue) { return nil, nil
 // SetKeywords sets the values of the Keywords field.
 func (p BaseProvider) SetKeywords(values []HierValue) error { return ErrNotSupported }
 
+// Label returns the value of the Label field.
+func (p BaseProvider) Label() string { return "" }
+
+// LabelTags returns a list of tag names for the Label field, and a parallel
+// list of values held by those tags.
+func (p BaseProvider) LabelTags() ([]string, []string) { return nil, nil }
+
+// SetLabel sets the value of the Label field.
+func (p BaseProvider) SetLabel(value string) error { return ErrNotSupported }
+
+// Lens returns the value of the Lens field.
+func (p BaseProvider) Lens() string { return "" }
+
+// LensTags returns a list of tag names for the Lens field, and a parallel
+// list of values held by those tags.
+func (p BaseProvider) LensTags() ([]string, []string) { return nil, nil }
+
+// License returns the value of the License field.
+func (p BaseProvider) License() string { return "" }
+
+// LicenseTags returns a list of tag names for the License field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) LicenseTags() ([]string, [][]string) { return nil, nil }
+
+// SetLicense sets the value of the License field.
+func (p BaseProvider) SetLicense(value string) error { return ErrNotSupported }
+
 // Location returns the value of the Location field.
 func (p BaseProvider) Location() Location { return Location{} }
 
@@ -227,6 +472,16 @@ func (p BaseProvider) LocationTags() ([]string, [][]Location) { return nil, nil
 // SetLocation sets the value of the Location field.
 func (p BaseProvider) SetLocation(values Location) error { return ErrNotSupported }
 
+// LocationsShown returns the values of the LocationsShown field.
+func (p BaseProvider) LocationsShown() []Location { return nil }
+
+// LocationsShownTags returns a list of tag names for the LocationsShown
+// field, and a parallel list of values held by those tags.
+func (p BaseProvider) LocationsShownTags() ([]string, [][]Location) { return nil, nil }
+
+// SetLocationsShown sets the values of the LocationsShown field.
+func (p BaseProvider) SetLocationsShown(values []Location) error { return ErrNotSupported }
+
 // Orientation returns the value of the Orientation field.
 func (p BaseProvider) Orientation() (value Orientation) { return Rotate0 }
 
@@ -257,6 +512,33 @@ func (p BaseProvider) PlacesTags() ([]string, [][]HierValue) { return nil, nil }
 // SetPlaces sets the values of the Places field.
 func (p BaseProvider) SetPlaces(values []HierValue) error { return ErrNotSupported }
 
+// Rating returns the value of the Rating field.
+func (p BaseProvider) Rating() int { return 0 }
+
+// RatingTags returns a list of tag names for the Rating field, and a parallel
+// list of values held by those tags.
+func (p BaseProvider) RatingTags() ([]string, []int) { return nil, nil }
+
+// SetRating sets the value of the Rating field.
+func (p BaseProvider) SetRating(value int) error { return ErrNotSupported }
+
+// Serial returns the value of the Serial field.
+func (p BaseProvider) Serial() string { return "" }
+
+// SerialTags returns a list of tag names for the Serial field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) SerialTags() ([]string, []string) { return nil, nil }
+
+// Source returns the value of the Source field.
+func (p BaseProvider) Source() string { return "" }
+
+// SourceTags returns a list of tag names for the Source field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) SourceTags() ([]string, [][]string) { return nil, nil }
+
+// SetSource sets the value of the Source field.
+func (p BaseProvider) SetSource(value string) error { return ErrNotSupported }
+
 // Title returns the value of the Title field.
 func (p BaseProvider) Title() string { return "" }
 
@@ -267,6 +549,12 @@ func (p BaseProvider) TitleTags() ([]string, [][]string) { return nil, nil }
 // SetTitle sets the values of the Title field.
 func (p BaseProvider) SetTitle(value string) error { return ErrNotSupported }
 
+// TitleAlt returns the language alternatives of the Title field.
+func (p BaseProvider) TitleAlt() AltString { return nil }
+
+// SetTitleAlt sets the language alternatives of the Title field.
+func (p BaseProvider) SetTitleAlt(value AltString) error { return ErrNotSupported }
+
 // Topics returns the values of the Topics field.
 func (p BaseProvider) Topics() []HierValue { return nil }
 
@@ -285,3 +573,13 @@ type Reader interface {
 	io.Seeker
 	Size() int64
 }
+
+// UsageTerms returns the value of the UsageTerms field.
+func (p BaseProvider) UsageTerms() string { return "" }
+
+// UsageTermsTags returns a list of tag names for the UsageTerms field, and a
+// parallel list of values held by those tags.
+func (p BaseProvider) UsageTermsTags() ([]string, [][]string) { return nil, nil }
+
+// SetUsageTerms sets the value of the UsageTerms field.
+func (p BaseProvider) SetUsageTerms(value string) error { return ErrNotSupported }
@@ -1,5 +1,11 @@
 package metadata
 
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
 // Orientation is an enumerated type indicating the orientation of an image.
 type Orientation uint
 
@@ -16,3 +22,104 @@ const (
 	Rotate270FlipX Orientation = 7
 	Rotate270      Orientation = 8
 )
+
+// orientationNames are the names of the orientations, as rendered by String
+// and accepted by Parse.  They are the names used by exiftool.
+var orientationNames = [...]string{
+	Rotate0:        "Horizontal (normal)",
+	FlipX:          "Mirror horizontal",
+	FlipXY:         "Rotate 180",
+	FlipY:          "Mirror vertical",
+	Rotate90FlipX:  "Mirror horizontal and rotate 270 CW",
+	Rotate90:       "Rotate 90 CW",
+	Rotate270FlipX: "Mirror horizontal and rotate 90 CW",
+	Rotate270:      "Rotate 270 CW",
+}
+
+// ErrParseOrientation is the error returned when a string cannot be parsed
+// into an orientation.
+var ErrParseOrientation = errors.New("invalid orientation: must be 1 through 8 or the name of an orientation")
+
+// Parse sets the value from the input string.  The input is either the
+// numeric value of the orientation or its name, in any case.
+func (o *Orientation) Parse(s string) error {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.Atoi(s); err == nil {
+		if v < int(Rotate0) || v > int(Rotate270) {
+			return ErrParseOrientation
+		}
+		*o = Orientation(v)
+		return nil
+	}
+	if strings.EqualFold(s, "normal") {
+		*o = Rotate0
+		return nil
+	}
+	for v, name := range orientationNames {
+		if name != "" && strings.EqualFold(s, name) {
+			*o = Orientation(v)
+			return nil
+		}
+	}
+	return ErrParseOrientation
+}
+
+// String returns the value in string form, suitable for input to Parse.
+func (o Orientation) String() string {
+	if o == 0 {
+		return orientationNames[Rotate0]
+	}
+	if o > Rotate270 {
+		return strconv.Itoa(int(o))
+	}
+	return orientationNames[o]
+}
+
+// orientationSteps gives, for each orientation, the steps that have to be
+// done to the data to get to the state where (0,0) is the top left corner:
+// first a horizontal mirroring if flip is true, then the specified number of
+// clockwise quarter turns.
+var orientationSteps = [...]struct {
+	turns int
+	flip  bool
+}{
+	0:              {0, false},
+	Rotate0:        {0, false},
+	FlipX:          {0, true},
+	FlipXY:         {2, false},
+	FlipY:          {2, true},
+	Rotate90FlipX:  {3, true},
+	Rotate90:       {1, false},
+	Rotate270FlipX: {1, true},
+	Rotate270:      {3, false},
+}
+
+// Then returns the orientation that results from transforming the data as
+// indicated by the receiver, and then transforming the result as indicated by
+// the argument.  For example, Rotate90.Then(Rotate90) is FlipXY.
+func (o Orientation) Then(next Orientation) Orientation {
+	if o > Rotate270 {
+		o = Rotate0
+	}
+	if next > Rotate270 {
+		next = Rotate0
+	}
+	var (
+		first = orientationSteps[o]
+		then  = orientationSteps[next]
+		turns = first.turns
+	)
+	// A mirroring followed by turns is the same as turns in the opposite
+	// direction followed by a mirroring.
+	if then.flip {
+		turns = -turns
+	}
+	turns = (turns + then.turns + 4) % 4
+	flip := first.flip != then.flip
+	for v, steps := range orientationSteps {
+		if v != 0 && steps.turns == turns && steps.flip == flip {
+			return Orientation(v)
+		}
+	}
+	panic("not reachable")
+}
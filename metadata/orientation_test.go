@@ -0,0 +1,40 @@
+package metadata
+
+import "testing"
+
+func TestOrientation(t *testing.T) {
+	var o Orientation
+	if err := o.Parse("rotate 90 cw"); err != nil || o != Rotate90 {
+		t.Errorf("o.Parse by name failed: %v %d", err, o)
+	}
+	if err := o.Parse("7"); err != nil || o != Rotate270FlipX {
+		t.Errorf("o.Parse by number failed: %v %d", err, o)
+	}
+	if s := o.String(); s != "Mirror horizontal and rotate 90 CW" {
+		t.Errorf("o.String is wrong: %s", s)
+	}
+	if err := o.Parse("9"); err == nil {
+		t.Errorf("o.Parse of 9 succeeded")
+	}
+}
+
+func TestOrientationThen(t *testing.T) {
+	for _, tc := range []struct {
+		first, then, want Orientation
+	}{
+		{0, Rotate90, Rotate90},
+		{Rotate0, Rotate270, Rotate270},
+		{Rotate90, Rotate90, FlipXY},
+		{Rotate90, Rotate270, Rotate0},
+		{FlipXY, Rotate90, Rotate270},
+		{FlipX, FlipX, Rotate0},
+		{FlipX, FlipXY, FlipY},
+		{FlipX, Rotate90, Rotate270FlipX},
+		{Rotate90, FlipX, Rotate90FlipX},
+		{Rotate90FlipX, Rotate90, FlipX},
+	} {
+		if got := tc.first.Then(tc.then); got != tc.want {
+			t.Errorf("%d.Then(%d): got %d, want %d", tc.first, tc.then, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package exififd
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+)
+
+const (
+	tagCanonSerialNumber uint16 = 0x000C
+	tagCanonTimeInfo     uint16 = 0x0035
+	tagCanonLensModel    uint16 = 0x0095
+)
+
+// getCanonMakerNote reads the lens model, camera serial number, and time zone
+// from a Canon MakerNote.
+func (p *Provider) getCanonMakerNote(ifd *tiff.IFD) {
+	if p.mnLens = makerNoteString(ifd, tagCanonLensModel); p.mnLens != "" {
+		p.mnLensTag = "MkNt Canon:LensModel"
+	}
+	if tag := ifd.Tag(tagCanonSerialNumber); tag != nil {
+		if longs, err := tag.AsLongs(); err == nil && len(longs) == 1 && longs[0] != 0 {
+			p.mnSerial = fmt.Sprintf("%010d", longs[0])
+			p.mnSerialTag = "MkNt Canon:SerialNumber"
+		}
+	}
+	// TimeInfo has the time zone offset in minutes at index 1, and the
+	// daylight saving time adjustment in minutes at index 3.
+	if tag := ifd.Tag(tagCanonTimeInfo); tag != nil {
+		if longs, err := tag.AsLongs(); err == nil && len(longs) >= 4 {
+			p.mnZone = makerNoteZone(int(int32(longs[1])) + int(int32(longs[3])))
+		}
+	}
+}
@@ -48,6 +48,12 @@ func (p *Provider) getDateTimeTagGroup(dttag, ottag, ssttag uint16, suffix strin
 			}
 		}
 	}
+	// If the offset isn't recorded, but the camera recorded its time zone
+	// setting in its MakerNote, use that.
+	if oto == "" && p.mnZone != "" {
+		oto = p.mnZone
+		p.mnZoneUsed = true
+	}
 	if err := dt.ParseEXIF(dto, ssto, oto); err != nil {
 		return metadata.DateTime{}, fmt.Errorf("DateTime%s: %s", suffix, err)
 	}
@@ -84,14 +90,16 @@ func (p *Provider) SetDateTime(value metadata.DateTime) error {
 	p.ifd.DeleteTag(tagOffsetTimeDigitized)
 	if value.Empty() {
 		p.dateTimeOriginal = metadata.DateTime{}
+		p.mnZoneUsed = false
 		p.ifd.DeleteTag(tagDateTimeOriginal)
 		p.ifd.DeleteTag(tagSubSecTimeOriginal)
 		p.ifd.DeleteTag(tagOffsetTimeOriginal)
 		return nil
 	}
-	if value.Equivalent(p.dateTimeOriginal) {
+	if value.Equivalent(p.dateTimeOriginal) && !p.mnZoneUsed {
 		return nil
 	}
+	p.mnZoneUsed = false
 	p.dateTimeOriginal = value
 	dto, ssto, oto := value.AsEXIF()
 	p.ifd.AddTag(tagDateTimeOriginal, 2).SetString(dto)
@@ -0,0 +1,52 @@
+package exififd
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+const (
+	tagPixelXDimension uint16 = 0xA002
+	tagPixelYDimension uint16 = 0xA003
+)
+
+// getDimensions reads the value of the Dimensions field from the IFD.
+func (p *Provider) getDimensions() (err error) {
+	var w, h uint32
+
+	wtag, htag := p.ifd.Tag(tagPixelXDimension), p.ifd.Tag(tagPixelYDimension)
+	if wtag == nil || htag == nil {
+		return nil
+	}
+	if w, err = wtag.AsUnsigned(); err != nil {
+		return fmt.Errorf("PixelXDimension: %s", err)
+	}
+	if h, err = htag.AsUnsigned(); err != nil {
+		return fmt.Errorf("PixelYDimension: %s", err)
+	}
+	p.dimensions = metadata.Dimensions{Width: int(w), Height: int(h)}
+	return nil
+}
+
+// Dimensions returns the value of the Dimensions field.
+func (p *Provider) Dimensions() (value metadata.Dimensions) { return p.dimensions }
+
+// DimensionsTags returns a list of tag names for the Dimensions field, and a
+// parallel list of values held by those tags.
+func (p *Provider) DimensionsTags() (tags []string, values []metadata.Dimensions) {
+	return []string{"EXIF PixelX/YDimension"}, []metadata.Dimensions{p.dimensions}
+}
+
+// SetDimensions sets the value of the Dimensions field, if the IFD has
+// dimension tags.  It is not part of the metadata.Provider interface, since
+// the dimensions are a property of the image data; it is used by file format
+// handlers that transform the image data.
+func (p *Provider) SetDimensions(value metadata.Dimensions) {
+	if p.dimensions.Empty() || value == p.dimensions {
+		return
+	}
+	p.dimensions = value
+	p.ifd.AddTag(tagPixelXDimension, 3).SetShort(value.Width)
+	p.ifd.AddTag(tagPixelYDimension, 3).SetShort(value.Height)
+}
@@ -2,6 +2,7 @@ package exififd
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"github.com/rothskeller/photo-tools/metadata"
@@ -13,8 +14,23 @@ type Provider struct {
 	metadata.BaseProvider
 	dateTimeDigitized metadata.DateTime
 	dateTimeOriginal  metadata.DateTime
+	dimensions        metadata.Dimensions
+	exposureTime      float64
+	fNumber           float64
+	focalLength       float64
+	iso               int
 	orientation       metadata.Orientation
 	userComment       string
+	lensModel         string
+	bodySerialNumber  string
+
+	// Values decoded from the MakerNote, if any.
+	mnLens      string
+	mnLensTag   string
+	mnSerial    string
+	mnSerialTag string
+	mnZone      string
+	mnZoneUsed  bool
 
 	ifd *tiff.IFD
 	enc binary.ByteOrder
@@ -25,17 +41,56 @@ var _ metadata.Provider = (*Provider)(nil) // verify interface compliance
 // New creates a new Provider based on the provided IFD.
 func New(ifd *tiff.IFD, enc binary.ByteOrder) (p *Provider, err error) {
 	p = &Provider{ifd: ifd, enc: enc}
+	p.getMakerNote()
 	if err = p.getCaption(); err != nil {
 		return nil, fmt.Errorf("EXIF IFD: %s", err)
 	}
 	if err = p.getDateTime(); err != nil {
 		return nil, fmt.Errorf("EXIF IFD: %s", err)
 	}
+	if err = p.getDimensions(); err != nil {
+		return nil, fmt.Errorf("EXIF IFD: %s", err)
+	}
+	if err = p.getExposureTime(); err != nil {
+		return nil, fmt.Errorf("EXIF IFD: %s", err)
+	}
+	if err = p.getFNumber(); err != nil {
+		return nil, fmt.Errorf("EXIF IFD: %s", err)
+	}
+	if err = p.getFocalLength(); err != nil {
+		return nil, fmt.Errorf("EXIF IFD: %s", err)
+	}
+	if err = p.getISO(); err != nil {
+		return nil, fmt.Errorf("EXIF IFD: %s", err)
+	}
+	if err = p.getLens(); err != nil {
+		return nil, fmt.Errorf("EXIF IFD: %s", err)
+	}
 	if err = p.getOrientation(); err != nil {
 		return nil, fmt.Errorf("EXIF IFD: %s", err)
 	}
+	if err = p.getSerial(); err != nil {
+		return nil, fmt.Errorf("EXIF IFD: %s", err)
+	}
 	return p, nil
 }
 
+// getRational returns the value of a RATIONAL tag as a floating point number.
+// It returns zero if the tag has a zero denominator, which some cameras use to
+// indicate an unknown value.
+func getRational(tag *tiff.Tag) (float64, error) {
+	rat, err := tag.AsRationals()
+	if err != nil {
+		return 0, err
+	}
+	if len(rat) < 2 {
+		return 0, errors.New("tag is empty")
+	}
+	if rat[1] == 0 {
+		return 0, nil
+	}
+	return float64(rat[0]) / float64(rat[1]), nil
+}
+
 // ProviderName is the name for the provider, for debug purposes.
 func (p *Provider) ProviderName() string { return "EXIF IFD" }
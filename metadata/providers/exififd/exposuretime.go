@@ -0,0 +1,28 @@
+package exififd
+
+import (
+	"fmt"
+)
+
+const tagExposureTime uint16 = 0x829A
+
+// getExposureTime reads the value of the ExposureTime field from the IFD.
+func (p *Provider) getExposureTime() (err error) {
+	tag := p.ifd.Tag(tagExposureTime)
+	if tag == nil {
+		return nil
+	}
+	if p.exposureTime, err = getRational(tag); err != nil {
+		return fmt.Errorf("ExposureTime: %s", err)
+	}
+	return nil
+}
+
+// ExposureTime returns the value of the ExposureTime field.
+func (p *Provider) ExposureTime() (value float64) { return p.exposureTime }
+
+// ExposureTimeTags returns a list of tag names for the ExposureTime field, and a
+// parallel list of values held by those tags.
+func (p *Provider) ExposureTimeTags() (tags []string, values []float64) {
+	return []string{"EXIF ExposureTime"}, []float64{p.exposureTime}
+}
@@ -0,0 +1,28 @@
+package exififd
+
+import (
+	"fmt"
+)
+
+const tagFNumber uint16 = 0x829D
+
+// getFNumber reads the value of the FNumber field from the IFD.
+func (p *Provider) getFNumber() (err error) {
+	tag := p.ifd.Tag(tagFNumber)
+	if tag == nil {
+		return nil
+	}
+	if p.fNumber, err = getRational(tag); err != nil {
+		return fmt.Errorf("FNumber: %s", err)
+	}
+	return nil
+}
+
+// FNumber returns the value of the FNumber field.
+func (p *Provider) FNumber() (value float64) { return p.fNumber }
+
+// FNumberTags returns a list of tag names for the FNumber field, and a parallel
+// list of values held by those tags.
+func (p *Provider) FNumberTags() (tags []string, values []float64) {
+	return []string{"EXIF FNumber"}, []float64{p.fNumber}
+}
@@ -0,0 +1,28 @@
+package exififd
+
+import (
+	"fmt"
+)
+
+const tagFocalLength uint16 = 0x920A
+
+// getFocalLength reads the value of the FocalLength field from the IFD.
+func (p *Provider) getFocalLength() (err error) {
+	tag := p.ifd.Tag(tagFocalLength)
+	if tag == nil {
+		return nil
+	}
+	if p.focalLength, err = getRational(tag); err != nil {
+		return fmt.Errorf("FocalLength: %s", err)
+	}
+	return nil
+}
+
+// FocalLength returns the value of the FocalLength field.
+func (p *Provider) FocalLength() (value float64) { return p.focalLength }
+
+// FocalLengthTags returns a list of tag names for the FocalLength field, and a
+// parallel list of values held by those tags.
+func (p *Provider) FocalLengthTags() (tags []string, values []float64) {
+	return []string{"EXIF FocalLength"}, []float64{p.focalLength}
+}
@@ -0,0 +1,30 @@
+package exififd
+
+import (
+	"fmt"
+)
+
+const tagISO uint16 = 0x8827
+
+// getISO reads the value of the ISO field from the IFD.
+func (p *Provider) getISO() (err error) {
+	tag := p.ifd.Tag(tagISO)
+	if tag == nil {
+		return nil
+	}
+	var iso uint32
+	if iso, err = tag.AsUnsigned(); err != nil {
+		return fmt.Errorf("ISOSpeedRatings: %s", err)
+	}
+	p.iso = int(iso)
+	return nil
+}
+
+// ISO returns the value of the ISO field.
+func (p *Provider) ISO() (value int) { return p.iso }
+
+// ISOTags returns a list of tag names for the ISO field, and a parallel
+// list of values held by those tags.
+func (p *Provider) ISOTags() (tags []string, values []int) {
+	return []string{"EXIF ISOSpeedRatings"}, []int{p.iso}
+}
@@ -0,0 +1,43 @@
+package exififd
+
+import (
+	"fmt"
+)
+
+const tagLensModel uint16 = 0xA434
+
+// getLens reads the value of the Lens field from the IFD.
+func (p *Provider) getLens() (err error) {
+	tag := p.ifd.Tag(tagLensModel)
+	if tag == nil {
+		return nil
+	}
+	if p.lensModel, err = tag.AsString(); err != nil {
+		return fmt.Errorf("LensModel: %s", err)
+	}
+	return nil
+}
+
+// Lens returns the value of the Lens field.
+func (p *Provider) Lens() (value string) {
+	if p.lensModel != "" {
+		return p.lensModel
+	}
+	return p.mnLens
+}
+
+// LensTags returns a list of tag names for the Lens field, and a parallel list
+// of values held by those tags.
+func (p *Provider) LensTags() (tags []string, values []string) {
+	// The EXIF tag isn't listed if it's empty and the MakerNote has the
+	// value, since it's read-only and therefore can't be fixed.
+	if p.lensModel != "" || p.mnLensTag == "" {
+		tags = append(tags, "EXIF LensModel")
+		values = append(values, p.lensModel)
+	}
+	if p.mnLensTag != "" {
+		tags = append(tags, p.mnLensTag)
+		values = append(values, p.mnLens)
+	}
+	return tags, values
+}
@@ -0,0 +1,63 @@
+package exififd
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+)
+
+const tagMakerNote uint16 = 0x927C
+
+// getMakerNote reads the camera vendor's MakerNote, if it's in a format we know
+// how to decode.  MakerNotes are undocumented and often mangled by other
+// software, so any problems decoding one are silently ignored.
+func (p *Provider) getMakerNote() {
+	var (
+		vendor string
+		ifd    *tiff.IFD
+		err    error
+	)
+	tag := p.ifd.Tag(tagMakerNote)
+	if tag == nil {
+		return
+	}
+	if vendor, ifd, err = tag.AsMakerNote(); err != nil {
+		return
+	}
+	switch vendor {
+	case "Canon":
+		p.getCanonMakerNote(ifd)
+	case "Nikon":
+		p.getNikonMakerNote(ifd)
+	case "Olympus", "OM System":
+		p.getOlympusMakerNote(vendor, ifd)
+	case "Sony":
+		p.getSonyMakerNote(ifd)
+	}
+}
+
+// makerNoteString returns the value of a string tag in a MakerNote IFD, or an
+// empty string if it isn't present or can't be read.
+func makerNoteString(ifd *tiff.IFD, id uint16) string {
+	if tag := ifd.Tag(id); tag != nil {
+		if s, err := tag.AsString(); err == nil {
+			return s
+		}
+	}
+	return ""
+}
+
+// makerNoteZone returns a time zone offset, given in minutes east of UTC, in
+// the form used by EXIF OffsetTime tags.  It returns an empty string if the
+// offset is out of range.
+func makerNoteZone(minutes int) string {
+	var sign = '+'
+
+	if minutes < -12*60 || minutes > 14*60 {
+		return ""
+	}
+	if minutes < 0 {
+		sign, minutes = '-', -minutes
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, minutes/60, minutes%60)
+}
@@ -0,0 +1,61 @@
+package exififd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+)
+
+const (
+	tagNikonSerialNumber uint16 = 0x001D
+	tagNikonWorldTime    uint16 = 0x0024
+	tagNikonLens         uint16 = 0x0084
+)
+
+// getNikonMakerNote reads the lens specification, camera serial number, and
+// time zone from a Nikon MakerNote.
+func (p *Provider) getNikonMakerNote(ifd *tiff.IFD) {
+	if p.mnSerial = makerNoteString(ifd, tagNikonSerialNumber); p.mnSerial != "" {
+		p.mnSerialTag = "MkNt Nikon:SerialNumber"
+	}
+	// The Lens tag has the minimum and maximum focal lengths, and the
+	// maximum apertures at each of those.
+	if tag := ifd.Tag(tagNikonLens); tag != nil {
+		if rat, err := tag.AsRationals(); err == nil && len(rat) == 8 {
+			if p.mnLens = nikonLens(rat); p.mnLens != "" {
+				p.mnLensTag = "MkNt Nikon:Lens"
+			}
+		}
+	}
+	// WorldTime has the time zone offset in minutes (signed 16-bit), a
+	// daylight saving time flag byte, and a date format byte.
+	if tag := ifd.Tag(tagNikonWorldTime); tag != nil {
+		if by, err := tag.AsUnknown(); err == nil && len(by) == 4 {
+			minutes := int(int16(ifd.Encoding().Uint16(by[0:2])))
+			if by[2] == 1 {
+				minutes += 60
+			}
+			p.mnZone = makerNoteZone(minutes)
+		}
+	}
+}
+
+// nikonLens renders a Nikon lens specification, e.g. "18-55mm f/3.5-5.6".
+func nikonLens(rat []uint32) string {
+	var vals [4]string
+
+	for i := range vals {
+		if rat[2*i+1] == 0 || rat[2*i] == 0 {
+			return ""
+		}
+		vals[i] = strconv.FormatFloat(float64(rat[2*i])/float64(rat[2*i+1]), 'f', -1, 64)
+	}
+	if vals[0] == vals[1] {
+		return fmt.Sprintf("%smm f/%s", vals[0], vals[2])
+	}
+	if vals[2] == vals[3] {
+		return fmt.Sprintf("%s-%smm f/%s", vals[0], vals[1], vals[2])
+	}
+	return fmt.Sprintf("%s-%smm f/%s-%s", vals[0], vals[1], vals[2], vals[3])
+}
@@ -0,0 +1,31 @@
+package exififd
+
+import (
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+)
+
+const (
+	tagOlympusEquipment    uint16 = 0x2010
+	tagOlympusSerialNumber uint16 = 0x0101
+	tagOlympusLensModel    uint16 = 0x0203
+)
+
+// getOlympusMakerNote reads the lens model and camera serial number from the
+// Equipment IFD of an Olympus (or OM System) MakerNote.  Olympus cameras don't
+// record their time zone setting.
+func (p *Provider) getOlympusMakerNote(vendor string, ifd *tiff.IFD) {
+	tag := ifd.Tag(tagOlympusEquipment)
+	if tag == nil {
+		return
+	}
+	equipment, err := tag.AsIFD()
+	if err != nil {
+		return
+	}
+	if p.mnSerial = makerNoteString(equipment, tagOlympusSerialNumber); p.mnSerial != "" {
+		p.mnSerialTag = "MkNt " + vendor + ":SerialNumber"
+	}
+	if p.mnLens = makerNoteString(equipment, tagOlympusLensModel); p.mnLens != "" {
+		p.mnLensTag = "MkNt " + vendor + ":LensModel"
+	}
+}
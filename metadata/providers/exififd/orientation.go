@@ -37,12 +37,13 @@ func (p *Provider) OrientationTags() (tags []string, values [][]metadata.Orienta
 	return []string{"EXIF Orientation"}, [][]metadata.Orientation{{p.orientation}}
 }
 
-// SetOrientation sets the value of the Orientation field.
+// SetOrientation sets the value of the Orientation field.  The Orientation
+// tag belongs in IFD0, so it is changed here only if it was already present.
 func (p *Provider) SetOrientation(value metadata.Orientation) error {
 	if value == 0 || value == metadata.Rotate0 {
 		p.orientation = 0
 		p.ifd.DeleteTag(tagOrientation)
-	} else {
+	} else if p.ifd.Tag(tagOrientation) != nil {
 		p.orientation = value
 		p.ifd.AddTag(tagOrientation, 3).SetShort(int(value))
 	}
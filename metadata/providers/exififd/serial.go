@@ -0,0 +1,43 @@
+package exififd
+
+import (
+	"fmt"
+)
+
+const tagBodySerialNumber uint16 = 0xA431
+
+// getSerial reads the value of the Serial field from the IFD.
+func (p *Provider) getSerial() (err error) {
+	tag := p.ifd.Tag(tagBodySerialNumber)
+	if tag == nil {
+		return nil
+	}
+	if p.bodySerialNumber, err = tag.AsString(); err != nil {
+		return fmt.Errorf("BodySerialNumber: %s", err)
+	}
+	return nil
+}
+
+// Serial returns the value of the Serial field.
+func (p *Provider) Serial() (value string) {
+	if p.bodySerialNumber != "" {
+		return p.bodySerialNumber
+	}
+	return p.mnSerial
+}
+
+// SerialTags returns a list of tag names for the Serial field, and a parallel
+// list of values held by those tags.
+func (p *Provider) SerialTags() (tags []string, values []string) {
+	// The EXIF tag isn't listed if it's empty and the MakerNote has the
+	// value, since it's read-only and therefore can't be fixed.
+	if p.bodySerialNumber != "" || p.mnSerialTag == "" {
+		tags = append(tags, "EXIF BodySerialNumber")
+		values = append(values, p.bodySerialNumber)
+	}
+	if p.mnSerialTag != "" {
+		tags = append(tags, p.mnSerialTag)
+		values = append(values, p.mnSerial)
+	}
+	return tags, values
+}
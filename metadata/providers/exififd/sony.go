@@ -0,0 +1,17 @@
+package exififd
+
+import (
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+)
+
+const tagSonySerialNumber uint16 = 0x2031
+
+// getSonyMakerNote reads the camera serial number from a Sony MakerNote.  Sony
+// cameras record their time zone and daylight saving time settings only in
+// enciphered tags whose layout varies from model to model, so those aren't
+// read.
+func (p *Provider) getSonyMakerNote(ifd *tiff.IFD) {
+	if p.mnSerial = makerNoteString(ifd, tagSonySerialNumber); p.mnSerial != "" {
+		p.mnSerialTag = "MkNt Sony:SerialNumber"
+	}
+}
@@ -0,0 +1,61 @@
+package gpsifd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+const (
+	tagGPSTimeStamp uint16 = 7
+	tagGPSDateStamp uint16 = 29
+)
+
+// getGPSDateTime reads the value of the GPSDateTime field from the IFD.  Some
+// cameras fill the tags with blanks or zeros when they have no fix; those are
+// treated as absent.
+func (p *Provider) getGPSDateTime() (err error) {
+	var (
+		datestamp string
+		timestamp []uint32
+	)
+	datet := p.ifd.Tag(tagGPSDateStamp)
+	timet := p.ifd.Tag(tagGPSTimeStamp)
+	if datet == nil || timet == nil {
+		return nil
+	}
+	if datestamp, err = datet.AsString(); err != nil {
+		return fmt.Errorf("GPSDateStamp: %s", err)
+	}
+	if timestamp, err = timet.AsRationals(); err != nil {
+		return fmt.Errorf("GPSTimeStamp: %s", err)
+	}
+	date, err := time.Parse("2006:01:02", strings.TrimSpace(datestamp))
+	if err != nil || date.Year() < 1980 || len(timestamp) != 6 || timestamp[1] == 0 || timestamp[3] == 0 || timestamp[5] == 0 {
+		return nil
+	}
+	hours := float64(timestamp[0]) / float64(timestamp[1])
+	minutes := float64(timestamp[2]) / float64(timestamp[3])
+	seconds := float64(timestamp[4]) / float64(timestamp[5])
+	if hours >= 24 || minutes >= 60 || seconds >= 61 {
+		return nil
+	}
+	t := date.Add(time.Duration((hours*3600 + minutes*60 + seconds) * float64(time.Second))).Round(time.Millisecond)
+	s := t.Format("2006-01-02T15:04:05.000")
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return p.gpsDateTime.Parse(s + "Z")
+}
+
+// GPSDateTime returns the value of the GPSDateTime field.
+func (p *Provider) GPSDateTime() (value metadata.DateTime) { return p.gpsDateTime }
+
+// GPSDateTimeTags returns a list of tag names for the GPSDateTime field, and a
+// parallel list of values held by those tags.
+func (p *Provider) GPSDateTimeTags() (tags []string, values []metadata.DateTime) {
+	if p.ifd.Tag(tagGPSDateStamp) == nil && p.ifd.Tag(tagGPSTimeStamp) == nil {
+		return nil, nil
+	}
+	return []string{"GPS  GPSDateStamp/GPSTimeStamp"}, []metadata.DateTime{p.gpsDateTime}
+}
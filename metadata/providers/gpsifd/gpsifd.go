@@ -10,7 +10,8 @@ import (
 // A Provider handles metadata in a GPS IFD.
 type Provider struct {
 	metadata.BaseProvider
-	gpsCoords metadata.GPSCoords
+	gpsCoords   metadata.GPSCoords
+	gpsDateTime metadata.DateTime
 
 	ifd *tiff.IFD
 }
@@ -23,6 +24,9 @@ func New(ifd *tiff.IFD) (p *Provider, err error) {
 	if err = p.getGPS(); err != nil {
 		return nil, fmt.Errorf("GPS IFD: %s", err)
 	}
+	if err = p.getGPSDateTime(); err != nil {
+		return nil, fmt.Errorf("GPS IFD: %s", err)
+	}
 	return p, nil
 }
 
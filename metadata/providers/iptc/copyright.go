@@ -0,0 +1,57 @@
+package iptc
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	idCopyrightNotice     uint16 = 0x0274
+	maxCopyrightNoticeLen        = 128
+)
+
+// getCopyright reads the value of the Copyright field from the IIM.
+func (p *Provider) getCopyright() (err error) {
+	switch dss := p.iim.DataSets(idCopyrightNotice); len(dss) {
+	case 0:
+		break
+	case 1:
+		if p.copyrightNotice, err = getString(dss[0]); err != nil {
+			return fmt.Errorf("Copyright Notice: %s", err)
+		}
+	default:
+		return errors.New("Copyright Notice: multiple data sets")
+	}
+	return nil
+}
+
+// Copyright returns the value of the Copyright field.
+func (p *Provider) Copyright() (value string) { return p.copyrightNotice }
+
+// CopyrightTags returns a list of tag names for the Copyright field, and a
+// parallel list of values held by those tags.
+func (p *Provider) CopyrightTags() (tags []string, values [][]string) {
+	if p.copyrightNotice == "" {
+		return []string{"IPTC Copyright Notice"}, [][]string{nil}
+	}
+	return []string{"IPTC Copyright Notice"}, [][]string{{p.copyrightNotice}}
+}
+
+// SetCopyright sets the value of the Copyright field.
+func (p *Provider) SetCopyright(value string) error {
+	if value == "" {
+		p.copyrightNotice = ""
+		p.iim.RemoveDataSets(idCopyrightNotice)
+		return nil
+	}
+	if len(value) > maxCopyrightNoticeLen {
+		value = value[:maxCopyrightNoticeLen]
+	}
+	if value == p.copyrightNotice {
+		return nil
+	}
+	p.copyrightNotice = value
+	p.iim.SetDataSet(idCopyrightNotice, []byte(value))
+	p.setEncoding()
+	return nil
+}
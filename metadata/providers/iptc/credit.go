@@ -0,0 +1,57 @@
+package iptc
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	idCredit     uint16 = 0x026E
+	maxCreditLen        = 32
+)
+
+// getCredit reads the value of the Credit field from the IIM.
+func (p *Provider) getCredit() (err error) {
+	switch dss := p.iim.DataSets(idCredit); len(dss) {
+	case 0:
+		break
+	case 1:
+		if p.credit, err = getString(dss[0]); err != nil {
+			return fmt.Errorf("Credit: %s", err)
+		}
+	default:
+		return errors.New("Credit: multiple data sets")
+	}
+	return nil
+}
+
+// Credit returns the value of the Credit field.
+func (p *Provider) Credit() (value string) { return p.credit }
+
+// CreditTags returns a list of tag names for the Credit field, and a
+// parallel list of values held by those tags.
+func (p *Provider) CreditTags() (tags []string, values [][]string) {
+	if p.credit == "" {
+		return []string{"IPTC Credit"}, [][]string{nil}
+	}
+	return []string{"IPTC Credit"}, [][]string{{p.credit}}
+}
+
+// SetCredit sets the value of the Credit field.
+func (p *Provider) SetCredit(value string) error {
+	if value == "" {
+		p.credit = ""
+		p.iim.RemoveDataSets(idCredit)
+		return nil
+	}
+	if len(value) > maxCreditLen {
+		value = value[:maxCreditLen]
+	}
+	if value == p.credit {
+		return nil
+	}
+	p.credit = value
+	p.iim.SetDataSet(idCredit, []byte(value))
+	p.setEncoding()
+	return nil
+}
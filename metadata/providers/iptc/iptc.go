@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/rothskeller/photo-tools/metadata"
@@ -11,7 +12,13 @@ import (
 	"golang.org/x/text/encoding/charmap"
 )
 
-const idCodedCharacterSet uint16 = 0x015A
+const (
+	idCodedCharacterSet uint16 = 0x015A
+	idRecordVersion     uint16 = 0x0200
+	idRasterizedCaption uint16 = 0x027D
+	idPreviewFormat     uint16 = 0x02C8
+	idPreviewData       uint16 = 0x02CA
+)
 
 // A Provider handles data from an IPTC IIM block.
 type Provider struct {
@@ -19,16 +26,20 @@ type Provider struct {
 	bylines                 []string
 	captionAbstract         string
 	city                    string
+	copyrightNotice         string
 	countryPLCode           string
 	countryPLName           string
+	credit                  string
 	dateTimeCreated         metadata.DateTime
 	digitalCreationDateTime metadata.DateTime
 	keywords                []string
 	objectName              string
 	provinceState           string
+	source                  string
 	sublocation             string
 
-	iim *iim.IIM
+	iim     *iim.IIM
+	charset string // legacy character set the IIM block was read in, if any
 }
 
 var utf8Escape1 = []byte{0x1B, 0x25, 0x47}
@@ -39,15 +50,21 @@ var _ metadata.Provider = (*Provider)(nil) // verify interface compliance
 // New creates a new Provider based on the provided IIM block.
 func New(iim *iim.IIM) (p *Provider, err error) {
 	p = &Provider{iim: iim}
-	if err = p.verifyEncoding(); err != nil {
+	if err = p.getEncoding(); err != nil {
 		return nil, fmt.Errorf("IPTC IIM: %s", err)
 	}
 	if err = p.getCaption(); err != nil {
 		return nil, fmt.Errorf("IPTC IIM: %s", err)
 	}
+	if err = p.getCopyright(); err != nil {
+		return nil, fmt.Errorf("IPTC IIM: %s", err)
+	}
 	if err = p.getCreator(); err != nil {
 		return nil, fmt.Errorf("IPTC IIM: %s", err)
 	}
+	if err = p.getCredit(); err != nil {
+		return nil, fmt.Errorf("IPTC IIM: %s", err)
+	}
 	if err = p.getDateTime(); err != nil {
 		return nil, fmt.Errorf("IPTC IIM: %s", err)
 	}
@@ -57,6 +74,9 @@ func New(iim *iim.IIM) (p *Provider, err error) {
 	if err = p.getLocation(); err != nil {
 		return nil, fmt.Errorf("IPTC IIM: %s", err)
 	}
+	if err = p.getSource(); err != nil {
+		return nil, fmt.Errorf("IPTC IIM: %s", err)
+	}
 	if err = p.getTitle(); err != nil {
 		return nil, fmt.Errorf("IPTC IIM: %s", err)
 	}
@@ -66,20 +86,120 @@ func New(iim *iim.IIM) (p *Provider, err error) {
 // ProviderName is the name for the provider, for debug purposes.
 func (p *Provider) ProviderName() string { return "IPTC" }
 
-func (p *Provider) verifyEncoding() (err error) {
+// legacyEscapes maps the Coded Character Set values for legacy character sets
+// to the names of those character sets.
+var legacyEscapes = map[string]string{
+	"\x1B\x2D\x41": "ISO-8859-1", // G1 = ISO 8859-1 right half
+	"\x1B\x2E\x41": "ISO-8859-1", // G2 = ISO 8859-1 right half
+}
+
+// legacyCharsets maps the names of the legacy character sets we understand to
+// their decoders.  ISO 8859-1 is decoded as Windows-1252, since that's what
+// the software that wrote it almost always actually used.
+var legacyCharsets = map[string]*charmap.Charmap{
+	"ISO-8859-1":   charmap.Windows1252,
+	"Windows-1252": charmap.Windows1252,
+	"Mac Roman":    charmap.Macintosh,
+}
+
+// getEncoding determines the character set of the text data sets in the IIM
+// block.  If it is a legacy character set, the text data sets are transcoded
+// to UTF-8 (which will be written the next time the block is written).
+func (p *Provider) getEncoding() (err error) {
 	switch dss := p.iim.DataSets(idCodedCharacterSet); len(dss) {
 	case 0:
-		break
+		p.charset = p.guessEncoding()
 	case 1:
-		if !bytes.Equal(dss[0].Data, utf8Escape1) && !bytes.Equal(dss[0].Data, utf8Escape2) {
-			return errors.New("Coded Character Set: not UTF-8")
+		if bytes.Equal(dss[0].Data, utf8Escape1) || bytes.Equal(dss[0].Data, utf8Escape2) {
+			break
+		}
+		if p.charset = legacyEscapes[string(dss[0].Data)]; p.charset == "" {
+			return errors.New("Coded Character Set: unsupported character set")
 		}
 	default:
 		return errors.New("Coded Character Set: multiple data sets")
 	}
+	if p.charset != "" {
+		p.iim.Transcode(legacyCharsets[p.charset].NewDecoder(), isText)
+	}
 	return nil
 }
 
+// guessEncoding guesses the character set of an IIM block that doesn't say
+// what it is.  If all of the text is valid UTF-8, we assume it's UTF-8.
+// Otherwise, it's most likely Windows-1252 or Mac Roman.  We choose the one
+// under which the non-ASCII bytes look most like accented letters.
+func (p *Provider) guessEncoding() string {
+	var valid = true
+	var mac, win int
+
+	for _, id := range p.iim.DataSetIDs() {
+		if !isText(id) {
+			continue
+		}
+		for _, ds := range p.iim.DataSets(id) {
+			if utf8.Valid(ds.Data) {
+				continue
+			}
+			valid = false
+			for _, b := range ds.Data {
+				if b >= 0x80 {
+					mac += letterScore(charmap.Macintosh.DecodeByte(b))
+					win += letterScore(charmap.Windows1252.DecodeByte(b))
+				}
+			}
+		}
+	}
+	switch {
+	case valid:
+		return ""
+	case mac > win:
+		return "Mac Roman"
+	default:
+		return "Windows-1252"
+	}
+}
+
+// letterScore rates how plausible a decoded character is in running text.
+// Accented lowercase letters are by far the most common non-ASCII characters
+// in captions and keywords, so they score highest.
+func letterScore(r rune) int {
+	switch {
+	case unicode.IsLower(r):
+		return 2
+	case unicode.IsLetter(r):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// isText returns whether the data set with the specified ID contains text.
+// That's true of all application record data sets except for the few that
+// contain binary data.
+func isText(id uint16) bool {
+	switch {
+	case id>>8 != 2:
+		return false
+	case id == idRecordVersion, id == idRasterizedCaption, id >= idPreviewFormat && id <= idPreviewData:
+		return false
+	}
+	return true
+}
+
+// LegacyCharset returns the name of the legacy character set in which the IIM
+// block was read, if any.  Its text has been transcoded to UTF-8, and it will
+// be written in UTF-8.
+func (p *Provider) LegacyCharset() string { return p.charset }
+
+// Transcode ensures that, if the IIM block was read in a legacy character
+// set, it will be written (in UTF-8) even if it hasn't otherwise changed.
+func (p *Provider) Transcode() {
+	if p.charset != "" {
+		p.setEncoding()
+	}
+}
+
 // setEncoding adds the record that defines the encoding as UTF-8.  It is called
 // whenever a data set with a string value is changed.
 func (p *Provider) setEncoding() {
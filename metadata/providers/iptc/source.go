@@ -0,0 +1,57 @@
+package iptc
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	idSource     uint16 = 0x0273
+	maxSourceLen        = 32
+)
+
+// getSource reads the value of the Source field from the IIM.
+func (p *Provider) getSource() (err error) {
+	switch dss := p.iim.DataSets(idSource); len(dss) {
+	case 0:
+		break
+	case 1:
+		if p.source, err = getString(dss[0]); err != nil {
+			return fmt.Errorf("Source: %s", err)
+		}
+	default:
+		return errors.New("Source: multiple data sets")
+	}
+	return nil
+}
+
+// Source returns the value of the Source field.
+func (p *Provider) Source() (value string) { return p.source }
+
+// SourceTags returns a list of tag names for the Source field, and a
+// parallel list of values held by those tags.
+func (p *Provider) SourceTags() (tags []string, values [][]string) {
+	if p.source == "" {
+		return []string{"IPTC Source"}, [][]string{nil}
+	}
+	return []string{"IPTC Source"}, [][]string{{p.source}}
+}
+
+// SetSource sets the value of the Source field.
+func (p *Provider) SetSource(value string) error {
+	if value == "" {
+		p.source = ""
+		p.iim.RemoveDataSets(idSource)
+		return nil
+	}
+	if len(value) > maxSourceLen {
+		value = value[:maxSourceLen]
+	}
+	if value == p.source {
+		return nil
+	}
+	p.source = value
+	p.iim.SetDataSet(idSource, []byte(value))
+	p.setEncoding()
+	return nil
+}
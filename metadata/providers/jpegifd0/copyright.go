@@ -0,0 +1,53 @@
+package jpegifd0
+
+import (
+	"fmt"
+	"strings"
+)
+
+const tagCopyright uint16 = 0x8298
+
+// getCopyright reads the value of the Copyright field from the IFD.
+func (p *Provider) getCopyright() (err error) {
+	if tag := p.ifd.Tag(tagCopyright); tag != nil {
+		var s string
+		if s, err = tag.AsString(); err != nil {
+			return fmt.Errorf("Copyright: %s", err)
+		}
+		// The tag may contain separate photographer and editor
+		// copyrights, separated by a NUL.  We use the photographer
+		// copyright unless only the editor copyright is present.
+		photographer, editor, _ := strings.Cut(s, "\000")
+		if p.copyright = strings.TrimSpace(photographer); p.copyright == "" {
+			p.copyright = strings.TrimSpace(editor)
+		}
+	}
+	return nil
+}
+
+// Copyright returns the value of the Copyright field.
+func (p *Provider) Copyright() (value string) { return p.copyright }
+
+// CopyrightTags returns a list of tag names for the Copyright field, and a
+// parallel list of values held by those tags.
+func (p *Provider) CopyrightTags() (tags []string, values [][]string) {
+	if p.copyright == "" {
+		return []string{"IFD0 Copyright"}, [][]string{nil}
+	}
+	return []string{"IFD0 Copyright"}, [][]string{{p.copyright}}
+}
+
+// SetCopyright sets the value of the Copyright field.
+func (p *Provider) SetCopyright(value string) error {
+	if value == "" {
+		p.copyright = ""
+		p.ifd.DeleteTag(tagCopyright)
+		return nil
+	}
+	if value == p.copyright {
+		return nil
+	}
+	p.copyright = value
+	p.ifd.AddTag(tagCopyright, 2).SetString(value)
+	return nil
+}
@@ -0,0 +1,69 @@
+package jpegifd0
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+const (
+	tagRating        uint16 = 0x4746
+	tagRatingPercent uint16 = 0x4749
+)
+
+// getRating reads the value of the Rating field from the IFD.
+func (p *Provider) getRating() (err error) {
+	if tag := p.ifd.Tag(tagRating); tag != nil {
+		if p.rating, err = tag.AsShort(); err != nil {
+			return fmt.Errorf("Rating: %s", err)
+		}
+		if p.rating > 5 {
+			return fmt.Errorf("Rating: invalid value %d", p.rating)
+		}
+	}
+	if tag := p.ifd.Tag(tagRatingPercent); tag != nil {
+		var percent int
+
+		if percent, err = tag.AsShort(); err != nil {
+			return fmt.Errorf("RatingPercent: %s", err)
+		}
+		if percent > 100 {
+			return fmt.Errorf("RatingPercent: invalid value %d", percent)
+		}
+		p.ratingPercent = metadata.PercentToRating(percent)
+	}
+	return nil
+}
+
+// Rating returns the value of the Rating field.
+func (p *Provider) Rating() (value int) {
+	if p.rating != 0 {
+		return p.rating
+	}
+	return p.ratingPercent
+}
+
+// RatingTags returns a list of tag names for the Rating field, and a parallel
+// list of values held by those tags.
+func (p *Provider) RatingTags() (tags []string, values []int) {
+	return []string{"IFD0 Rating", "IFD0 RatingPercent"}, []int{p.rating, p.ratingPercent}
+}
+
+// SetRating sets the value of the Rating field.
+func (p *Provider) SetRating(value int) error {
+	// These tags can't represent rejected media, so we remove them for
+	// rejected media as well as for unrated media.
+	if value <= 0 {
+		p.rating, p.ratingPercent = 0, 0
+		p.ifd.DeleteTag(tagRating)
+		p.ifd.DeleteTag(tagRatingPercent)
+		return nil
+	}
+	if value == p.rating && value == p.ratingPercent {
+		return nil
+	}
+	p.rating, p.ratingPercent = value, value
+	p.ifd.AddTag(tagRating, 3).SetShort(value)
+	p.ifd.AddTag(tagRatingPercent, 3).SetShort(metadata.RatingToPercent(value))
+	return nil
+}
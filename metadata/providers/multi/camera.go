@@ -0,0 +1,22 @@
+package multi
+
+// Camera returns the value of the Camera field.
+func (p Provider) Camera() (value string) {
+	for _, sp := range p {
+		if value = sp.Camera(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// CameraTags returns a list of tag names for the Camera field, and a parallel
+// list of values held by those tags.
+func (p Provider) CameraTags() (tags []string, values []string) {
+	for _, sp := range p {
+		t, v := sp.CameraTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
@@ -41,3 +41,45 @@ func (p Provider) SetCaption(value string) error {
 	}
 	return nil
 }
+
+// CaptionAlt returns the language alternatives of the Caption field.  They come
+// from the first provider that has any, but the default is always the value of
+// the Caption field, in case the providers disagree.
+func (p Provider) CaptionAlt() (value metadata.AltString) {
+	var def = p.Caption()
+
+	for _, sp := range p {
+		if value = sp.CaptionAlt(); !value.Empty() {
+			if def != "" && value.Default() != def {
+				value = value.SetDefault(def)
+			}
+			return value
+		}
+	}
+	if def != "" {
+		return metadata.NewAltString(def)
+	}
+	return nil
+}
+
+// SetCaptionAlt sets the language alternatives of the Caption field.  Providers
+// that don't support language alternatives are given the default.
+func (p Provider) SetCaptionAlt(value metadata.AltString) error {
+	var set = false
+
+	for _, sp := range p {
+		err := sp.SetCaptionAlt(value)
+		if err == metadata.ErrNotSupported {
+			err = sp.SetCaption(value.Default())
+		}
+		if err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
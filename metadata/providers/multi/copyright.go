@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// Copyright returns the value of the Copyright field.
+func (p Provider) Copyright() (value string) {
+	for _, sp := range p {
+		if value = sp.Copyright(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// CopyrightTags returns a list of tag names for the Copyright field, and a
+// parallel list of values held by those tags.
+func (p Provider) CopyrightTags() (tags []string, values [][]string) {
+	for _, sp := range p {
+		t, v := sp.CopyrightTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
+
+// SetCopyright sets the value of the Copyright field.
+func (p Provider) SetCopyright(value string) error {
+	var set = false
+
+	for _, sp := range p {
+		if err := sp.SetCopyright(value); err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
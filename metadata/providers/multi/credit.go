@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// Credit returns the value of the Credit field.
+func (p Provider) Credit() (value string) {
+	for _, sp := range p {
+		if value = sp.Credit(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// CreditTags returns a list of tag names for the Credit field, and a
+// parallel list of values held by those tags.
+func (p Provider) CreditTags() (tags []string, values [][]string) {
+	for _, sp := range p {
+		t, v := sp.CreditTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
+
+// SetCredit sets the value of the Credit field.
+func (p Provider) SetCredit(value string) error {
+	var set = false
+
+	for _, sp := range p {
+		if err := sp.SetCredit(value); err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
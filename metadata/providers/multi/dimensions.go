@@ -0,0 +1,24 @@
+package multi
+
+import "github.com/rothskeller/photo-tools/metadata"
+
+// Dimensions returns the value of the Dimensions field.
+func (p Provider) Dimensions() (value metadata.Dimensions) {
+	for _, sp := range p {
+		if value = sp.Dimensions(); !value.Empty() {
+			return value
+		}
+	}
+	return metadata.Dimensions{}
+}
+
+// DimensionsTags returns a list of tag names for the Dimensions field, and a
+// parallel list of values held by those tags.
+func (p Provider) DimensionsTags() (tags []string, values []metadata.Dimensions) {
+	for _, sp := range p {
+		t, v := sp.DimensionsTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
@@ -0,0 +1,22 @@
+package multi
+
+// ExposureTime returns the value of the ExposureTime field.
+func (p Provider) ExposureTime() (value float64) {
+	for _, sp := range p {
+		if value = sp.ExposureTime(); value != 0 {
+			return value
+		}
+	}
+	return 0
+}
+
+// ExposureTimeTags returns a list of tag names for the ExposureTime field, and a
+// parallel list of values held by those tags.
+func (p Provider) ExposureTimeTags() (tags []string, values []float64) {
+	for _, sp := range p {
+		t, v := sp.ExposureTimeTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
@@ -5,7 +5,7 @@ import (
 )
 
 // Faces returns the value of the Faces field.
-func (p Provider) Faces() (value []string) {
+func (p Provider) Faces() (value []metadata.FaceRegion) {
 	for _, sp := range p {
 		if value = sp.Faces(); len(value) != 0 {
 			return value
@@ -16,7 +16,7 @@ func (p Provider) Faces() (value []string) {
 
 // FacesTags returns a list of tag names for the Faces field, and a parallel
 // list of values held by those tags.
-func (p Provider) FacesTags() (tags []string, values [][]string) {
+func (p Provider) FacesTags() (tags []string, values [][]metadata.FaceRegion) {
 	for _, sp := range p {
 		t, v := sp.FacesTags()
 		tags = append(tags, t...)
@@ -26,7 +26,7 @@ func (p Provider) FacesTags() (tags []string, values [][]string) {
 }
 
 // SetFaces sets the value of the Faces field.
-func (p Provider) SetFaces(value []string) error {
+func (p Provider) SetFaces(value []metadata.FaceRegion) error {
 	var set = false
 
 	for _, sp := range p {
@@ -0,0 +1,22 @@
+package multi
+
+// FNumber returns the value of the FNumber field.
+func (p Provider) FNumber() (value float64) {
+	for _, sp := range p {
+		if value = sp.FNumber(); value != 0 {
+			return value
+		}
+	}
+	return 0
+}
+
+// FNumberTags returns a list of tag names for the FNumber field, and a parallel
+// list of values held by those tags.
+func (p Provider) FNumberTags() (tags []string, values []float64) {
+	for _, sp := range p {
+		t, v := sp.FNumberTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
@@ -0,0 +1,22 @@
+package multi
+
+// FocalLength returns the value of the FocalLength field.
+func (p Provider) FocalLength() (value float64) {
+	for _, sp := range p {
+		if value = sp.FocalLength(); value != 0 {
+			return value
+		}
+	}
+	return 0
+}
+
+// FocalLengthTags returns a list of tag names for the FocalLength field, and a
+// parallel list of values held by those tags.
+func (p Provider) FocalLengthTags() (tags []string, values []float64) {
+	for _, sp := range p {
+		t, v := sp.FocalLengthTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
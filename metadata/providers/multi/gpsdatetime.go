@@ -0,0 +1,26 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// GPSDateTime returns the value of the GPSDateTime field.
+func (p Provider) GPSDateTime() (value metadata.DateTime) {
+	for _, sp := range p {
+		if value = sp.GPSDateTime(); !value.Empty() {
+			return value
+		}
+	}
+	return metadata.DateTime{}
+}
+
+// GPSDateTimeTags returns a list of tag names for the GPSDateTime field, and a parallel
+// list of values held by those tags.
+func (p Provider) GPSDateTimeTags() (tags []string, values []metadata.DateTime) {
+	for _, sp := range p {
+		t, v := sp.GPSDateTimeTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
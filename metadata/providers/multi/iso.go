@@ -0,0 +1,22 @@
+package multi
+
+// ISO returns the value of the ISO field.
+func (p Provider) ISO() (value int) {
+	for _, sp := range p {
+		if value = sp.ISO(); value != 0 {
+			return value
+		}
+	}
+	return 0
+}
+
+// ISOTags returns a list of tag names for the ISO field, and a parallel
+// list of values held by those tags.
+func (p Provider) ISOTags() (tags []string, values []int) {
+	for _, sp := range p {
+		t, v := sp.ISOTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// Label returns the value of the Label field.
+func (p Provider) Label() (value string) {
+	for _, sp := range p {
+		if value = sp.Label(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// LabelTags returns a list of tag names for the Label field, and a parallel
+// list of values held by those tags.
+func (p Provider) LabelTags() (tags []string, values []string) {
+	for _, sp := range p {
+		t, v := sp.LabelTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
+
+// SetLabel sets the value of the Label field.
+func (p Provider) SetLabel(value string) error {
+	var set = false
+
+	for _, sp := range p {
+		if err := sp.SetLabel(value); err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package multi
+
+// Lens returns the value of the Lens field.
+func (p Provider) Lens() (value string) {
+	for _, sp := range p {
+		if value = sp.Lens(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// LensTags returns a list of tag names for the Lens field, and a parallel
+// list of values held by those tags.
+func (p Provider) LensTags() (tags []string, values []string) {
+	for _, sp := range p {
+		t, v := sp.LensTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
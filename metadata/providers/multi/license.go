@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// License returns the value of the License field.
+func (p Provider) License() (value string) {
+	for _, sp := range p {
+		if value = sp.License(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// LicenseTags returns a list of tag names for the License field, and a
+// parallel list of values held by those tags.
+func (p Provider) LicenseTags() (tags []string, values [][]string) {
+	for _, sp := range p {
+		t, v := sp.LicenseTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
+
+// SetLicense sets the value of the License field.
+func (p Provider) SetLicense(value string) error {
+	var set = false
+
+	for _, sp := range p {
+		if err := sp.SetLicense(value); err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// LocationsShown returns the value of the LocationsShown field.
+func (p Provider) LocationsShown() (value []metadata.Location) {
+	for _, sp := range p {
+		if value = sp.LocationsShown(); len(value) != 0 {
+			return value
+		}
+	}
+	return nil
+}
+
+// LocationsShownTags returns a list of tag names for the LocationsShown
+// field, and a parallel list of values held by those tags.
+func (p Provider) LocationsShownTags() (tags []string, values [][]metadata.Location) {
+	for _, sp := range p {
+		t, v := sp.LocationsShownTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
+
+// SetLocationsShown sets the value of the LocationsShown field.
+func (p Provider) SetLocationsShown(value []metadata.Location) error {
+	var set = false
+
+	for _, sp := range p {
+		if err := sp.SetLocationsShown(value); err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
@@ -7,7 +7,7 @@ import (
 // Orientation returns the value of the Orientation field.
 func (p Provider) Orientation() (value metadata.Orientation) {
 	for _, sp := range p {
-		if value = sp.Orientation(); value != metadata.Rotate0 {
+		if value = sp.Orientation(); value != 0 && value != metadata.Rotate0 {
 			return value
 		}
 	}
@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// Rating returns the value of the Rating field.
+func (p Provider) Rating() (value int) {
+	for _, sp := range p {
+		if value = sp.Rating(); value != 0 {
+			return value
+		}
+	}
+	return 0
+}
+
+// RatingTags returns a list of tag names for the Rating field, and a parallel
+// list of values held by those tags.
+func (p Provider) RatingTags() (tags []string, values []int) {
+	for _, sp := range p {
+		t, v := sp.RatingTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
+
+// SetRating sets the value of the Rating field.
+func (p Provider) SetRating(value int) error {
+	var set = false
+
+	for _, sp := range p {
+		if err := sp.SetRating(value); err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
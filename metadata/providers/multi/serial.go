@@ -0,0 +1,22 @@
+package multi
+
+// Serial returns the value of the Serial field.
+func (p Provider) Serial() (value string) {
+	for _, sp := range p {
+		if value = sp.Serial(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// SerialTags returns a list of tag names for the Serial field, and a parallel
+// list of values held by those tags.
+func (p Provider) SerialTags() (tags []string, values []string) {
+	for _, sp := range p {
+		t, v := sp.SerialTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// Source returns the value of the Source field.
+func (p Provider) Source() (value string) {
+	for _, sp := range p {
+		if value = sp.Source(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// SourceTags returns a list of tag names for the Source field, and a
+// parallel list of values held by those tags.
+func (p Provider) SourceTags() (tags []string, values [][]string) {
+	for _, sp := range p {
+		t, v := sp.SourceTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
+
+// SetSource sets the value of the Source field.
+func (p Provider) SetSource(value string) error {
+	var set = false
+
+	for _, sp := range p {
+		if err := sp.SetSource(value); err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
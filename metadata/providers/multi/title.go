@@ -41,3 +41,45 @@ func (p Provider) SetTitle(value string) error {
 	}
 	return nil
 }
+
+// TitleAlt returns the language alternatives of the Title field.  They come
+// from the first provider that has any, but the default is always the value of
+// the Title field, in case the providers disagree.
+func (p Provider) TitleAlt() (value metadata.AltString) {
+	var def = p.Title()
+
+	for _, sp := range p {
+		if value = sp.TitleAlt(); !value.Empty() {
+			if def != "" && value.Default() != def {
+				value = value.SetDefault(def)
+			}
+			return value
+		}
+	}
+	if def != "" {
+		return metadata.NewAltString(def)
+	}
+	return nil
+}
+
+// SetTitleAlt sets the language alternatives of the Title field.  Providers
+// that don't support language alternatives are given the default.
+func (p Provider) SetTitleAlt(value metadata.AltString) error {
+	var set = false
+
+	for _, sp := range p {
+		err := sp.SetTitleAlt(value)
+		if err == metadata.ErrNotSupported {
+			err = sp.SetTitle(value.Default())
+		}
+		if err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
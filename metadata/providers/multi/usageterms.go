@@ -0,0 +1,43 @@
+package multi
+
+import (
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// UsageTerms returns the value of the UsageTerms field.
+func (p Provider) UsageTerms() (value string) {
+	for _, sp := range p {
+		if value = sp.UsageTerms(); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// UsageTermsTags returns a list of tag names for the UsageTerms field, and a
+// parallel list of values held by those tags.
+func (p Provider) UsageTermsTags() (tags []string, values [][]string) {
+	for _, sp := range p {
+		t, v := sp.UsageTermsTags()
+		tags = append(tags, t...)
+		values = append(values, v...)
+	}
+	return tags, values
+}
+
+// SetUsageTerms sets the value of the UsageTerms field.
+func (p Provider) SetUsageTerms(value string) error {
+	var set = false
+
+	for _, sp := range p {
+		if err := sp.SetUsageTerms(value); err != nil && err != metadata.ErrNotSupported {
+			return err
+		} else if err == nil {
+			set = true
+		}
+	}
+	if !set {
+		return metadata.ErrNotSupported
+	}
+	return nil
+}
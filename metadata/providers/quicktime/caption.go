@@ -0,0 +1,39 @@
+package quicktime
+
+// getCaption reads the value of the Caption field from the movie.
+func (p *Provider) getCaption() (err error) {
+	p.keysDescription = p.movie.Key(keyDescription)
+	p.udtaDescription = p.movie.UserDataText(udtaDescription)
+	return nil
+}
+
+// Caption returns the value of the Caption field.
+func (p *Provider) Caption() (value string) {
+	if p.keysDescription != "" {
+		return p.keysDescription
+	}
+	return p.udtaDescription
+}
+
+// CaptionTags returns a list of tag names for the Caption field, and a
+// parallel list of values held by those tags.
+func (p *Provider) CaptionTags() (tags []string, values [][]string) {
+	tags = []string{"QT   Keys:description", "QT   ©des"}
+	values = [][]string{nil, nil}
+	if p.keysDescription != "" {
+		values[0] = []string{p.keysDescription}
+	}
+	if p.udtaDescription != "" {
+		values[1] = []string{p.udtaDescription}
+	}
+	return tags, values
+}
+
+// SetCaption sets the value of the Caption field.
+func (p *Provider) SetCaption(value string) error {
+	p.keysDescription = value
+	p.movie.SetKey(keyDescription, value)
+	p.udtaDescription = value
+	p.movie.SetUserDataText(udtaDescription, value)
+	return nil
+}
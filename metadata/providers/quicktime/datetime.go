@@ -0,0 +1,77 @@
+package quicktime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// getDateTime reads the value of the DateTime field from the movie.
+func (p *Provider) getDateTime() (err error) {
+	var s = p.movie.Key(keyCreationDate)
+
+	// Apple writes the time zone offset without a colon.
+	if len(s) > 5 && (s[len(s)-5] == '+' || s[len(s)-5] == '-') && !strings.Contains(s[len(s)-5:], ":") {
+		s = s[:len(s)-2] + ":" + s[len(s)-2:]
+	}
+	if err = p.keysCreationDate.Parse(s); err != nil {
+		return fmt.Errorf("Keys:creationdate: %s", err)
+	}
+	p.getMVHDCreationTime()
+	return nil
+}
+
+// getMVHDCreationTime reads the creation time from the movie header.  The
+// movie header time is in UTC; for easier comparison, we express it in the
+// same time zone as the creationdate key, if we have one.
+func (p *Provider) getMVHDCreationTime() {
+	var (
+		t  = p.movie.CreationTime()
+		ks = p.keysCreationDate.String()
+	)
+	switch {
+	case t.IsZero():
+		p.mvhdCreationTime = metadata.DateTime{}
+	case ks == "":
+		p.mvhdCreationTime.Parse(t.Format("2006-01-02T15:04:05Z"))
+	case strings.HasSuffix(ks, "Z") || strings.ContainsAny(ks[19:], "+-"):
+		p.mvhdCreationTime.Parse(t.In(p.keysCreationDate.AsTime().Location()).Format("2006-01-02T15:04:05-07:00"))
+	default:
+		p.mvhdCreationTime.Parse(t.In(time.Local).Format("2006-01-02T15:04:05"))
+	}
+}
+
+// DateTime returns the value of the DateTime field.
+func (p *Provider) DateTime() (value metadata.DateTime) {
+	if !p.keysCreationDate.Empty() {
+		return p.keysCreationDate
+	}
+	return p.mvhdCreationTime
+}
+
+// DateTimeTags returns a list of tag names for the DateTime field, and
+// a parallel list of values held by those tags.
+func (p *Provider) DateTimeTags() (tags []string, values []metadata.DateTime) {
+	return []string{"QT   Keys:creationdate", "QT   mvhd:CreationTime"},
+		[]metadata.DateTime{p.keysCreationDate, p.mvhdCreationTime}
+}
+
+// SetDateTime sets the value of the DateTime field.
+func (p *Provider) SetDateTime(value metadata.DateTime) error {
+	if value.Empty() {
+		p.keysCreationDate = metadata.DateTime{}
+		p.movie.SetKey(keyCreationDate, "")
+		p.mvhdCreationTime = metadata.DateTime{}
+		p.movie.SetCreationTime(time.Time{})
+		return nil
+	}
+	if !value.Equivalent(p.keysCreationDate) {
+		p.keysCreationDate = value
+		p.movie.SetKey(keyCreationDate, value.String())
+	}
+	p.movie.SetCreationTime(value.AsTime().Truncate(time.Second))
+	p.getMVHDCreationTime()
+	return nil
+}
@@ -0,0 +1,52 @@
+package quicktime
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// getGPS reads the value of the GPS field from the movie.
+func (p *Provider) getGPS() (err error) {
+	if err = p.keysLocation.ParseISO6709(p.movie.Key(keyLocation)); err != nil {
+		return fmt.Errorf("Keys:location.ISO6709: %s", err)
+	}
+	if err = p.udtaLocation.ParseISO6709(p.movie.UserDataText(udtaLocation)); err != nil {
+		return fmt.Errorf("©xyz: %s", err)
+	}
+	return nil
+}
+
+// GPS returns the value of the GPS field.
+func (p *Provider) GPS() (value metadata.GPSCoords) {
+	if !p.keysLocation.Empty() {
+		return p.keysLocation
+	}
+	return p.udtaLocation
+}
+
+// GPSTags returns a list of tag names for the GPS field, and a parallel list of
+// values held by those tags.
+func (p *Provider) GPSTags() (tags []string, values []metadata.GPSCoords) {
+	return []string{"QT   Keys:location.ISO6709", "QT   ©xyz"}, []metadata.GPSCoords{p.keysLocation, p.udtaLocation}
+}
+
+// SetGPS sets the value of the GPS field.
+func (p *Provider) SetGPS(value metadata.GPSCoords) error {
+	if value.Empty() {
+		p.keysLocation = metadata.GPSCoords{}
+		p.movie.SetKey(keyLocation, "")
+		p.udtaLocation = metadata.GPSCoords{}
+		p.movie.SetUserDataText(udtaLocation, "")
+		return nil
+	}
+	if !value.Equivalent(p.keysLocation) {
+		p.keysLocation = value
+		p.movie.SetKey(keyLocation, value.AsISO6709())
+	}
+	if !value.Equivalent(p.udtaLocation) {
+		p.udtaLocation = value
+		p.movie.SetUserDataText(udtaLocation, value.AsISO6709())
+	}
+	return nil
+}
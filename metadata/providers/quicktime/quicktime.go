@@ -0,0 +1,59 @@
+// Package quicktime handles the native metadata of QuickTime and MP4 movie
+// files: the movie header, the QuickTime user data items, and the QuickTime
+// metadata keys.
+package quicktime
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/isobmff"
+)
+
+const (
+	keyCreationDate = "com.apple.quicktime.creationdate"
+	keyDescription  = "com.apple.quicktime.description"
+	keyLocation     = "com.apple.quicktime.location.ISO6709"
+	keyTitle        = "com.apple.quicktime.title"
+	udtaDescription = "\xA9des"
+	udtaLocation    = "\xA9xyz"
+	udtaName        = "\xA9nam"
+)
+
+// A Provider handles the native metadata in a movie file.
+type Provider struct {
+	metadata.BaseProvider
+	keysCreationDate metadata.DateTime
+	keysDescription  string
+	keysLocation     metadata.GPSCoords
+	keysTitle        string
+	mvhdCreationTime metadata.DateTime
+	udtaDescription  string
+	udtaLocation     metadata.GPSCoords
+	udtaName         string
+
+	movie *isobmff.ISOBMFF
+}
+
+var _ metadata.Provider = (*Provider)(nil) // verify interface compliance
+
+// New creates a new Provider based on the provided movie file container.
+func New(movie *isobmff.ISOBMFF) (p *Provider, err error) {
+	p = &Provider{movie: movie}
+	if err = p.getCaption(); err != nil {
+		return nil, fmt.Errorf("QuickTime: %s", err)
+	}
+	if err = p.getDateTime(); err != nil {
+		return nil, fmt.Errorf("QuickTime: %s", err)
+	}
+	if err = p.getGPS(); err != nil {
+		return nil, fmt.Errorf("QuickTime: %s", err)
+	}
+	if err = p.getTitle(); err != nil {
+		return nil, fmt.Errorf("QuickTime: %s", err)
+	}
+	return p, nil
+}
+
+// ProviderName is the name for the provider, for debug purposes.
+func (p *Provider) ProviderName() string { return "QuickTime" }
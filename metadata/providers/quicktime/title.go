@@ -0,0 +1,39 @@
+package quicktime
+
+// getTitle reads the value of the Title field from the movie.
+func (p *Provider) getTitle() (err error) {
+	p.keysTitle = p.movie.Key(keyTitle)
+	p.udtaName = p.movie.UserDataText(udtaName)
+	return nil
+}
+
+// Title returns the value of the Title field.
+func (p *Provider) Title() (value string) {
+	if p.keysTitle != "" {
+		return p.keysTitle
+	}
+	return p.udtaName
+}
+
+// TitleTags returns a list of tag names for the Title field, and a
+// parallel list of values held by those tags.
+func (p *Provider) TitleTags() (tags []string, values [][]string) {
+	tags = []string{"QT   Keys:title", "QT   ©nam"}
+	values = [][]string{nil, nil}
+	if p.keysTitle != "" {
+		values[0] = []string{p.keysTitle}
+	}
+	if p.udtaName != "" {
+		values[1] = []string{p.udtaName}
+	}
+	return tags, values
+}
+
+// SetTitle sets the values of the Title field.
+func (p *Provider) SetTitle(value string) error {
+	p.keysTitle = value
+	p.movie.SetKey(keyTitle, value)
+	p.udtaName = value
+	p.movie.SetUserDataText(udtaName, value)
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package readonly contains a metadata.Provider that presents the metadata of
+// an underlying provider, but refuses to change them.
+package readonly
+
+import "github.com/rothskeller/photo-tools/metadata"
+
+// Provider presents the metadata of an underlying provider, but returns
+// metadata.ErrNotSupported from all of its Set functions.
+type Provider struct {
+	metadata.Provider
+}
+
+var _ metadata.Provider = Provider{} // verify interface compliance
+
+// New creates a new Provider wrapping the specified provider.
+func New(p metadata.Provider) Provider { return Provider{p} }
+
+// ProviderName is the name for the provider, for debug purposes.
+func (p Provider) ProviderName() string { return p.Provider.ProviderName() + " (read-only)" }
+
+// SetCaption is not supported.
+func (p Provider) SetCaption(value string) error { return metadata.ErrNotSupported }
+
+// SetCaptionAlt is not supported.
+func (p Provider) SetCaptionAlt(value metadata.AltString) error { return metadata.ErrNotSupported }
+
+// SetCopyright is not supported.
+func (p Provider) SetCopyright(value string) error { return metadata.ErrNotSupported }
+
+// SetCreator is not supported.
+func (p Provider) SetCreator(value string) error { return metadata.ErrNotSupported }
+
+// SetCredit is not supported.
+func (p Provider) SetCredit(value string) error { return metadata.ErrNotSupported }
+
+// SetDateTime is not supported.
+func (p Provider) SetDateTime(value metadata.DateTime) error { return metadata.ErrNotSupported }
+
+// SetFaces is not supported.
+func (p Provider) SetFaces(values []metadata.FaceRegion) error { return metadata.ErrNotSupported }
+
+// SetGPS is not supported.
+func (p Provider) SetGPS(value metadata.GPSCoords) error { return metadata.ErrNotSupported }
+
+// SetGroups is not supported.
+func (p Provider) SetGroups(values []metadata.HierValue) error { return metadata.ErrNotSupported }
+
+// SetKeywords is not supported.
+func (p Provider) SetKeywords(values []metadata.HierValue) error { return metadata.ErrNotSupported }
+
+// SetLabel is not supported.
+func (p Provider) SetLabel(value string) error { return metadata.ErrNotSupported }
+
+// SetLicense is not supported.
+func (p Provider) SetLicense(value string) error { return metadata.ErrNotSupported }
+
+// SetLocation is not supported.
+func (p Provider) SetLocation(value metadata.Location) error { return metadata.ErrNotSupported }
+
+// SetLocationsShown is not supported.
+func (p Provider) SetLocationsShown(values []metadata.Location) error {
+	return metadata.ErrNotSupported
+}
+
+// SetOrientation is not supported.
+func (p Provider) SetOrientation(value metadata.Orientation) error { return metadata.ErrNotSupported }
+
+// SetPeople is not supported.
+func (p Provider) SetPeople(values []string) error { return metadata.ErrNotSupported }
+
+// SetPlaces is not supported.
+func (p Provider) SetPlaces(values []metadata.HierValue) error { return metadata.ErrNotSupported }
+
+// SetRating is not supported.
+func (p Provider) SetRating(value int) error { return metadata.ErrNotSupported }
+
+// SetSource is not supported.
+func (p Provider) SetSource(value string) error { return metadata.ErrNotSupported }
+
+// SetTitle is not supported.
+func (p Provider) SetTitle(value string) error { return metadata.ErrNotSupported }
+
+// SetTitleAlt is not supported.
+func (p Provider) SetTitleAlt(value metadata.AltString) error { return metadata.ErrNotSupported }
+
+// SetTopics is not supported.
+func (p Provider) SetTopics(values []metadata.HierValue) error { return metadata.ErrNotSupported }
+
+// SetUsageTerms is not supported.
+func (p Provider) SetUsageTerms(value string) error { return metadata.ErrNotSupported }
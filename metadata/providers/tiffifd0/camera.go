@@ -0,0 +1,44 @@
+package tiffifd0
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	tagMake  uint16 = 0x10F
+	tagModel uint16 = 0x110
+)
+
+// getCamera reads the value of the Camera field from the IFD.
+func (p *Provider) getCamera() (err error) {
+	var maker, model string
+
+	if tag := p.ifd.Tag(tagMake); tag != nil {
+		if maker, err = tag.AsString(); err != nil {
+			return fmt.Errorf("Make: %s", err)
+		}
+	}
+	if tag := p.ifd.Tag(tagModel); tag != nil {
+		if model, err = tag.AsString(); err != nil {
+			return fmt.Errorf("Model: %s", err)
+		}
+	}
+	// Many cameras repeat the make in the model name; we don't want it
+	// twice.
+	if maker == "" || strings.HasPrefix(strings.ToLower(model), strings.ToLower(strings.Fields(maker)[0])) {
+		p.camera = model
+	} else {
+		p.camera = strings.TrimSpace(maker + " " + model)
+	}
+	return nil
+}
+
+// Camera returns the value of the Camera field.
+func (p *Provider) Camera() (value string) { return p.camera }
+
+// CameraTags returns a list of tag names for the Camera field, and a
+// parallel list of values held by those tags.
+func (p *Provider) CameraTags() (tags []string, values []string) {
+	return []string{"IFD0 Model*"}, []string{p.camera}
+}
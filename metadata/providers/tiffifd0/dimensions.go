@@ -0,0 +1,47 @@
+package tiffifd0
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+const (
+	tagNewSubfileType uint16 = 0xFE
+	tagImageWidth     uint16 = 0x100
+	tagImageLength    uint16 = 0x101
+)
+
+// getDimensions reads the value of the Dimensions field from the IFD.
+func (p *Provider) getDimensions() (err error) {
+	var w, h uint32
+
+	// In many raw files, IFD0 contains a reduced-resolution thumbnail
+	// rather than the main image.  Its dimensions are not interesting.
+	if tag := p.ifd.Tag(tagNewSubfileType); tag != nil {
+		if subfileType, err := tag.AsUnsigned(); err == nil && subfileType&1 != 0 {
+			return nil
+		}
+	}
+	wtag, htag := p.ifd.Tag(tagImageWidth), p.ifd.Tag(tagImageLength)
+	if wtag == nil || htag == nil {
+		return nil
+	}
+	if w, err = wtag.AsUnsigned(); err != nil {
+		return fmt.Errorf("ImageWidth: %s", err)
+	}
+	if h, err = htag.AsUnsigned(); err != nil {
+		return fmt.Errorf("ImageLength: %s", err)
+	}
+	p.dimensions = metadata.Dimensions{Width: int(w), Height: int(h)}
+	return nil
+}
+
+// Dimensions returns the value of the Dimensions field.
+func (p *Provider) Dimensions() (value metadata.Dimensions) { return p.dimensions }
+
+// DimensionsTags returns a list of tag names for the Dimensions field, and a
+// parallel list of values held by those tags.
+func (p *Provider) DimensionsTags() (tags []string, values []metadata.Dimensions) {
+	return []string{"IFD0 ImageWidth/Length"}, []metadata.Dimensions{p.dimensions}
+}
@@ -10,10 +10,15 @@ import (
 // A Provider handles metadata in the IFD0 of a TIFF file.
 type Provider struct {
 	metadata.BaseProvider
+	camera           string
+	copyright        string
 	artist           string
 	dateTime         metadata.DateTime
+	dimensions       metadata.Dimensions
 	orientation      metadata.Orientation
 	imageDescription string
+	rating           int
+	ratingPercent    int
 
 	ifd *tiff.IFD
 }
@@ -23,18 +28,30 @@ var _ metadata.Provider = (*Provider)(nil) // verify interface compliance
 // New creates a new Provider based on the provided IFD.
 func New(ifd *tiff.IFD) (p *Provider, err error) {
 	p = &Provider{ifd: ifd}
+	if err = p.getCamera(); err != nil {
+		return nil, fmt.Errorf("JPEG IFD0: %s", err)
+	}
 	if err = p.getCaption(); err != nil {
 		return nil, fmt.Errorf("JPEG IFD0: %s", err)
 	}
+	if err = p.getCopyright(); err != nil {
+		return nil, fmt.Errorf("JPEG IFD0: %s", err)
+	}
 	if err = p.getCreator(); err != nil {
 		return nil, fmt.Errorf("JPEG IFD0: %s", err)
 	}
 	if err = p.getDateTime(); err != nil {
 		return nil, fmt.Errorf("JPEG IFD0: %s", err)
 	}
+	if err = p.getDimensions(); err != nil {
+		return nil, fmt.Errorf("JPEG IFD0: %s", err)
+	}
 	if err = p.getOrientation(); err != nil {
 		return nil, fmt.Errorf("JPEG IFD0: %s", err)
 	}
+	if err = p.getRating(); err != nil {
+		return nil, fmt.Errorf("JPEG IFD0: %s", err)
+	}
 	return p, nil
 }
 
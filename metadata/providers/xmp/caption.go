@@ -3,6 +3,7 @@ package xmp
 import (
 	"fmt"
 
+	"github.com/rothskeller/photo-tools/metadata"
 	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
 )
 
@@ -38,56 +39,57 @@ func (p *Provider) Caption() (value string) {
 }
 
 // CaptionTags returns a list of tag names for the Caption field, and a
-// parallel list of values held by those tags.
+// parallel list of values held by those tags.  Only the default language
+// alternatives are listed; see CaptionAlt for the others.
 func (p *Provider) CaptionTags() (tags []string, values [][]string) {
-	tags = append(tags, "XMP  dc:description")
-	if len(p.dcDescription) == 0 {
-		values = append(values, nil)
-	} else {
-		vlist := make([]string, len(p.dcDescription))
-		for i := range p.dcDescription {
-			vlist[i] = p.dcDescription[i].Value
-		}
-		values = append(values, vlist)
-	}
-	tags = append(tags, "XMP  tiff:ImageDescription")
-	if len(p.tiffImageDescription) == 0 {
-		values = append(values, nil)
-	} else {
-		vlist := make([]string, len(p.tiffImageDescription))
-		for i := range p.tiffImageDescription {
-			vlist[i] = p.tiffImageDescription[i].Value
-		}
-		values = append(values, vlist)
-	}
+	tags = append(tags, "XMP  dc:description", "XMP  tiff:ImageDescription")
+	values = append(values, defaultOnly(p.dcDescription), defaultOnly(p.tiffImageDescription))
 	if len(p.exifUserComment) != 0 {
-		vlist := make([]string, len(p.exifUserComment))
-		for i, as := range p.exifUserComment {
-			vlist[i] = as.Value
-		}
 		tags = append(tags, "XMP  exif:UserComment")
-		values = append(values, vlist)
+		values = append(values, defaultOnly(p.exifUserComment))
 	}
 	return tags, values
 }
 
-// SetCaption sets the value of the Caption field.
+// SetCaption sets the value of the Caption field.  Language alternatives other
+// than the default are retained.
 func (p *Provider) SetCaption(value string) error {
+	return p.setCaption(p.dcDescription.SetDefault(value), p.tiffImageDescription.SetDefault(value))
+}
+
+// CaptionAlt returns the language alternatives of the Caption field.
+func (p *Provider) CaptionAlt() (value metadata.AltString) {
+	if !p.dcDescription.Empty() {
+		return p.dcDescription
+	}
+	if !p.exifUserComment.Empty() {
+		return p.exifUserComment
+	}
+	return p.tiffImageDescription
+}
+
+// SetCaptionAlt sets the language alternatives of the Caption field.
+func (p *Provider) SetCaptionAlt(value metadata.AltString) error {
+	return p.setCaption(value, value)
+}
+
+// setCaption sets dc:description and tiff:ImageDescription to the specified
+// values, and removes exif:UserComment.
+func (p *Provider) setCaption(description, imageDescription altString) error {
 	p.exifUserComment = nil
 	p.rdf.RemoveProperty(userCommentName)
-	if value == "" {
+	if description.Empty() {
 		p.dcDescription = nil
 		p.rdf.RemoveProperty(descriptionName)
-		p.tiffImageDescription = nil
-		p.rdf.RemoveProperty(imageDescriptionName)
-		return nil
-	}
-	if len(p.dcDescription) != 1 || value != p.dcDescription[0].Value {
-		p.dcDescription = newAltString(value)
+	} else if !description.Equal(p.dcDescription) {
+		p.dcDescription = description
 		p.rdf.SetProperty(descriptionName, makeAlt(p.dcDescription))
 	}
-	if len(p.tiffImageDescription) != 1 || value != p.tiffImageDescription[0].Value {
-		p.tiffImageDescription = newAltString(value)
+	if imageDescription.Empty() {
+		p.tiffImageDescription = nil
+		p.rdf.RemoveProperty(imageDescriptionName)
+	} else if !imageDescription.Equal(p.tiffImageDescription) {
+		p.tiffImageDescription = imageDescription
 		p.rdf.SetProperty(imageDescriptionName, makeAlt(p.tiffImageDescription))
 	}
 	return nil
@@ -0,0 +1,44 @@
+package xmp
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+)
+
+var rightsName = rdf.Name{Namespace: nsDC, Name: "rights"}
+
+// getCopyright reads the value of the Copyright field from the RDF.
+func (p *Provider) getCopyright() (err error) {
+	if p.dcRights, err = getAlt(p.rdf.Property(rightsName)); err != nil {
+		return fmt.Errorf("dc:rights: %s", err)
+	}
+	return nil
+}
+
+// Copyright returns the value of the Copyright field.
+func (p *Provider) Copyright() (value string) { return p.dcRights.Default() }
+
+// CopyrightTags returns a list of tag names for the Copyright field, and a
+// parallel list of values held by those tags.  Only the default language
+// alternative is listed.
+func (p *Provider) CopyrightTags() (tags []string, values [][]string) {
+	return []string{"XMP  dc:rights"}, [][]string{defaultOnly(p.dcRights)}
+}
+
+// SetCopyright sets the value of the Copyright field.  Language alternatives
+// other than the default are retained.
+func (p *Provider) SetCopyright(value string) error {
+	var rights = p.dcRights.SetDefault(value)
+	if rights.Empty() {
+		p.dcRights = nil
+		p.rdf.RemoveProperty(rightsName)
+		return nil
+	}
+	if rights.Equal(p.dcRights) {
+		return nil
+	}
+	p.dcRights = rights
+	p.rdf.SetProperty(rightsName, makeAlt(p.dcRights))
+	return nil
+}
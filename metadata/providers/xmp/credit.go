@@ -0,0 +1,44 @@
+package xmp
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+)
+
+var creditName = rdf.Name{Namespace: nsPS, Name: "Credit"}
+
+// getCredit reads the value of the Credit field from the RDF.
+func (p *Provider) getCredit() (err error) {
+	if p.psCredit, err = getString(p.rdf.Property(creditName)); err != nil {
+		return fmt.Errorf("photoshop:Credit: %s", err)
+	}
+	return nil
+}
+
+// Credit returns the value of the Credit field.
+func (p *Provider) Credit() (value string) { return p.psCredit }
+
+// CreditTags returns a list of tag names for the Credit field, and a
+// parallel list of values held by those tags.
+func (p *Provider) CreditTags() (tags []string, values [][]string) {
+	if p.psCredit == "" {
+		return []string{"XMP  photoshop:Credit"}, [][]string{nil}
+	}
+	return []string{"XMP  photoshop:Credit"}, [][]string{{p.psCredit}}
+}
+
+// SetCredit sets the value of the Credit field.
+func (p *Provider) SetCredit(value string) error {
+	if value == "" {
+		p.psCredit = ""
+		p.rdf.RemoveProperty(creditName)
+		return nil
+	}
+	if value == p.psCredit {
+		return nil
+	}
+	p.psCredit = value
+	p.rdf.SetProperty(creditName, makeString(value))
+	return nil
+}
@@ -2,20 +2,35 @@ package xmp
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/rothskeller/photo-tools/metadata"
 	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
 )
 
 var (
-	mpRegionInfoName           = rdf.Name{Namespace: nsMP, Name: "RegionInfo"}
-	mpriRegionsName            = rdf.Name{Namespace: nsMPRI, Name: "Regions"}
-	mpRegRectangleName         = rdf.Name{Namespace: nsMPReg, Name: "Rectangle"}
-	mpRegPersonDisplayNameName = rdf.Name{Namespace: nsMPReg, Name: "PersonDisplayName"}
-	mwgrsRegionsName           = rdf.Name{Namespace: nsMWGRS, Name: "Regions"}
-	mwgrsRegionListName        = rdf.Name{Namespace: nsMWGRS, Name: "RegionList"}
-	mwgrsTypeName              = rdf.Name{Namespace: nsMWGRS, Name: "Type"}
-	mwgrsNameName              = rdf.Name{Namespace: nsMWGRS, Name: "Name"}
+	mpRegionInfoName             = rdf.Name{Namespace: nsMP, Name: "RegionInfo"}
+	mpriRegionsName              = rdf.Name{Namespace: nsMPRI, Name: "Regions"}
+	mpRegRectangleName           = rdf.Name{Namespace: nsMPReg, Name: "Rectangle"}
+	mpRegPersonDisplayNameName   = rdf.Name{Namespace: nsMPReg, Name: "PersonDisplayName"}
+	mwgrsRegionsName             = rdf.Name{Namespace: nsMWGRS, Name: "Regions"}
+	mwgrsAppliedToDimensionsName = rdf.Name{Namespace: nsMWGRS, Name: "AppliedToDimensions"}
+	mwgrsRegionListName          = rdf.Name{Namespace: nsMWGRS, Name: "RegionList"}
+	mwgrsAreaName                = rdf.Name{Namespace: nsMWGRS, Name: "Area"}
+	mwgrsTypeName                = rdf.Name{Namespace: nsMWGRS, Name: "Type"}
+	mwgrsNameName                = rdf.Name{Namespace: nsMWGRS, Name: "Name"}
+	stAreaXName                  = rdf.Name{Namespace: nsStArea, Name: "x"}
+	stAreaYName                  = rdf.Name{Namespace: nsStArea, Name: "y"}
+	stAreaWName                  = rdf.Name{Namespace: nsStArea, Name: "w"}
+	stAreaHName                  = rdf.Name{Namespace: nsStArea, Name: "h"}
+	stAreaUnitName               = rdf.Name{Namespace: nsStArea, Name: "unit"}
+	stDimWName                   = rdf.Name{Namespace: nsStDim, Name: "w"}
+	stDimHName                   = rdf.Name{Namespace: nsStDim, Name: "h"}
+	stDimUnitName                = rdf.Name{Namespace: nsStDim, Name: "unit"}
 )
 
 // getFaces reads the value of the Faces field from the RDF.
@@ -42,19 +57,24 @@ func (p *Provider) getFacesMP() (err error) {
 	if !ok {
 		return errors.New("MPRI:Regions: wrong data type")
 	}
-	p.mpRegPersonDisplayNames = make([]string, 0, len(bag))
+	p.mpRegions = make([]metadata.FaceRegion, 0, len(bag))
 	for _, reg := range bag {
 		region, ok := reg.Value.(rdf.Struct)
 		if !ok {
 			return errors.New("MPRI:Regions element: wrong data type")
 		}
-		if _, ok := region[mpRegRectangleName]; !ok {
+		rectangle, ok := region[mpRegRectangleName]
+		if !ok {
 			// digiKam tends to create region entries for anyone who has a face
 			// in *any* photo, but omits the rectangle if they don't have a face
 			// in *this* photo.  For our purposes, if there's no rectangle, it
 			// doesn't count.
 			continue
 		}
+		rectString, ok := rectangle.Value.(string)
+		if !ok {
+			return errors.New("MPReg:Rectangle: wrong data type")
+		}
 		personDisplayName, ok := region[mpRegPersonDisplayNameName]
 		if !ok {
 			continue
@@ -63,7 +83,7 @@ func (p *Provider) getFacesMP() (err error) {
 		if !ok {
 			return errors.New("MPReg:PersonDisplayName: wrong data type")
 		}
-		p.mpRegPersonDisplayNames = append(p.mpRegPersonDisplayNames, name)
+		p.mpRegions = append(p.mpRegions, parseMPRectangle(name, rectString))
 	}
 	return nil
 }
@@ -76,7 +96,15 @@ func (p *Provider) getFacesMWGRS() (err error) {
 	if !ok {
 		return errors.New("mwg-rs:Regions: wrong data type")
 	}
-	regionList, ok := regionsStruct[rdf.Name{Namespace: nsMWGRS, Name: "RegionList"}]
+	var appliedW, appliedH int
+	if dims, ok := regionsStruct[mwgrsAppliedToDimensionsName]; ok {
+		dimsStruct, ok := dims.Value.(rdf.Struct)
+		if !ok {
+			return errors.New("mwg-rs:AppliedToDimensions: wrong data type")
+		}
+		appliedW, appliedH = int(structFloat(dimsStruct, stDimWName)), int(structFloat(dimsStruct, stDimHName))
+	}
+	regionList, ok := regionsStruct[mwgrsRegionListName]
 	if !ok {
 		return nil
 	}
@@ -93,13 +121,13 @@ func (p *Provider) getFacesMWGRS() (err error) {
 			return errors.New("mwg-rs:RegionList: wrong data type")
 		}
 	}
-	p.mwgrsNames = make([]string, 0, len(items))
+	p.mwgrsRegions = make([]metadata.FaceRegion, 0, len(items))
 	for _, reg := range items {
 		region, ok := reg.Value.(rdf.Struct)
 		if !ok {
 			return errors.New("mwg-rs:RegionList element: wrong data type")
 		}
-		typ, ok := region[rdf.Name{Namespace: nsMWGRS, Name: "Type"}]
+		typ, ok := region[mwgrsTypeName]
 		if !ok {
 			continue
 		}
@@ -110,7 +138,7 @@ func (p *Provider) getFacesMWGRS() (err error) {
 		if typString != "Face" {
 			continue
 		}
-		name, ok := region[rdf.Name{Namespace: nsMWGRS, Name: "Name"}]
+		name, ok := region[mwgrsNameName]
 		if !ok {
 			continue
 		}
@@ -118,81 +146,172 @@ func (p *Provider) getFacesMWGRS() (err error) {
 		if !ok {
 			return errors.New("mwg-rs:Name: wrong data type")
 		}
-		p.mwgrsNames = append(p.mwgrsNames, nameString)
+		if area, ok := region[mwgrsAreaName]; ok {
+			if _, ok := area.Value.(rdf.Struct); !ok {
+				return errors.New("mwg-rs:Area: wrong data type")
+			}
+		}
+		p.mwgrsRegions = append(p.mwgrsRegions, makeMWGRSFace(nameString, region, appliedW, appliedH))
 	}
 	return nil
 }
 
-// Faces returns the values of the Faces field.
-func (p *Provider) Faces() (values []string) {
-	if len(p.mpRegPersonDisplayNames) == 0 && len(p.mwgrsNames) == 0 {
-		return nil
+// parseMPRectangle returns a face region with the specified name and the area
+// described by an MPReg:Rectangle string.  That string contains the
+// normalized coordinates of the top left corner of the region, and its width
+// and height, separated by commas.  If the string can't be parsed, the region
+// has no area.
+func parseMPRectangle(name, rect string) (face metadata.FaceRegion) {
+	var coords [4]float64
+	var err error
+
+	face.Name = name
+	parts := strings.Split(rect, ",")
+	if len(parts) != 4 {
+		return face
 	}
-	var facemap = make(map[string]bool)
-	for _, face := range p.mpRegPersonDisplayNames {
-		facemap[face] = true
+	for i, part := range parts {
+		if coords[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64); err != nil {
+			return face
+		}
 	}
-	for _, face := range p.mwgrsNames {
-		facemap[face] = true
+	face.X, face.Y = coords[0]+coords[2]/2, coords[1]+coords[3]/2
+	face.W, face.H = coords[2], coords[3]
+	face.Unit = "normalized"
+	return face
+}
+
+// makeMWGRSFace returns a face region with the specified name and the area
+// described by the mwg-rs:Area field of the specified region structure (if
+// any).
+func makeMWGRSFace(name string, region rdf.Struct, appliedW, appliedH int) (face metadata.FaceRegion) {
+	face = metadata.FaceRegion{Name: name, AppliedToW: appliedW, AppliedToH: appliedH}
+	if area, ok := region[mwgrsAreaName].Value.(rdf.Struct); ok {
+		face.X, face.Y = structFloat(area, stAreaXName), structFloat(area, stAreaYName)
+		face.W, face.H = structFloat(area, stAreaWName), structFloat(area, stAreaHName)
+		face.Unit, _ = area[stAreaUnitName].Value.(string)
 	}
-	values = make([]string, 0, len(facemap))
-	for face := range facemap {
-		values = append(values, face)
+	return face
+}
+
+// makeMPRectangle returns the MPReg:Rectangle string describing the area of
+// a face region, which must be in normalized units.
+func makeMPRectangle(face metadata.FaceRegion) string {
+	return fmt.Sprintf("%s, %s, %s, %s", formatFloat(face.X-face.W/2), formatFloat(face.Y-face.H/2),
+		formatFloat(face.W), formatFloat(face.H))
+}
+
+// structFloat returns the value of a numeric structure field, or zero if it is
+// missing or invalid.
+func structFloat(s rdf.Struct, name rdf.Name) float64 {
+	str, _ := s[name].Value.(string)
+	f, _ := strconv.ParseFloat(str, 64)
+	return f
+}
+
+// formatFloat formats a coordinate for storage in the RDF.  Six decimal places
+// are far more than enough for any image.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e6)/1e6, 'f', -1, 64)
+}
+
+// Faces returns the values of the Faces field.
+func (p *Provider) Faces() (values []metadata.FaceRegion) {
+	values = append(values, p.mwgrsRegions...)
+	for _, face := range p.mpRegions {
+		if findFace(values, face) < 0 {
+			values = append(values, face)
+		}
 	}
-	sort.Strings(values)
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Name < values[j].Name })
 	return values
 }
 
 // FacesTags returns a list of tag names for the Faces field, and a
 // parallel list of values held by those tags.
-func (p *Provider) FacesTags() (tags []string, values [][]string) {
-	if len(p.mpRegPersonDisplayNames) != 0 {
+func (p *Provider) FacesTags() (tags []string, values [][]metadata.FaceRegion) {
+	if len(p.mpRegions) != 0 {
 		tags = append(tags, "XMP  MP:Regions")
-		values = append(values, p.mpRegPersonDisplayNames)
+		values = append(values, p.mpRegions)
 	}
-	if len(p.mwgrsNames) != 0 {
+	if len(p.mwgrsRegions) != 0 {
 		tags = append(tags, "XMP  mwg-rs:RegionInfo")
-		values = append(values, p.mwgrsNames)
+		values = append(values, p.mwgrsRegions)
 	}
 	return tags, values
 }
 
-// SetFaces sets the values of the Faces field.
-func (p *Provider) SetFaces(values []string) error {
+// SetFaces sets the values of the Faces field.  Existing face regions that
+// are equivalent to one of the values are retained; others are removed.
+// Values that don't have an equivalent region are added to each region
+// schema, which requires that they have an area.
+func (p *Provider) SetFaces(values []metadata.FaceRegion) error {
+	var mpSeen = make([]bool, len(values))
+	var mwgrsSeen = make([]bool, len(values))
+
+	p.setFacesMP(values, mpSeen)
+	p.setFacesMWGRS(values, mwgrsSeen)
+	for i := range values {
+		if !mpSeen[i] && !mwgrsSeen[i] && !values[i].HasArea() {
+			return errors.New("cannot add face regions without coordinates")
+		}
+	}
+	return nil
+}
+func (p *Provider) setFacesMP(values []metadata.FaceRegion, seen []bool) {
 	var (
-		regions   rdf.Struct
+		regions   = rdf.Struct{}
 		bag       rdf.Bag
 		nextInBag int
-		vmap      = make(map[string]bool)
+		changed   bool
 	)
-	for _, value := range values {
-		vmap[value] = false
-	}
 	if val := p.rdf.Property(mpRegionInfoName); val.Value != nil {
 		regions = val.Value.(rdf.Struct)
 		if val, ok := regions[mpriRegionsName]; ok {
 			bag = val.Value.(rdf.Bag)
 		}
 	}
-	p.mpRegPersonDisplayNames = nil
+	p.mpRegions = nil
 	for _, oldv := range bag {
-		if _, ok := oldv.Value.(rdf.Struct)[mpRegRectangleName]; !ok {
+		if r, ok := oldv.Value.(rdf.Struct)[mpRegRectangleName]; !ok {
 			bag[nextInBag] = oldv
 			nextInBag++
 		} else if n, ok := oldv.Value.(rdf.Struct)[mpRegPersonDisplayNameName]; !ok {
 			bag[nextInBag] = oldv
 			nextInBag++
-		} else if _, ok := vmap[n.Value.(string)]; ok {
+		} else if face := parseMPRectangle(n.Value.(string), r.Value.(string)); keepFace(values, seen, face) {
 			bag[nextInBag] = oldv
-			vmap[n.Value.(string)] = true
-			p.mpRegPersonDisplayNames = append(p.mpRegPersonDisplayNames, n.Value.(string))
+			nextInBag++
+			p.mpRegions = append(p.mpRegions, face)
 		}
 	}
 	if nextInBag != len(bag) {
-		regions[mpriRegionsName] = rdf.Value{Value: bag[:nextInBag]}
+		bag, changed = bag[:nextInBag], true
+	}
+	for i, face := range values {
+		if face = face.Normalized(); seen[i] || !face.HasArea() || face.Unit != "normalized" {
+			continue
+		}
+		bag = append(bag, rdf.Value{Value: rdf.Struct{
+			mpRegRectangleName:         rdf.Value{Value: makeMPRectangle(face)},
+			mpRegPersonDisplayNameName: rdf.Value{Value: face.Name},
+		}})
+		seen[i], changed = true, true
+		p.mpRegions = append(p.mpRegions, face)
+	}
+	if changed {
+		regions[mpriRegionsName] = rdf.Value{Value: bag}
 		p.rdf.SetProperty(mpRegionInfoName, rdf.Value{Value: regions})
 	}
-	bag, nextInBag = nil, 0
+}
+func (p *Provider) setFacesMWGRS(values []metadata.FaceRegion, seen []bool) {
+	var (
+		regions            = rdf.Struct{}
+		bag                rdf.Bag
+		nextInBag          int
+		changed            bool
+		appliedW, appliedH int
+	)
 	if val := p.rdf.Property(mwgrsRegionsName); val.Value != nil {
 		regions = val.Value.(rdf.Struct)
 		if val, ok := regions[mwgrsRegionListName]; ok {
@@ -201,29 +320,87 @@ func (p *Provider) SetFaces(values []string) error {
 				bag = rdf.Bag(val.Value.(rdf.Seq))
 			}
 		}
+		if val, ok := regions[mwgrsAppliedToDimensionsName]; ok {
+			dims := val.Value.(rdf.Struct)
+			appliedW, appliedH = int(structFloat(dims, stDimWName)), int(structFloat(dims, stDimHName))
+		}
 	}
-	p.mwgrsNames = nil
+	p.mwgrsRegions = nil
 	for _, oldv := range bag {
-		if t, ok := oldv.Value.(rdf.Struct)[mwgrsTypeName]; !ok || t.Value.(string) != "Face" {
+		region := oldv.Value.(rdf.Struct)
+		if t, ok := region[mwgrsTypeName]; !ok || t.Value.(string) != "Face" {
 			bag[nextInBag] = oldv
 			nextInBag++
-		} else if n, ok := oldv.Value.(rdf.Struct)[mwgrsNameName]; !ok {
+		} else if n, ok := region[mwgrsNameName]; !ok {
 			bag[nextInBag] = oldv
 			nextInBag++
-		} else if _, ok := vmap[n.Value.(string)]; ok {
+		} else if face := makeMWGRSFace(n.Value.(string), region, appliedW, appliedH); keepFace(values, seen, face) {
 			bag[nextInBag] = oldv
-			vmap[n.Value.(string)] = true
-			p.mwgrsNames = append(p.mwgrsNames, n.Value.(string))
+			nextInBag++
+			p.mwgrsRegions = append(p.mwgrsRegions, face)
 		}
 	}
 	if nextInBag != len(bag) {
-		regions[mwgrsRegionListName] = rdf.Value{Value: bag[:nextInBag]}
+		bag, changed = bag[:nextInBag], true
+	}
+	for i, face := range values {
+		if seen[i] || !face.HasArea() {
+			continue
+		}
+		if face.Unit == "" {
+			face.Unit = "normalized"
+		}
+		// The new faces' dimensions replace any existing ones, since a
+		// lossless rotation changes them along with the face areas.
+		if face.AppliedToW != 0 && (face.AppliedToW != appliedW || face.AppliedToH != appliedH) {
+			appliedW, appliedH = face.AppliedToW, face.AppliedToH
+			regions[mwgrsAppliedToDimensionsName] = rdf.Value{Value: rdf.Struct{
+				stDimWName:    rdf.Value{Value: strconv.Itoa(appliedW)},
+				stDimHName:    rdf.Value{Value: strconv.Itoa(appliedH)},
+				stDimUnitName: rdf.Value{Value: "pixel"},
+			}}
+		}
+		bag = append(bag, rdf.Value{Value: rdf.Struct{
+			mwgrsTypeName: rdf.Value{Value: "Face"},
+			mwgrsNameName: rdf.Value{Value: face.Name},
+			mwgrsAreaName: rdf.Value{Value: rdf.Struct{
+				stAreaXName:    rdf.Value{Value: formatFloat(face.X)},
+				stAreaYName:    rdf.Value{Value: formatFloat(face.Y)},
+				stAreaWName:    rdf.Value{Value: formatFloat(face.W)},
+				stAreaHName:    rdf.Value{Value: formatFloat(face.H)},
+				stAreaUnitName: rdf.Value{Value: face.Unit},
+			}},
+		}})
+		seen[i], changed = true, true
+		face.AppliedToW, face.AppliedToH = appliedW, appliedH
+		p.mwgrsRegions = append(p.mwgrsRegions, face)
+	}
+	if changed {
+		regions[mwgrsRegionListName] = rdf.Value{Value: bag}
 		p.rdf.SetProperty(mwgrsRegionsName, rdf.Value{Value: regions})
 	}
-	for _, seen := range vmap {
-		if !seen {
-			return errors.New("cannot add face regions")
+}
+
+// keepFace returns whether an existing face region should be kept, i.e.,
+// whether it is equivalent to one of the values that hasn't already been seen.
+// If so, it marks that value seen.
+func keepFace(values []metadata.FaceRegion, seen []bool, face metadata.FaceRegion) bool {
+	for i := range values {
+		if !seen[i] && values[i].Equivalent(face) {
+			seen[i] = true
+			return true
 		}
 	}
-	return nil
+	return false
+}
+
+// findFace returns the index of the face region in the list that is
+// equivalent to the specified one, or -1 if there is none.
+func findFace(faces []metadata.FaceRegion, face metadata.FaceRegion) int {
+	for i := range faces {
+		if faces[i].Equivalent(face) {
+			return i
+		}
+	}
+	return -1
 }
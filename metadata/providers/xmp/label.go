@@ -0,0 +1,41 @@
+package xmp
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+)
+
+var labelName = rdf.Name{Namespace: nsXMP, Name: "Label"}
+
+// getLabel reads the value of the Label field from the RDF.
+func (p *Provider) getLabel() (err error) {
+	if p.xmpLabel, err = getString(p.rdf.Property(labelName)); err != nil {
+		return fmt.Errorf("xmp:Label: %s", err)
+	}
+	return nil
+}
+
+// Label returns the value of the Label field.
+func (p *Provider) Label() (value string) { return p.xmpLabel }
+
+// LabelTags returns a list of tag names for the Label field, and a parallel
+// list of values held by those tags.
+func (p *Provider) LabelTags() (tags []string, values []string) {
+	return []string{"XMP  xmp:Label"}, []string{p.xmpLabel}
+}
+
+// SetLabel sets the value of the Label field.
+func (p *Provider) SetLabel(value string) error {
+	if value == "" {
+		p.xmpLabel = ""
+		p.rdf.RemoveProperty(labelName)
+		return nil
+	}
+	if value == p.xmpLabel {
+		return nil
+	}
+	p.xmpLabel = value
+	p.rdf.SetProperty(labelName, makeString(value))
+	return nil
+}
@@ -0,0 +1,72 @@
+package xmp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+)
+
+var (
+	webStatementName = rdf.Name{Namespace: nsXMPRights, Name: "WebStatement"}
+	ccLicenseName    = rdf.Name{Namespace: nsCC, Name: "license"}
+)
+
+// getLicense reads the value of the License field from the RDF.
+func (p *Provider) getLicense() (err error) {
+	if p.xmpRightsWebStatement, err = getString(p.rdf.Property(webStatementName)); err != nil {
+		return fmt.Errorf("xmpRights:WebStatement: %s", err)
+	}
+	switch val := p.rdf.Property(ccLicenseName).Value.(type) {
+	case nil:
+		break
+	case rdf.URI:
+		p.ccLicense = string(val)
+	case string:
+		p.ccLicense = val
+	default:
+		return errors.New("cc:license: wrong data type")
+	}
+	return nil
+}
+
+// License returns the value of the License field.
+func (p *Provider) License() (value string) {
+	if p.ccLicense != "" {
+		return p.ccLicense
+	}
+	return p.xmpRightsWebStatement
+}
+
+// LicenseTags returns a list of tag names for the License field, and a
+// parallel list of values held by those tags.
+func (p *Provider) LicenseTags() (tags []string, values [][]string) {
+	tags = []string{"XMP  xmpRights:WebStatement", "XMP  cc:license"}
+	values = make([][]string, 2)
+	if p.xmpRightsWebStatement != "" {
+		values[0] = []string{p.xmpRightsWebStatement}
+	}
+	if p.ccLicense != "" {
+		values[1] = []string{p.ccLicense}
+	}
+	return tags, values
+}
+
+// SetLicense sets the value of the License field.
+func (p *Provider) SetLicense(value string) error {
+	if value == "" {
+		p.xmpRightsWebStatement, p.ccLicense = "", ""
+		p.rdf.RemoveProperty(webStatementName)
+		p.rdf.RemoveProperty(ccLicenseName)
+		return nil
+	}
+	if value != p.xmpRightsWebStatement {
+		p.xmpRightsWebStatement = value
+		p.rdf.SetProperty(webStatementName, makeString(value))
+	}
+	if value != p.ccLicense {
+		p.ccLicense = value
+		p.rdf.SetProperty(ccLicenseName, rdf.Value{Value: rdf.URI(value)})
+	}
+	return nil
+}
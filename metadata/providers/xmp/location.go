@@ -10,7 +10,6 @@ import (
 
 var (
 	locationCreatedName = rdf.Name{Namespace: nsIPTC, Name: "LocationCreated"}
-	locationShownName   = rdf.Name{Namespace: nsIPTC, Name: "LocationShown"}
 	countryCodeName     = rdf.Name{Namespace: nsIPTC, Name: "CountryCode"}
 	countryNameName     = rdf.Name{Namespace: nsIPTC, Name: "CountryName"}
 	provinceStateName   = rdf.Name{Namespace: nsIPTC, Name: "ProvinceState"}
@@ -30,26 +29,6 @@ func (p *Provider) getLocation() (err error) {
 			return errors.New("Iptc4xmpExt:LocationCreated: wrong data type")
 		}
 	}
-	if val := p.rdf.Property(locationShownName); val.Value != nil {
-		switch val := val.Value.(type) {
-		case rdf.Seq:
-			p.iptcLocationsShown = make([]location, 0, len(val))
-			for idx, loc := range val {
-				switch loc := loc.Value.(type) {
-				case rdf.Struct:
-					if ls, err := getLocationFromStruct(loc); err == nil {
-						p.iptcLocationsShown = append(p.iptcLocationsShown, ls)
-					} else {
-						return fmt.Errorf("Iptc4xmpExt:LocationShown[%d]: %s", idx, err)
-					}
-				default:
-					return fmt.Errorf("Iptc4xmpExt:LocationShown[%d]: wrong data type", idx)
-				}
-			}
-		default:
-			return errors.New("Iptc4xmpExt:LocationShown: wrong data type")
-		}
-	}
 	return nil
 }
 func getLocationFromStruct(str rdf.Struct) (loc location, err error) {
@@ -73,14 +52,9 @@ func getLocationFromStruct(str rdf.Struct) (loc location, err error) {
 
 // Location returns the value of the Location field.
 func (p *Provider) Location() (value metadata.Location) {
-	var loc location
-	if !p.iptcLocationCreated.Empty() {
-		loc = p.iptcLocationCreated
-	} else if len(p.iptcLocationsShown) != 0 {
-		loc = p.iptcLocationsShown[0]
-	} else {
-		return metadata.Location{}
-	}
+	return locationToDefault(p.iptcLocationCreated)
+}
+func locationToDefault(loc location) metadata.Location {
 	return metadata.Location{
 		CountryCode: loc.CountryCode,
 		CountryName: loc.CountryName.Default(),
@@ -93,16 +67,7 @@ func (p *Provider) Location() (value metadata.Location) {
 // LocationTags returns a list of tag names for the Location field, and a
 // parallel list of values held by those tags.
 func (p *Provider) LocationTags() (tags []string, values [][]metadata.Location) {
-	tags = []string{"XMP  iptc:LocationCreated"}
-	values = [][]metadata.Location{locationToValues(p.iptcLocationCreated)}
-	if len(p.iptcLocationsShown) != 0 {
-		tags = append(tags, "XMP  iptc:LocationShown")
-		values = append(values, nil)
-		for _, shown := range p.iptcLocationsShown {
-			values[1] = append(values[1], locationToValues(shown)...)
-		}
-	}
-	return tags, values
+	return []string{"XMP  iptc:LocationCreated"}, [][]metadata.Location{locationToValues(p.iptcLocationCreated)}
 }
 func locationToValues(loc location) (values []metadata.Location) {
 	// What languages are used in the location?
@@ -119,6 +84,9 @@ func locationToValues(loc location) (values []metadata.Location) {
 	for _, ai := range loc.Sublocation {
 		langs = addUnique(langs, ai.Lang)
 	}
+	if len(langs) == 0 && loc.CountryCode != "" {
+		return []metadata.Location{{CountryCode: loc.CountryCode}}
+	}
 	// Make a location for each language.
 	for _, lang := range langs {
 		var mdl metadata.Location
@@ -127,15 +95,15 @@ func locationToValues(loc location) (values []metadata.Location) {
 			mdl.CountryName = loc.CountryName.Default()
 		}
 		if mdl.State = loc.State.Get(lang); mdl.State == "" {
-			mdl.State = loc.CountryName.Default()
+			mdl.State = loc.State.Default()
 		}
 		if mdl.City = loc.City.Get(lang); mdl.City == "" {
-			mdl.City = loc.CountryName.Default()
+			mdl.City = loc.City.Default()
 		}
 		if mdl.Sublocation = loc.Sublocation.Get(lang); mdl.Sublocation == "" {
-			mdl.Sublocation = loc.CountryName.Default()
+			mdl.Sublocation = loc.Sublocation.Default()
 		}
-		if loc.Empty() {
+		if mdl.Empty() {
 			continue
 		}
 		values = append(values, mdl)
@@ -153,79 +121,87 @@ func addUnique(list []string, val string) []string {
 
 // SetLocation sets the value of the Location field.
 func (p *Provider) SetLocation(value metadata.Location) error {
-	p.iptcLocationsShown = nil
-	p.rdf.RemoveProperty(locationShownName)
 	if value.Empty() {
 		p.iptcLocationCreated = location{}
 		p.rdf.RemoveProperty(locationCreatedName)
 		return nil
 	}
-	if value.CountryCode != p.iptcLocationCreated.CountryCode {
-		goto DIFFERENT
+	if locationMatches(p.iptcLocationCreated, value) {
+		return nil
 	}
-	switch len(p.iptcLocationCreated.CountryName) {
-	case 0:
-		if value.CountryName != "" {
-			goto DIFFERENT
-		}
-	case 1:
-		if value.CountryName != p.iptcLocationCreated.CountryName.Default() {
-			goto DIFFERENT
+	p.iptcLocationCreated = locationFromValue(value)
+	p.rdf.SetProperty(locationCreatedName, makeLocationStruct(p.iptcLocationCreated))
+	return nil
+}
+
+// locationMatches returns whether the location (which may have language
+// alternatives) is the same as the value (which does not).
+func locationMatches(loc location, value metadata.Location) bool {
+	if value.CountryCode != loc.CountryCode {
+		return false
+	}
+	for _, pair := range []struct {
+		as  altString
+		str string
+	}{
+		{loc.CountryName, value.CountryName},
+		{loc.State, value.State},
+		{loc.City, value.City},
+		{loc.Sublocation, value.Sublocation},
+	} {
+		switch len(pair.as) {
+		case 0:
+			if pair.str != "" {
+				return false
+			}
+		case 1:
+			if pair.str != pair.as.Default() {
+				return false
+			}
+		default:
+			return false
 		}
-	default:
-		goto DIFFERENT
 	}
-	switch len(p.iptcLocationCreated.State) {
-	case 0:
-		if value.State != "" {
-			goto DIFFERENT
-		}
-	case 1:
-		if value.State != p.iptcLocationCreated.State.Default() {
-			goto DIFFERENT
-		}
-	default:
-		goto DIFFERENT
+	return true
+}
+
+// locationFromValue converts a location value into the form stored in the
+// XMP.
+func locationFromValue(value metadata.Location) location {
+	var loc = location{CountryCode: value.CountryCode}
+	if value.CountryName != "" {
+		loc.CountryName = newAltString(value.CountryName)
 	}
-	switch len(p.iptcLocationCreated.City) {
-	case 0:
-		if value.City != "" {
-			goto DIFFERENT
-		}
-	case 1:
-		if value.City != p.iptcLocationCreated.City.Default() {
-			goto DIFFERENT
-		}
-	default:
-		goto DIFFERENT
+	if value.State != "" {
+		loc.State = newAltString(value.State)
 	}
-	switch len(p.iptcLocationCreated.Sublocation) {
-	case 0:
-		if value.Sublocation != "" {
-			goto DIFFERENT
-		}
-	case 1:
-		if value.Sublocation != p.iptcLocationCreated.Sublocation.Default() {
-			goto DIFFERENT
-		}
-	default:
-		goto DIFFERENT
+	if value.City != "" {
+		loc.City = newAltString(value.City)
 	}
-	return nil
-DIFFERENT:
-	p.iptcLocationCreated = location{
-		CountryCode: value.CountryCode,
-		CountryName: newAltString(value.CountryName),
-		State:       newAltString(value.State),
-		City:        newAltString(value.City),
-		Sublocation: newAltString(value.Sublocation),
-	}
-	var str rdf.Struct
-	str[countryCodeName] = makeString(p.iptcLocationCreated.CountryCode)
-	str[countryNameName] = makeAlt(p.iptcLocationCreated.CountryName)
-	str[provinceStateName] = makeAlt(p.iptcLocationCreated.State)
-	str[cityName] = makeAlt(p.iptcLocationCreated.City)
-	str[sublocationName] = makeAlt(p.iptcLocationCreated.Sublocation)
-	p.rdf.SetProperty(locationCreatedName, rdf.Value{Value: str})
-	return nil
+	if value.Sublocation != "" {
+		loc.Sublocation = newAltString(value.Sublocation)
+	}
+	return loc
+}
+
+// makeLocationStruct creates an Iptc4xmpExt:LocationDetails structure for the
+// location.  Empty components are omitted.
+func makeLocationStruct(loc location) rdf.Value {
+	var str = rdf.Struct{}
+	if loc.CountryCode != "" {
+		str[countryCodeName] = makeString(loc.CountryCode)
+	}
+	if !loc.CountryName.Empty() {
+		str[countryNameName] = makeAlt(loc.CountryName)
+	}
+	if !loc.State.Empty() {
+		str[provinceStateName] = makeAlt(loc.State)
+	}
+	if !loc.City.Empty() {
+		str[cityName] = makeAlt(loc.City)
+	}
+	if !loc.Sublocation.Empty() {
+		str[sublocationName] = makeAlt(loc.Sublocation)
+	}
+	return rdf.Value{Value: str}
 }
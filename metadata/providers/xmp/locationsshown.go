@@ -0,0 +1,100 @@
+package xmp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+)
+
+var locationShownName = rdf.Name{Namespace: nsIPTC, Name: "LocationShown"}
+
+// getLocationsShown reads the values of the LocationsShown field from the RDF.
+func (p *Provider) getLocationsShown() (err error) {
+	var vals []rdf.Value
+
+	switch val := p.rdf.Property(locationShownName).Value.(type) {
+	case nil:
+		return nil
+	case rdf.Seq:
+		vals = val
+	case rdf.Bag:
+		vals = val
+	default:
+		return errors.New("Iptc4xmpExt:LocationShown: wrong data type")
+	}
+	p.iptcLocationsShown = make([]location, 0, len(vals))
+	for idx, loc := range vals {
+		switch loc := loc.Value.(type) {
+		case rdf.Struct:
+			if ls, err := getLocationFromStruct(loc); err == nil {
+				p.iptcLocationsShown = append(p.iptcLocationsShown, ls)
+			} else {
+				return fmt.Errorf("Iptc4xmpExt:LocationShown[%d]: %s", idx, err)
+			}
+		default:
+			return fmt.Errorf("Iptc4xmpExt:LocationShown[%d]: wrong data type", idx)
+		}
+	}
+	return nil
+}
+
+// LocationsShown returns the values of the LocationsShown field.
+func (p *Provider) LocationsShown() (values []metadata.Location) {
+	for _, loc := range p.iptcLocationsShown {
+		if !loc.Empty() {
+			values = append(values, locationToDefault(loc))
+		}
+	}
+	return values
+}
+
+// LocationsShownTags returns a list of tag names for the LocationsShown
+// field, and a parallel list of values held by those tags.
+func (p *Provider) LocationsShownTags() (tags []string, values [][]metadata.Location) {
+	var list []metadata.Location
+	for _, shown := range p.iptcLocationsShown {
+		list = append(list, locationToValues(shown)...)
+	}
+	return []string{"XMP  iptc:LocationShown"}, [][]metadata.Location{list}
+}
+
+// SetLocationsShown sets the values of the LocationsShown field.  Existing
+// locations that match the new values keep their language alternatives.
+func (p *Provider) SetLocationsShown(values []metadata.Location) error {
+	var (
+		shown   []location
+		changed bool
+	)
+	for _, value := range values {
+		if value.Empty() {
+			continue
+		}
+		var found = false
+		for _, loc := range p.iptcLocationsShown {
+			if locationToDefault(loc).Equal(value) {
+				shown, found = append(shown, loc), true
+				break
+			}
+		}
+		if !found {
+			shown, changed = append(shown, locationFromValue(value)), true
+		}
+	}
+	if len(shown) == 0 {
+		p.iptcLocationsShown = nil
+		p.rdf.RemoveProperty(locationShownName)
+		return nil
+	}
+	if !changed && len(shown) == len(p.iptcLocationsShown) {
+		return nil
+	}
+	p.iptcLocationsShown = shown
+	var bag = make(rdf.Bag, len(shown))
+	for i, loc := range shown {
+		bag[i] = makeLocationStruct(loc)
+	}
+	p.rdf.SetProperty(locationShownName, rdf.Value{Value: bag})
+	return nil
+}
@@ -20,6 +20,9 @@ func (p *Provider) getOrientation() (err error) {
 	if ostring, err = getString(p.rdf.Property(orientationName)); err != nil {
 		return fmt.Errorf("tiff:Orientation: %s", err)
 	}
+	if ostring == "" {
+		return nil
+	}
 	if oval, err = strconv.Atoi(ostring); err != nil {
 		return fmt.Errorf("tiff:Orientation: %s", err)
 	}
@@ -16,16 +16,10 @@ func (p *Provider) People() (values []string) {
 		values[i] = kws[i][1]
 		pmap[values[i]] = true
 	}
-	for _, face := range p.mpRegPersonDisplayNames {
-		if !pmap[face] {
-			values = append(values, face)
-			pmap[face] = true
-		}
-	}
-	for _, face := range p.mwgrsNames {
-		if !pmap[face] {
-			values = append(values, face)
-			pmap[face] = true
+	for _, face := range p.Faces() {
+		if !pmap[face.Name] {
+			values = append(values, face.Name)
+			pmap[face.Name] = true
 		}
 	}
 	return values
@@ -50,18 +44,16 @@ func (p *Provider) PeopleTags() (tags []string, values [][]string) {
 
 // SetPeople sets the values of the People field.
 func (p *Provider) SetPeople(values []string) (err error) {
-	var faces = p.Faces()
-	var facemap = make(map[string]bool)
-	for _, face := range faces {
-		facemap[face] = false
-	}
-	faces = faces[:0]
+	var faces []metadata.FaceRegion
+	var pmap = make(map[string]bool)
 	var kws = make([]metadata.HierValue, len(values))
 	for i := range values {
 		kws[i] = metadata.HierValue{"People", values[i]}
-		if seen, ok := facemap[values[i]]; ok && !seen {
-			facemap[values[i]] = true
-			faces = append(faces, values[i])
+		pmap[values[i]] = true
+	}
+	for _, face := range p.Faces() {
+		if pmap[face.Name] {
+			faces = append(faces, face)
 		}
 	}
 	p.setFilteredKeywords(personPredicate, kws)
@@ -34,49 +34,41 @@ func (p *Provider) PlacesTags() (tags []string, values [][]metadata.HierValue) {
 // SetPlaces sets the values of the Places field.
 func (p *Provider) SetPlaces(values []metadata.HierValue) (err error) {
 	var (
-		kws      = make([]metadata.HierValue, len(values))
-		parts    []string
-		location = p.Location()
+		kws   = make([]metadata.HierValue, len(values))
+		shown []metadata.Location
 	)
 	for i := range values {
 		kws[i] = append(metadata.HierValue{"Places"}, values[i]...)
 	}
 	p.setFilteredKeywords(placePredicate, kws)
-	// SetPlaces clears the value of the Location field if the places that
-	// are being set do not include the location.
-	if location.CountryName != "" {
-		parts = append(parts, location.CountryName)
-	}
-	if location.State != "" {
-		parts = append(parts, location.State)
-	}
-	if location.City != "" {
-		parts = append(parts, location.City)
-	}
-	if location.Sublocation != "" {
-		parts = append(parts, location.Sublocation)
-	}
-	if len(parts) == 0 {
-		return nil
-	}
-	for _, p := range values {
-		if locationCongruentToPlace(parts, p) {
-			return nil
+	// SetPlaces clears the value of the Location field, and removes values
+	// of the LocationsShown field, if the places that are being set do not
+	// include them.
+	if !congruentToAny(p.Location(), values) {
+		if err = p.SetLocation(metadata.Location{}); err != nil {
+			return err
 		}
 	}
-	if err = p.SetLocation(metadata.Location{}); err != nil {
-		return err
+	for _, loc := range p.LocationsShown() {
+		if congruentToAny(loc, values) {
+			shown = append(shown, loc)
+		}
 	}
-	return nil
+	return p.SetLocationsShown(shown)
 }
-func locationCongruentToPlace(loc []string, place metadata.HierValue) bool {
-	for len(loc) != 0 && len(place) != 0 {
-		if loc[0] == place[0] {
-			loc = loc[1:]
+
+// congruentToAny returns whether the location is congruent to any of the
+// places.  An empty location is considered congruent.
+func congruentToAny(loc metadata.Location, places []metadata.HierValue) bool {
+	if loc.CountryName == "" && loc.State == "" && loc.City == "" && loc.Sublocation == "" {
+		return true
+	}
+	for _, place := range places {
+		if loc.CongruentTo(place) {
+			return true
 		}
-		place = place[1:]
 	}
-	return len(loc) == 0
+	return false
 }
 
 // placePredicate is the predicate satisfied by keyword tags that encode place
@@ -0,0 +1,80 @@
+package xmp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+)
+
+var (
+	msPhotoRatingName = rdf.Name{Namespace: nsMSPhoto, Name: "Rating"}
+	xmpRatingName     = rdf.Name{Namespace: nsXMP, Name: "Rating"}
+)
+
+// getRating reads the value of the Rating field from the RDF.
+func (p *Provider) getRating() (err error) {
+	var str string
+	var val float64
+
+	if str, err = getString(p.rdf.Property(xmpRatingName)); err != nil {
+		return fmt.Errorf("xmp:Rating: %s", err)
+	}
+	if str != "" {
+		// The XMP specification defines xmp:Rating as a Real, so we
+		// accept fractional values and round them.
+		if val, err = strconv.ParseFloat(str, 64); err != nil || val < -1 || val > 5 {
+			return fmt.Errorf("xmp:Rating: invalid value %q", str)
+		}
+		p.xmpRating = int(math.Round(val))
+	}
+	if str, err = getString(p.rdf.Property(msPhotoRatingName)); err != nil {
+		return fmt.Errorf("MicrosoftPhoto:Rating: %s", err)
+	}
+	if str != "" {
+		var percent int
+
+		if percent, err = strconv.Atoi(str); err != nil || percent < 0 || percent > 100 {
+			return fmt.Errorf("MicrosoftPhoto:Rating: invalid value %q", str)
+		}
+		p.msPhotoRating = metadata.PercentToRating(percent)
+	}
+	return nil
+}
+
+// Rating returns the value of the Rating field.
+func (p *Provider) Rating() (value int) {
+	if p.xmpRating != 0 {
+		return p.xmpRating
+	}
+	return p.msPhotoRating
+}
+
+// RatingTags returns a list of tag names for the Rating field, and a parallel
+// list of values held by those tags.
+func (p *Provider) RatingTags() (tags []string, values []int) {
+	return []string{"XMP  xmp:Rating", "XMP  MicrosoftPhoto:Rating"}, []int{p.xmpRating, p.msPhotoRating}
+}
+
+// SetRating sets the value of the Rating field.
+func (p *Provider) SetRating(value int) error {
+	if value == 0 {
+		p.xmpRating = 0
+		p.rdf.RemoveProperty(xmpRatingName)
+	} else if value != p.xmpRating {
+		p.xmpRating = value
+		p.rdf.SetProperty(xmpRatingName, makeString(strconv.Itoa(value)))
+	}
+	// Windows has no representation for rejected media, so we remove
+	// MicrosoftPhoto:Rating for them as well as for unrated media.
+	if value <= 0 {
+		p.msPhotoRating = 0
+		p.rdf.RemoveProperty(msPhotoRatingName)
+	} else if value != p.msPhotoRating {
+		p.msPhotoRating = value
+		p.rdf.SetProperty(msPhotoRatingName, makeString(strconv.Itoa(metadata.RatingToPercent(value))))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package xmp
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+)
+
+var sourceName = rdf.Name{Namespace: nsPS, Name: "Source"}
+
+// getSource reads the value of the Source field from the RDF.
+func (p *Provider) getSource() (err error) {
+	if p.psSource, err = getString(p.rdf.Property(sourceName)); err != nil {
+		return fmt.Errorf("photoshop:Source: %s", err)
+	}
+	return nil
+}
+
+// Source returns the value of the Source field.
+func (p *Provider) Source() (value string) { return p.psSource }
+
+// SourceTags returns a list of tag names for the Source field, and a
+// parallel list of values held by those tags.
+func (p *Provider) SourceTags() (tags []string, values [][]string) {
+	if p.psSource == "" {
+		return []string{"XMP  photoshop:Source"}, [][]string{nil}
+	}
+	return []string{"XMP  photoshop:Source"}, [][]string{{p.psSource}}
+}
+
+// SetSource sets the value of the Source field.
+func (p *Provider) SetSource(value string) error {
+	if value == "" {
+		p.psSource = ""
+		p.rdf.RemoveProperty(sourceName)
+		return nil
+	}
+	if value == p.psSource {
+		return nil
+	}
+	p.psSource = value
+	p.rdf.SetProperty(sourceName, makeString(value))
+	return nil
+}
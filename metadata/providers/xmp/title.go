@@ -3,6 +3,7 @@ package xmp
 import (
 	"fmt"
 
+	"github.com/rothskeller/photo-tools/metadata"
 	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
 )
 
@@ -20,26 +21,32 @@ func (p *Provider) getTitle() (err error) {
 func (p *Provider) Title() (value string) { return p.dcTitle.Default() }
 
 // TitleTags returns a list of tag names for the Title field, and a
-// parallel list of values held by those tags.
+// parallel list of values held by those tags.  Only the default language
+// alternative is listed; see TitleAlt for the others.
 func (p *Provider) TitleTags() (tags []string, values [][]string) {
-	values = [][]string{nil}
-	for _, as := range p.dcTitle {
-		values[0] = append(values[0], as.Value)
-	}
-	return []string{"XMP  dc:title"}, values
+	return []string{"XMP  dc:title"}, [][]string{defaultOnly(p.dcTitle)}
 }
 
-// SetTitle sets the values of the Title field.
+// SetTitle sets the values of the Title field.  Language alternatives other
+// than the default are retained.
 func (p *Provider) SetTitle(value string) error {
-	if value == "" {
+	return p.SetTitleAlt(p.dcTitle.SetDefault(value))
+}
+
+// TitleAlt returns the language alternatives of the Title field.
+func (p *Provider) TitleAlt() (value metadata.AltString) { return p.dcTitle }
+
+// SetTitleAlt sets the language alternatives of the Title field.
+func (p *Provider) SetTitleAlt(value metadata.AltString) error {
+	if value.Empty() {
 		p.dcTitle = nil
 		p.rdf.RemoveProperty(titleName)
 		return nil
 	}
-	if len(p.dcTitle) == 1 && value == p.dcTitle[0].Value {
+	if value.Equal(p.dcTitle) {
 		return nil
 	}
-	p.dcTitle = newAltString(value)
+	p.dcTitle = value
 	p.rdf.SetProperty(titleName, makeAlt(p.dcTitle))
 	return nil
 }
@@ -3,52 +3,30 @@ package xmp
 import (
 	"errors"
 
+	"github.com/rothskeller/photo-tools/metadata"
 	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
 )
 
 var xmlLang = rdf.Name{Namespace: rdf.NSxml, Name: "lang"}
 
 // An altString is a set of language alternatives for a single conceptual
-// string.  The first alternative is the default language.
-type altString []altItem
+// string.  It is the metadata.AltString exposed through the Provider
+// interface.
+type altString = metadata.AltString
 
 // An altItem is a single language variant of an altString.
-type altItem struct {
-	Value string
-	Lang  string
-}
+type altItem = metadata.AltItem
 
 // newAltString creates a new altString, with a single default alternative.
-func newAltString(s string) altString {
-	return altString{{s, "x-default"}}
-}
-
-// Empty returns true if the AltString contains no non-empty values.
-func (as altString) Empty() bool {
-	for _, ai := range as {
-		if ai.Value != "" {
-			return false
-		}
-	}
-	return true
-}
+func newAltString(s string) altString { return metadata.NewAltString(s) }
 
-// Default returns the default string from the altString.
-func (as altString) Default() string {
-	if len(as) == 0 {
-		return ""
-	}
-	return as[0].Value
-}
-
-// Get returns the value of the AltString for the specified language.
-func (as altString) Get(lang string) string {
-	for _, alt := range as {
-		if alt.Lang == lang {
-			return alt.Value
-		}
+// defaultOnly returns a list containing the default value of an altString, or
+// an empty list if it has none.
+func defaultOnly(as altString) []string {
+	if as.Empty() {
+		return nil
 	}
-	return ""
+	return []string{as.Default()}
 }
 
 // A Location is the textual description of a location, with language
@@ -0,0 +1,44 @@
+package xmp
+
+import (
+	"fmt"
+
+	"github.com/rothskeller/photo-tools/metadata/containers/rdf"
+)
+
+var usageTermsName = rdf.Name{Namespace: nsXMPRights, Name: "UsageTerms"}
+
+// getUsageTerms reads the value of the UsageTerms field from the RDF.
+func (p *Provider) getUsageTerms() (err error) {
+	if p.xmpRightsUsageTerms, err = getAlt(p.rdf.Property(usageTermsName)); err != nil {
+		return fmt.Errorf("xmpRights:UsageTerms: %s", err)
+	}
+	return nil
+}
+
+// UsageTerms returns the value of the UsageTerms field.
+func (p *Provider) UsageTerms() (value string) { return p.xmpRightsUsageTerms.Default() }
+
+// UsageTermsTags returns a list of tag names for the UsageTerms field, and a
+// parallel list of values held by those tags.  Only the default language
+// alternative is listed.
+func (p *Provider) UsageTermsTags() (tags []string, values [][]string) {
+	return []string{"XMP  xmpRights:UsageTerms"}, [][]string{defaultOnly(p.xmpRightsUsageTerms)}
+}
+
+// SetUsageTerms sets the value of the UsageTerms field.  Language alternatives
+// other than the default are retained.
+func (p *Provider) SetUsageTerms(value string) error {
+	var terms = p.xmpRightsUsageTerms.SetDefault(value)
+	if terms.Empty() {
+		p.xmpRightsUsageTerms = nil
+		p.rdf.RemoveProperty(usageTermsName)
+		return nil
+	}
+	if terms.Equal(p.xmpRightsUsageTerms) {
+		return nil
+	}
+	p.xmpRightsUsageTerms = terms
+	p.rdf.SetProperty(usageTermsName, makeAlt(p.xmpRightsUsageTerms))
+	return nil
+}
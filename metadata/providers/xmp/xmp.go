@@ -8,58 +8,77 @@ import (
 )
 
 const (
-	pfxDC      = "dc"
-	nsDC       = "http://purl.org/dc/elements/1.1/"
-	pfxDigiKam = "digiKam"
-	nsDigiKam  = "http://www.digikam.org/ns/1.0/"
-	pfxEXIF    = "exif"
-	nsEXIF     = "http://ns.adobe.com/exif/1.0/"
-	pfxIPTC    = "Iptc4xmpExt"
-	nsIPTC     = "http://iptc.org/std/Iptc4xmpExt/2008-02-29/"
-	pfxLR      = "lr"
-	nsLR       = "http://ns.adobe.com/lightroom/1.0/"
-	pfxMP      = "MP"
-	nsMP       = "http://ns.microsoft.com/photo/1.2/"
-	pfxMPRI    = "MPRI"
-	nsMPRI     = "http://ns.microsoft.com/photo/1.2/t/RegionInfo#"
-	pfxMPReg   = "MPReg"
-	nsMPReg    = "http://ns.microsoft.com/photo/1.2/t/Region#"
-	pfxMWGRS   = "mwg-rs"
-	nsMWGRS    = "http://www.metadataworkinggroup.com/schemas/regions/"
-	pfxPS      = "photoshop"
-	nsPS       = "http://ns.adobe.com/photoshop/1.0/"
-	pfxTIFF    = "tiff"
-	nsTIFF     = "http://ns.adobe.com/tiff/1.0/"
-	pfxXMP     = "xmp"
-	nsXMP      = "http://ns.adobe.com/xap/1.0/"
+	pfxCC        = "cc"
+	nsCC         = "http://creativecommons.org/ns#"
+	pfxDC        = "dc"
+	nsDC         = "http://purl.org/dc/elements/1.1/"
+	pfxDigiKam   = "digiKam"
+	nsDigiKam    = "http://www.digikam.org/ns/1.0/"
+	pfxEXIF      = "exif"
+	nsEXIF       = "http://ns.adobe.com/exif/1.0/"
+	pfxIPTC      = "Iptc4xmpExt"
+	nsIPTC       = "http://iptc.org/std/Iptc4xmpExt/2008-02-29/"
+	pfxLR        = "lr"
+	nsLR         = "http://ns.adobe.com/lightroom/1.0/"
+	pfxMP        = "MP"
+	nsMP         = "http://ns.microsoft.com/photo/1.2/"
+	pfxMPRI      = "MPRI"
+	nsMPRI       = "http://ns.microsoft.com/photo/1.2/t/RegionInfo#"
+	pfxMPReg     = "MPReg"
+	nsMPReg      = "http://ns.microsoft.com/photo/1.2/t/Region#"
+	pfxMSPhoto   = "MicrosoftPhoto"
+	nsMSPhoto    = "http://ns.microsoft.com/photo/1.0/"
+	pfxMWGRS     = "mwg-rs"
+	nsMWGRS      = "http://www.metadataworkinggroup.com/schemas/regions/"
+	pfxPS        = "photoshop"
+	nsPS         = "http://ns.adobe.com/photoshop/1.0/"
+	pfxStArea    = "stArea"
+	nsStArea     = "http://ns.adobe.com/xmp/sType/Area#"
+	pfxStDim     = "stDim"
+	nsStDim      = "http://ns.adobe.com/xap/1.0/sType/Dimensions#"
+	pfxTIFF      = "tiff"
+	nsTIFF       = "http://ns.adobe.com/tiff/1.0/"
+	pfxXMP       = "xmp"
+	nsXMP        = "http://ns.adobe.com/xap/1.0/"
+	pfxXMPRights = "xmpRights"
+	nsXMPRights  = "http://ns.adobe.com/xap/1.0/rights/"
 )
 
 // A Provider handles data from an XMP/RDF block — but only the native XMP data,
 // not the namespaces that mirror EXIF, IPTC, and/or Photoshop data.
 type Provider struct {
 	metadata.BaseProvider
-	dcCreator               []string
-	dcDescription           altString
-	dcSubject               []string
-	dcTitle                 altString
-	digiKamTagsList         []metadata.HierValue
-	exifDateTimeOriginal    metadata.DateTime
-	exifDateTimeDigitized   metadata.DateTime
-	exifGPSCoords           metadata.GPSCoords
-	exifUserComment         altString
-	iptcLocationCreated     location
-	iptcLocationsShown      []location
-	lrHierarchicalSubject   []metadata.HierValue
-	mpRegPersonDisplayNames []string
-	mwgrsNames              []string
-	psDateCreated           metadata.DateTime
-	tiffArtist              []string
-	tiffDateTime            metadata.DateTime
-	tiffImageDescription    altString
-	tiffOrientation         metadata.Orientation
-	xmpCreateDate           metadata.DateTime
-	xmpMetadataDate         metadata.DateTime
-	xmpModifyDate           metadata.DateTime
+	ccLicense             string
+	dcCreator             []string
+	dcDescription         altString
+	dcRights              altString
+	dcSubject             []string
+	dcTitle               altString
+	digiKamTagsList       []metadata.HierValue
+	exifDateTimeOriginal  metadata.DateTime
+	exifDateTimeDigitized metadata.DateTime
+	exifGPSCoords         metadata.GPSCoords
+	exifUserComment       altString
+	iptcLocationCreated   location
+	iptcLocationsShown    []location
+	lrHierarchicalSubject []metadata.HierValue
+	mpRegions             []metadata.FaceRegion
+	msPhotoRating         int
+	mwgrsRegions          []metadata.FaceRegion
+	psCredit              string
+	psDateCreated         metadata.DateTime
+	psSource              string
+	tiffArtist            []string
+	tiffDateTime          metadata.DateTime
+	tiffImageDescription  altString
+	tiffOrientation       metadata.Orientation
+	xmpCreateDate         metadata.DateTime
+	xmpLabel              string
+	xmpMetadataDate       metadata.DateTime
+	xmpModifyDate         metadata.DateTime
+	xmpRating             int
+	xmpRightsUsageTerms   altString
+	xmpRightsWebStatement string
 
 	rdf *rdf.Packet
 }
@@ -69,6 +88,7 @@ var _ metadata.Provider = (*Provider)(nil) // verify interface compliance
 // New creates a new Provider based on the provided RDF block.
 func New(rdf *rdf.Packet) (p *Provider, err error) {
 	p = &Provider{rdf: rdf}
+	p.rdf.RegisterNamespace(pfxCC, nsCC)
 	p.rdf.RegisterNamespace(pfxDC, nsDC)
 	p.rdf.RegisterNamespace(pfxDigiKam, nsDigiKam)
 	p.rdf.RegisterNamespace(pfxEXIF, nsEXIF)
@@ -77,16 +97,26 @@ func New(rdf *rdf.Packet) (p *Provider, err error) {
 	p.rdf.RegisterNamespace(pfxMP, nsMP)
 	p.rdf.RegisterNamespace(pfxMPRI, nsMPRI)
 	p.rdf.RegisterNamespace(pfxMPReg, nsMPReg)
+	p.rdf.RegisterNamespace(pfxMSPhoto, nsMSPhoto)
 	p.rdf.RegisterNamespace(pfxMWGRS, nsMWGRS)
 	p.rdf.RegisterNamespace(pfxPS, nsPS)
+	p.rdf.RegisterNamespace(pfxStArea, nsStArea)
+	p.rdf.RegisterNamespace(pfxStDim, nsStDim)
 	p.rdf.RegisterNamespace(pfxTIFF, nsTIFF)
 	p.rdf.RegisterNamespace(pfxXMP, nsXMP)
+	p.rdf.RegisterNamespace(pfxXMPRights, nsXMPRights)
 	if err = p.getCaption(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
+	if err = p.getCopyright(); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
 	if err = p.getCreator(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
+	if err = p.getCredit(); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
 	if err = p.getDateTime(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
@@ -102,9 +132,18 @@ func New(rdf *rdf.Packet) (p *Provider, err error) {
 	if err = p.getKeywords(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
+	if err = p.getLabel(); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
+	if err = p.getLicense(); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
 	if err = p.getLocation(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
+	if err = p.getLocationsShown(); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
 	if err = p.getOrientation(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
@@ -114,12 +153,21 @@ func New(rdf *rdf.Packet) (p *Provider, err error) {
 	if err = p.getPlaces(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
+	if err = p.getRating(); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
+	if err = p.getSource(); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
 	if err = p.getTitle(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
 	if err = p.getTopics(); err != nil {
 		return nil, fmt.Errorf("XMP: %s", err)
 	}
+	if err = p.getUsageTerms(); err != nil {
+		return nil, fmt.Errorf("XMP: %s", err)
+	}
 	return p, nil
 }
 
@@ -0,0 +1,271 @@
+// Package xp contains the provider for the Windows XP* tags in the root IFD of
+// a JPEG or TIFF file.
+package xp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+)
+
+// Windows Explorer and Windows Photo Gallery store titles, comments, authors,
+// keywords, and subjects in these tags, as UTF-16LE text.
+const (
+	tagXPTitle    uint16 = 0x9C9B
+	tagXPComment  uint16 = 0x9C9C
+	tagXPAuthor   uint16 = 0x9C9D
+	tagXPKeywords uint16 = 0x9C9E
+	tagXPSubject  uint16 = 0x9C9F
+)
+
+// The kinds of entries in XPKeywords.  It is a flat list, in which people and
+// places appear alongside keywords, as they do in dc:subject.
+const (
+	kindKeyword = iota
+	kindPerson
+	kindPlace
+)
+
+// A Provider handles the Windows XP* tags in an IFD0.  It is separate from the
+// providers for the rest of the IFD0 so that file handlers can give it the
+// lowest priority:  these tags are often stale, and a flat keyword list
+// shouldn't override a hierarchical one.
+//
+// The Provider never adds XP* tags.  Changes to a field update its XP* tags if
+// they are present, keeping them in sync with the other tags, or remove them
+// if RemoveXPTags has been called.
+type Provider struct {
+	metadata.BaseProvider
+	author   []string
+	comment  string
+	keywords []string
+	kinds    map[string]int
+	subject  string
+	title    string
+	remove   bool
+
+	ifd *tiff.IFD
+}
+
+var _ metadata.Provider = (*Provider)(nil) // verify interface compliance
+
+// New creates a new Provider based on the provided IFD.  The entries in
+// XPKeywords that name people or places known to the other providers of the
+// file are kept apart from its keywords.
+func New(ifd *tiff.IFD, others metadata.Provider) (p *Provider, err error) {
+	var author, keywords string
+
+	p = &Provider{ifd: ifd, kinds: make(map[string]int)}
+	if author, err = p.getTag(tagXPAuthor, "XPAuthor"); err != nil {
+		return nil, err
+	}
+	p.author = splitXPList(author)
+	if p.comment, err = p.getTag(tagXPComment, "XPComment"); err != nil {
+		return nil, err
+	}
+	if keywords, err = p.getTag(tagXPKeywords, "XPKeywords"); err != nil {
+		return nil, err
+	}
+	p.keywords = splitXPList(keywords)
+	if p.subject, err = p.getTag(tagXPSubject, "XPSubject"); err != nil {
+		return nil, err
+	}
+	if p.title, err = p.getTag(tagXPTitle, "XPTitle"); err != nil {
+		return nil, err
+	}
+	for _, person := range others.People() {
+		p.kinds[person] = kindPerson
+	}
+	for _, place := range others.Places() {
+		p.kinds[place[len(place)-1]] = kindPlace
+	}
+	return p, nil
+}
+
+// ProviderName is the name for the provider, for debug purposes.
+func (p *Provider) ProviderName() string { return "IFD0 XP" }
+
+// RemoveXPTags causes subsequent changes to fields to remove the corresponding
+// XP* tags, rather than keeping them in sync with the other tags.
+func (p *Provider) RemoveXPTags() { p.remove = true }
+
+// getTag reads the value of an XP* tag from the IFD.
+func (p *Provider) getTag(tnum uint16, name string) (value string, err error) {
+	if tag := p.ifd.Tag(tnum); tag != nil {
+		if value, err = tag.AsUCS2(); err != nil {
+			return "", fmt.Errorf("IFD0 %s: %s", name, err)
+		}
+	}
+	return value, nil
+}
+
+// hasTag returns whether the IFD has the specified XP* tag.
+func (p *Provider) hasTag(tnum uint16) bool { return p.ifd.Tag(tnum) != nil }
+
+// setTag sets the value of an XP* tag, if it is present, and returns the
+// value it now has.  The tag is removed if the value is empty or if
+// RemoveXPTags has been called.
+func (p *Provider) setTag(tnum uint16, value string) string {
+	if !p.hasTag(tnum) {
+		return ""
+	}
+	if value == "" || p.remove {
+		p.ifd.DeleteTag(tnum)
+		return ""
+	}
+	p.ifd.Tag(tnum).SetUCS2(value)
+	return value
+}
+
+// splitXPList splits a semicolon-separated list from an XP* tag.
+func splitXPList(s string) (list []string) {
+	for _, item := range strings.Split(s, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// Caption returns the value of the Caption field.
+func (p *Provider) Caption() (value string) {
+	if p.comment != "" {
+		return p.comment
+	}
+	return p.subject
+}
+
+// CaptionTags returns a list of tag names for the Caption field, and a
+// parallel list of values held by those tags.
+func (p *Provider) CaptionTags() (tags []string, values [][]string) {
+	if p.hasTag(tagXPComment) {
+		tags, values = append(tags, "IFD0 XPComment"), append(values, []string{p.comment})
+	}
+	if p.hasTag(tagXPSubject) {
+		tags, values = append(tags, "IFD0 XPSubject"), append(values, []string{p.subject})
+	}
+	return tags, values
+}
+
+// SetCaption sets the value of the Caption field.
+func (p *Provider) SetCaption(value string) error {
+	p.comment = p.setTag(tagXPComment, value)
+	p.subject = p.setTag(tagXPSubject, value)
+	return nil
+}
+
+// Creator returns the value of the Creator field.
+func (p *Provider) Creator() (value string) {
+	if len(p.author) == 0 {
+		return ""
+	}
+	return p.author[0]
+}
+
+// CreatorTags returns a list of tag names for the Creator field, and a
+// parallel list of values held by those tags.
+func (p *Provider) CreatorTags() (tags []string, values [][]string) {
+	if !p.hasTag(tagXPAuthor) {
+		return nil, nil
+	}
+	return []string{"IFD0 XPAuthor"}, [][]string{p.author}
+}
+
+// SetCreator sets the value of the Creator field.
+func (p *Provider) SetCreator(value string) error {
+	p.author = splitXPList(p.setTag(tagXPAuthor, value))
+	return nil
+}
+
+// Keywords returns the values of the Keywords field.  Entries in XPKeywords
+// that name people or places are not included.
+func (p *Provider) Keywords() (values []metadata.HierValue) {
+	values = make([]metadata.HierValue, 0, len(p.keywords))
+	for _, kw := range p.keywords {
+		if p.kinds[kw] == kindKeyword {
+			values = append(values, metadata.HierValue{kw})
+		}
+	}
+	return values
+}
+
+// KeywordsTags returns a list of tag names for the Keywords field, and a
+// parallel list of values held by those tags.
+func (p *Provider) KeywordsTags() (tags []string, values [][]metadata.HierValue) {
+	if !p.hasTag(tagXPKeywords) {
+		return nil, nil
+	}
+	return []string{"IFD0 XPKeywords"}, [][]metadata.HierValue{p.Keywords()}
+}
+
+// SetKeywords sets the values of the Keywords field.  XPKeywords is not
+// hierarchical, so only the last component of each keyword is stored.  The
+// entries in it that name people or places are kept.
+func (p *Provider) SetKeywords(values []metadata.HierValue) error {
+	p.setKind(kindKeyword, values)
+	return nil
+}
+
+// SetPeople sets the values of the People field.  They are stored only as
+// entries in XPKeywords, so this changes only those entries, leaving the
+// keywords and places alone.
+func (p *Provider) SetPeople(values []string) error {
+	var hvs = make([]metadata.HierValue, len(values))
+	for i := range values {
+		hvs[i] = metadata.HierValue{values[i]}
+	}
+	p.setKind(kindPerson, hvs)
+	return nil
+}
+
+// SetPlaces sets the values of the Places field.  They are stored only as
+// entries in XPKeywords, by the last component of each, so this changes only
+// those entries, leaving the keywords and people alone.
+func (p *Provider) SetPlaces(values []metadata.HierValue) error {
+	p.setKind(kindPlace, values)
+	return nil
+}
+
+// setKind replaces the entries of the specified kind in XPKeywords with the
+// last components of the supplied values, keeping the entries of other kinds.
+func (p *Provider) setKind(kind int, values []metadata.HierValue) {
+	var (
+		seen  = make(map[string]bool)
+		vlist []string
+	)
+	for _, kw := range p.keywords {
+		if p.kinds[kw] != kind && !seen[kw] {
+			seen[kw] = true
+			vlist = append(vlist, kw)
+		}
+	}
+	for _, hv := range values {
+		kw := hv[len(hv)-1]
+		p.kinds[kw] = kind
+		if !seen[kw] {
+			seen[kw] = true
+			vlist = append(vlist, kw)
+		}
+	}
+	p.keywords = splitXPList(p.setTag(tagXPKeywords, strings.Join(vlist, ";")))
+}
+
+// Title returns the value of the Title field.
+func (p *Provider) Title() (value string) { return p.title }
+
+// TitleTags returns a list of tag names for the Title field, and a parallel
+// list of values held by those tags.
+func (p *Provider) TitleTags() (tags []string, values [][]string) {
+	if !p.hasTag(tagXPTitle) {
+		return nil, nil
+	}
+	return []string{"IFD0 XPTitle"}, [][]string{{p.title}}
+}
+
+// SetTitle sets the value of the Title field.
+func (p *Provider) SetTitle(value string) error {
+	p.title = p.setTag(tagXPTitle, value)
+	return nil
+}
@@ -0,0 +1,47 @@
+package xp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rothskeller/photo-tools/metadata"
+	"github.com/rothskeller/photo-tools/metadata/containers/tiff"
+)
+
+// others is a stand-in for the other providers of a file.
+type others struct{ metadata.BaseProvider }
+
+func (others) ProviderName() string { return "others" }
+func (others) People() []string     { return []string{"Jane Doe"} }
+func (others) Places() []metadata.HierValue {
+	return []metadata.HierValue{{"France", "Paris"}}
+}
+
+func TestKeywords(t *testing.T) {
+	var tl tiff.TIFF
+
+	ifd := tl.IFD0()
+	ifd.AddTag(tagXPKeywords, 1).SetUCS2("Jane Doe;Paris;beach;dog")
+	p, err := New(ifd, others{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.Keywords(), []metadata.HierValue{{"beach"}, {"dog"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keywords: got %v, want %v", got, want)
+	}
+	if err = p.SetKeywords([]metadata.HierValue{{"beach"}, {"Nature", "sunset"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ifd.Tag(tagXPKeywords).AsUCS2(); got != "Jane Doe;Paris;beach;sunset" {
+		t.Errorf("SetKeywords: got %q", got)
+	}
+	if err = p.SetPeople([]string{"John Smith"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ifd.Tag(tagXPKeywords).AsUCS2(); got != "Paris;beach;sunset;John Smith" {
+		t.Errorf("SetPeople: got %q", got)
+	}
+	if got, want := p.Keywords(), []metadata.HierValue{{"beach"}, {"sunset"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keywords: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package metadata
+
+// Windows stores ratings as percentages, in the MicrosoftPhoto:Rating XMP tag
+// and the EXIF RatingPercent tag.  These functions convert between those
+// percentages and star ratings the same way Windows Explorer does.
+
+// RatingToPercent converts a star rating (1 to 5) to a percentage.  It returns
+// zero for unrated or rejected media, which Windows can't represent.
+func RatingToPercent(rating int) int {
+	switch rating {
+	case 1:
+		return 1
+	case 2:
+		return 25
+	case 3:
+		return 50
+	case 4:
+		return 75
+	case 5:
+		return 99
+	default:
+		return 0
+	}
+}
+
+// PercentToRating converts a percentage to a star rating.
+func PercentToRating(percent int) int {
+	switch {
+	case percent <= 0:
+		return 0
+	case percent < 13:
+		return 1
+	case percent < 38:
+		return 2
+	case percent < 63:
+		return 3
+	case percent < 88:
+		return 4
+	default:
+		return 5
+	}
+}
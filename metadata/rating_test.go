@@ -0,0 +1,20 @@
+package metadata
+
+import "testing"
+
+func TestRatingPercent(t *testing.T) {
+	for rating := 1; rating <= 5; rating++ {
+		if got := PercentToRating(RatingToPercent(rating)); got != rating {
+			t.Errorf("rating %d round trips to %d", rating, got)
+		}
+	}
+	if got := RatingToPercent(-1); got != 0 {
+		t.Errorf("rejected rating converts to %d%%", got)
+	}
+	if got := PercentToRating(0); got != 0 {
+		t.Errorf("0%% converts to %d stars", got)
+	}
+	if got := PercentToRating(100); got != 5 {
+		t.Errorf("100%% converts to %d stars", got)
+	}
+}
@@ -0,0 +1,221 @@
+// Package timezone determines the time zone in effect at a location, without
+// needing any online service.  It uses an embedded, simplified time zone
+// boundary dataset, and the nautical time zone far out at sea.  Elsewhere, the
+// time zone is unknown.  The time zone rules themselves come from the tz
+// database embedded in the Go runtime, so offsets are correct across daylight
+// saving time changes.
+package timezone
+
+import (
+	"bufio"
+	_ "embed" // for embedded boundary data
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // so that time zone rules are available offline
+
+	"github.com/rothskeller/photo-tools/metadata"
+)
+
+// zonesData contains the simplified boundary polygons.  See the comments at
+// the top of the file for its format.
+//
+//go:embed zones.txt
+var zonesData string
+
+// zoneTabData is the zone.tab file from the tz database, which gives the
+// country and principal location of each time zone.
+//
+//go:embed zone.tab
+var zoneTabData string
+
+// atSeaDistance is the distance, in kilometers, from the principal location of
+// every time zone beyond which a location outside all of the polygons is
+// assumed to be at sea.
+const atSeaDistance = 1500
+
+// earthRadius is the mean radius of the Earth, in kilometers.
+const earthRadius = 6371
+
+// A point is a latitude and longitude, in degrees.
+type point struct{ lat, long float64 }
+
+// A polygon is an area in which one of a set of time zones is observed.
+type polygon struct {
+	zones    []principal
+	vertices []point
+	area     float64
+	min, max point // bounding box
+}
+
+// A principal is the principal location of a time zone.
+type principal struct {
+	zone string
+	loc  point
+}
+
+var (
+	loadOnce   sync.Once
+	polygons   []*polygon
+	principals []principal
+)
+
+// ErrUnknown is returned by Location when the time zone at the location is
+// unknown.
+var ErrUnknown = errors.New("time zone unknown")
+
+// Lookup returns the name of the time zone in effect at the specified
+// location, or an empty string if it is unknown.
+func Lookup(lat, long float64) string {
+	loadOnce.Do(load)
+	pt := point{lat, long}
+	for _, p := range polygons {
+		if p.contains(pt) {
+			return nearest(p.zones, pt)
+		}
+	}
+	for _, pr := range principals {
+		if distance(pt, pr.loc) <= atSeaDistance {
+			return ""
+		}
+	}
+	return nauticalZone(long)
+}
+
+// Location returns the time zone in effect at the specified location.  It
+// returns ErrUnknown if the time zone is unknown.
+func Location(lat, long float64) (*time.Location, error) {
+	zone := Lookup(lat, long)
+	if zone == "" {
+		return nil, ErrUnknown
+	}
+	return time.LoadLocation(zone)
+}
+
+// nearest returns the name of the time zone whose principal location is
+// nearest to the point.
+func nearest(zones []principal, pt point) (best string) {
+	bestDist := math.Inf(1)
+	for _, z := range zones {
+		if d := distance(pt, z.loc); d < bestDist {
+			best, bestDist = z.zone, d
+		}
+	}
+	return best
+}
+
+// nauticalZone returns the name of the nautical time zone at the specified
+// longitude.  Note that the signs of the Etc/GMT zone names are inverted.
+func nauticalZone(long float64) string {
+	switch hours := int(math.Round(long / 15)); {
+	case hours == 0:
+		return "Etc/GMT"
+	case hours > 0:
+		return "Etc/GMT-" + strconv.Itoa(hours)
+	default:
+		return "Etc/GMT+" + strconv.Itoa(-hours)
+	}
+}
+
+// load parses the embedded data.  Problems with the data are programming
+// errors, so they cause panics.
+func load() {
+	var (
+		byZone    = make(map[string]principal)
+		byCountry = make(map[string][]principal)
+	)
+	scan := bufio.NewScanner(strings.NewReader(zoneTabData))
+	for scan.Scan() {
+		fields := strings.Split(scan.Text(), "\t")
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		var gc metadata.GPSCoords
+		if err := gc.ParseISO6709(fields[1]); err != nil || gc.Empty() {
+			panic(fmt.Sprintf("zone.tab: invalid coordinates %q", fields[1]))
+		}
+		pr := principal{fields[2], point{gc.Latitude(), gc.Longitude()}}
+		principals = append(principals, pr)
+		byZone[pr.zone] = pr
+		byCountry[fields[0]] = append(byCountry[fields[0]], pr)
+	}
+	for lnum, line := range strings.Split(zonesData, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 4 {
+			panic(fmt.Sprintf("zones.txt line %d: polygon has too few vertices", lnum+1))
+		}
+		var p polygon
+		for _, z := range strings.Split(fields[0], ",") {
+			if pr, ok := byZone[z]; ok {
+				p.zones = append(p.zones, pr)
+			} else if prs := byCountry[z]; prs != nil {
+				p.zones = append(p.zones, prs...)
+			} else {
+				panic(fmt.Sprintf("zones.txt line %d: %q is not in zone.tab", lnum+1, z))
+			}
+		}
+		p.vertices = make([]point, len(fields)-1)
+		for i, f := range fields[1:] {
+			var err1, err2 error
+			comma := strings.IndexByte(f, ',')
+			if comma < 0 {
+				panic(fmt.Sprintf("zones.txt line %d: invalid vertex %q", lnum+1, f))
+			}
+			p.vertices[i].lat, err1 = strconv.ParseFloat(f[:comma], 64)
+			p.vertices[i].long, err2 = strconv.ParseFloat(f[comma+1:], 64)
+			if err1 != nil || err2 != nil {
+				panic(fmt.Sprintf("zones.txt line %d: invalid vertex %q", lnum+1, f))
+			}
+		}
+		p.computeBounds()
+		polygons = append(polygons, &p)
+	}
+	sort.SliceStable(polygons, func(i, j int) bool { return polygons[i].area < polygons[j].area })
+}
+
+// computeBounds computes the bounding box and area of the polygon.  The area
+// is in square degrees, which is good enough for comparing overlapping
+// polygons.
+func (p *polygon) computeBounds() {
+	p.min, p.max = p.vertices[0], p.vertices[0]
+	for i, v := range p.vertices {
+		p.min.lat, p.min.long = math.Min(p.min.lat, v.lat), math.Min(p.min.long, v.long)
+		p.max.lat, p.max.long = math.Max(p.max.lat, v.lat), math.Max(p.max.long, v.long)
+		w := p.vertices[(i+1)%len(p.vertices)]
+		p.area += v.long*w.lat - w.long*v.lat
+	}
+	p.area = math.Abs(p.area / 2)
+}
+
+// contains returns whether the polygon contains the point.
+func (p *polygon) contains(pt point) (in bool) {
+	if pt.lat < p.min.lat || pt.lat > p.max.lat || pt.long < p.min.long || pt.long > p.max.long {
+		return false
+	}
+	// Count the crossings of a ray running east from the point.
+	for i, j := 0, len(p.vertices)-1; i < len(p.vertices); j, i = i, i+1 {
+		a, b := p.vertices[i], p.vertices[j]
+		if (a.lat > pt.lat) != (b.lat > pt.lat) &&
+			pt.long < a.long+(pt.lat-a.lat)*(b.long-a.long)/(b.lat-a.lat) {
+			in = !in
+		}
+	}
+	return in
+}
+
+// distance returns the great circle distance between two points, in
+// kilometers.
+func distance(a, b point) float64 {
+	const rad = math.Pi / 180
+	dlat, dlong := (b.lat-a.lat)*rad, (b.long-a.long)*rad
+	h := math.Pow(math.Sin(dlat/2), 2) + math.Cos(a.lat*rad)*math.Cos(b.lat*rad)*math.Pow(math.Sin(dlong/2), 2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
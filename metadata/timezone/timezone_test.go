@@ -0,0 +1,139 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name      string
+		lat, long float64
+		zone      string
+	}{
+		{"San Francisco", 37.77, -122.42, "America/Los_Angeles"},
+		{"Spokane", 47.66, -117.43, "America/Los_Angeles"},
+		{"Boise", 43.62, -116.2, "America/Denver"},
+		{"Phoenix", 33.45, -112.07, "America/Phoenix"},
+		{"El Paso", 31.76, -106.49, "America/Denver"},
+		{"Dallas", 32.78, -96.8, "America/Chicago"},
+		{"Evansville", 37.97, -87.57, "America/Chicago"},
+		{"Indianapolis", 39.77, -86.16, "America/New_York"},
+		{"Chattanooga", 35.05, -85.31, "America/New_York"},
+		{"Nashville", 36.16, -86.78, "America/Chicago"},
+		{"Miami", 25.76, -80.19, "America/New_York"},
+		{"Anchorage", 61.22, -149.9, "America/Anchorage"},
+		{"Honolulu", 21.31, -157.86, "Pacific/Honolulu"},
+		{"Victoria", 48.43, -123.37, "America/Vancouver"},
+		{"Calgary", 51.05, -114.07, "America/Edmonton"},
+		{"Cranbrook", 49.51, -115.77, "America/Edmonton"},
+		{"Dawson Creek", 55.76, -120.24, "America/Dawson_Creek"},
+		{"Regina", 50.45, -104.61, "America/Regina"},
+		{"Kenora", 49.77, -94.49, "America/Winnipeg"},
+		{"Montreal", 45.5, -73.57, "America/Toronto"},
+		{"Edmundston", 47.37, -68.33, "America/Halifax"},
+		{"Corner Brook", 48.95, -57.95, "America/St_Johns"},
+		{"Mildura", -34.19, 142.16, "Australia/Melbourne"},
+		{"Broken Hill", -31.95, 141.45, "Australia/Broken_Hill"},
+		{"Uluru", -25.34, 131.04, "Australia/Darwin"},
+		{"Canberra", -35.28, 149.13, "Australia/Sydney"},
+		{"Gold Coast", -28.02, 153.4, "Australia/Brisbane"},
+		{"Santiago de Compostela", 42.88, -8.54, "Europe/Madrid"},
+		{"Porto", 41.15, -8.61, "Europe/Lisbon"},
+		{"Brest", 48.39, -4.49, "Europe/Paris"},
+		{"St Helier", 49.19, -2.11, "Europe/Jersey"},
+		{"Tokyo", 35.68, 139.69, "Asia/Tokyo"},
+		{"London", 51.51, -0.13, "Europe/London"},
+		{"Berlin", 52.52, 13.4, "Europe/Berlin"},
+		{"Helsinki", 60.17, 24.94, "Europe/Helsinki"},
+		{"St. Petersburg", 59.94, 30.31, "Europe/Moscow"},
+		{"Ivangorod", 59.365, 28.23, "Europe/Moscow"},
+		{"Narva", 59.38, 28.18, "Europe/Tallinn"},
+		{"Brest, Belarus", 52.1, 23.73, "Europe/Minsk"},
+		{"Terespol", 52.08, 23.61, "Europe/Warsaw"},
+		{"Kyiv", 50.45, 30.52, "Europe/Kyiv"},
+		{"Istanbul", 41.01, 28.98, "Europe/Istanbul"},
+		{"Samara", 53.2, 50.15, "Europe/Samara"},
+		{"Yekaterinburg", 56.84, 60.6, "Asia/Yekaterinburg"},
+		{"Novosibirsk", 55.03, 82.92, "Asia/Novosibirsk"},
+		{"Irkutsk", 52.29, 104.28, "Asia/Irkutsk"},
+		{"Vladivostok", 43.12, 131.89, "Asia/Vladivostok"},
+		{"Almaty", 43.24, 76.89, "Asia/Almaty"},
+		{"Bishkek", 42.87, 74.59, "Asia/Bishkek"},
+		{"Kashgar", 39.47, 75.99, "Asia/Urumqi"},
+		{"Lhasa", 29.65, 91.1, "Asia/Shanghai"},
+		{"Beijing", 39.9, 116.41, "Asia/Shanghai"},
+		{"Thimphu", 27.47, 89.64, "Asia/Thimphu"},
+		{"Darjeeling", 27.04, 88.26, "Asia/Kolkata"},
+		{"Delhi", 28.61, 77.21, "Asia/Kolkata"},
+		{"Kathmandu", 27.72, 85.32, "Asia/Kathmandu"},
+		{"Seoul", 37.57, 126.98, "Asia/Seoul"},
+		{"Nairobi", -1.29, 36.82, ""},
+		{"Mexico City", 19.43, -99.13, ""},
+		{"mid-Pacific", 30, -140, "Etc/GMT+9"},
+		{"Indian Ocean", -30, 80, "Etc/GMT-5"},
+	}
+	for _, test := range tests {
+		if zone := Lookup(test.lat, test.long); zone != test.zone {
+			t.Errorf("%s: got %s, expected %s", test.name, zone, test.zone)
+		}
+	}
+}
+
+func TestLocation(t *testing.T) {
+	loc, err := Location(37.77, -122.42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, offset := time.Date(2022, 1, 15, 12, 0, 0, 0, loc).Zone(); offset != -8*3600 {
+		t.Errorf("winter offset: got %d", offset)
+	}
+	if _, offset := time.Date(2022, 7, 15, 12, 0, 0, 0, loc).Zone(); offset != -7*3600 {
+		t.Errorf("summer offset: got %d", offset)
+	}
+}
+
+func TestLocationUnknown(t *testing.T) {
+	if _, err := Location(-1.29, 36.82); err != ErrUnknown {
+		t.Errorf("got %v, expected ErrUnknown", err)
+	}
+}
+
+// TestPrincipals checks that every principal location in zone.tab either has
+// an unknown time zone or one with the same offsets as its own.
+func TestPrincipals(t *testing.T) {
+	loadOnce.Do(load)
+	for _, pr := range principals {
+		zone := Lookup(pr.loc.lat, pr.loc.long)
+		if zone == "" || zone == pr.zone {
+			continue
+		}
+		want, _ := time.LoadLocation(pr.zone)
+		got, _ := time.LoadLocation(zone)
+		for _, month := range []time.Month{time.January, time.July} {
+			date := time.Date(2025, month, 15, 12, 0, 0, 0, time.UTC)
+			_, wantOffset := date.In(want).Zone()
+			_, gotOffset := date.In(got).Zone()
+			if gotOffset != wantOffset {
+				t.Errorf("%s: got %s", pr.zone, zone)
+				break
+			}
+		}
+	}
+}
+
+func TestAllZonesLoad(t *testing.T) {
+	loadOnce.Do(load)
+	for _, p := range polygons {
+		for _, z := range p.zones {
+			if _, err := time.LoadLocation(z.zone); err != nil {
+				t.Errorf("zones.txt: %s", err)
+			}
+		}
+	}
+	for _, pr := range principals {
+		if _, err := time.LoadLocation(pr.zone); err != nil {
+			t.Errorf("zone.tab: %s", err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package track
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// gpxPoint is the structure of a track, route, or waypoint in a GPX file.
+type gpxPoint struct {
+	Lat  float64  `xml:"lat,attr"`
+	Lon  float64  `xml:"lon,attr"`
+	Ele  *float64 `xml:"ele"`
+	Time string   `xml:"time"`
+}
+
+// ReadGPX reads a track log in GPX format.  All track points, route points,
+// and waypoints that have times are included.
+func ReadGPX(r io.Reader) (t Track, err error) {
+	var dec = xml.NewDecoder(r)
+
+	for {
+		var (
+			tok xml.Token
+			gp  gpxPoint
+			p   Point
+		)
+		if tok, err = dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("GPX: %s", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || (se.Name.Local != "trkpt" && se.Name.Local != "rtept" && se.Name.Local != "wpt") {
+			continue
+		}
+		if err = dec.DecodeElement(&gp, &se); err != nil {
+			return nil, fmt.Errorf("GPX: %s", err)
+		}
+		if gp.Time == "" {
+			continue
+		}
+		if p.Time, err = time.Parse(time.RFC3339, gp.Time); err != nil {
+			return nil, fmt.Errorf("GPX: invalid time %q", gp.Time)
+		}
+		if !validCoords(gp.Lat, gp.Lon) {
+			return nil, fmt.Errorf("GPX: invalid coordinates %f, %f", gp.Lat, gp.Lon)
+		}
+		p.Latitude, p.Longitude = gp.Lat, gp.Lon
+		if gp.Ele != nil {
+			p.Altitude, p.HasAltitude = *gp.Ele, true
+		}
+		t = append(t, p)
+	}
+	t.sort()
+	return t, nil
+}
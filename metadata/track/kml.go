@@ -0,0 +1,93 @@
+package track
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kmlPlacemark is the structure of a placemark in a KML file.  It may hold a
+// single timestamped point, or one or more gx:Track elements (as written by
+// Google Earth and most GPS logging apps).
+type kmlPlacemark struct {
+	When        string     `xml:"TimeStamp>when"`
+	Coordinates string     `xml:"Point>coordinates"`
+	Tracks      []kmlTrack `xml:"Track"`
+	MultiTracks []kmlTrack `xml:"MultiTrack>Track"`
+}
+
+// kmlTrack is the structure of a gx:Track element:  parallel lists of times
+// and coordinates.
+type kmlTrack struct {
+	When  []string `xml:"when"`
+	Coord []string `xml:"coord"`
+}
+
+// ReadKML reads a track log in KML format.  All gx:Track points, and all
+// placemark points that have timestamps, are included.
+func ReadKML(r io.Reader) (t Track, err error) {
+	var dec = xml.NewDecoder(r)
+
+	for {
+		var (
+			tok xml.Token
+			pm  kmlPlacemark
+			p   Point
+		)
+		if tok, err = dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("KML: %s", err)
+		}
+		if se, ok := tok.(xml.StartElement); !ok || se.Name.Local != "Placemark" {
+			continue
+		} else if err = dec.DecodeElement(&pm, &se); err != nil {
+			return nil, fmt.Errorf("KML: %s", err)
+		}
+		for _, kt := range append(pm.Tracks, pm.MultiTracks...) {
+			if len(kt.When) != len(kt.Coord) {
+				return nil, fmt.Errorf("KML: track has %d times but %d coordinates", len(kt.When), len(kt.Coord))
+			}
+			for i := range kt.When {
+				if p, err = parseKMLPoint(kt.When[i], strings.Fields(kt.Coord[i])); err != nil {
+					return nil, err
+				}
+				t = append(t, p)
+			}
+		}
+		if pm.When != "" && pm.Coordinates != "" {
+			if p, err = parseKMLPoint(pm.When, strings.Split(strings.TrimSpace(pm.Coordinates), ",")); err != nil {
+				return nil, err
+			}
+			t = append(t, p)
+		}
+	}
+	t.sort()
+	return t, nil
+}
+
+// parseKMLPoint parses a KML time and a longitude, latitude, and optional
+// altitude into a Point.
+func parseKMLPoint(when string, coords []string) (p Point, err error) {
+	if p.Time, err = time.Parse(time.RFC3339, strings.TrimSpace(when)); err != nil {
+		return Point{}, fmt.Errorf("KML: invalid time %q", when)
+	}
+	if len(coords) < 2 || len(coords) > 3 {
+		return Point{}, fmt.Errorf("KML: invalid coordinates %q", strings.Join(coords, ","))
+	}
+	p.Longitude, err = strconv.ParseFloat(strings.TrimSpace(coords[0]), 64)
+	if err == nil {
+		p.Latitude, err = strconv.ParseFloat(strings.TrimSpace(coords[1]), 64)
+	}
+	if err == nil && len(coords) == 3 {
+		p.Altitude, err = strconv.ParseFloat(strings.TrimSpace(coords[2]), 64)
+		p.HasAltitude = true
+	}
+	if err != nil || !validCoords(p.Latitude, p.Longitude) {
+		return Point{}, fmt.Errorf("KML: invalid coordinates %q", strings.Join(coords, ","))
+	}
+	return p, nil
+}
@@ -0,0 +1,157 @@
+package track
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReadNMEA reads a track log of NMEA 0183 sentences.  Positions come from the
+// RMC (recommended minimum) sentences, which include the date, and altitudes
+// from the GGA (fix data) sentences, which don't.  Sentences with bad
+// checksums or without a valid fix are ignored.
+func ReadNMEA(r io.Reader) (t Track, err error) {
+	var (
+		scan = bufio.NewScanner(r)
+		date time.Time // date of the most recent RMC sentence
+	)
+	for scan.Scan() {
+		var (
+			fields []string
+			p      Point
+			tod    time.Duration
+			ok     bool
+		)
+		if fields, ok = nmeaFields(scan.Text()); !ok || len(fields[0]) != 5 {
+			continue
+		}
+		switch fields[0][2:] {
+		case "RMC":
+			// time, status, lat, N/S, long, E/W, speed, course, date, ...
+			if len(fields) < 10 || fields[2] != "A" {
+				continue
+			}
+			if tod, ok = nmeaTime(fields[1]); !ok {
+				continue
+			}
+			if p.Time, err = time.Parse("020106", fields[9]); err != nil {
+				continue
+			}
+			date = p.Time
+			p.Time = date.Add(tod)
+			if p.Latitude, p.Longitude, ok = nmeaCoords(fields[3:7]); !ok {
+				continue
+			}
+		case "GGA":
+			// time, lat, N/S, long, E/W, quality, satellites, HDOP,
+			// altitude, M, ...
+			if len(fields) < 11 || fields[6] == "" || fields[6] == "0" || date.IsZero() {
+				continue
+			}
+			if tod, ok = nmeaTime(fields[1]); !ok {
+				continue
+			}
+			p.Time = date.Add(tod)
+			// Handle a GGA sentence that comes after midnight but
+			// before the first RMC sentence of the new day.
+			if len(t) != 0 && t[len(t)-1].Time.Sub(p.Time) > 12*time.Hour {
+				p.Time = p.Time.AddDate(0, 0, 1)
+			}
+			if p.Latitude, p.Longitude, ok = nmeaCoords(fields[2:6]); !ok {
+				continue
+			}
+			if alt, err := strconv.ParseFloat(fields[9], 64); err == nil && fields[10] == "M" {
+				p.Altitude, p.HasAltitude = alt, true
+			}
+		default:
+			continue
+		}
+		// RMC and GGA sentences for the same fix are merged.
+		if len(t) != 0 && t[len(t)-1].Time.Equal(p.Time) {
+			if p.HasAltitude {
+				t[len(t)-1].Altitude, t[len(t)-1].HasAltitude = p.Altitude, true
+			}
+			continue
+		}
+		t = append(t, p)
+	}
+	if err = scan.Err(); err != nil {
+		return nil, fmt.Errorf("NMEA: %s", err)
+	}
+	t.sort()
+	return t, nil
+}
+
+// nmeaFields verifies the checksum of an NMEA sentence, if it has one, and
+// splits it into fields.  The first field is the talker and sentence type
+// (e.g. "GPRMC").
+func nmeaFields(line string) (fields []string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "$") {
+		return nil, false
+	}
+	line = line[1:]
+	if star := strings.IndexByte(line, '*'); star >= 0 {
+		var sum byte
+
+		want, err := strconv.ParseUint(line[star+1:], 16, 8)
+		if err != nil {
+			return nil, false
+		}
+		line = line[:star]
+		for i := 0; i < len(line); i++ {
+			sum ^= line[i]
+		}
+		if sum != byte(want) {
+			return nil, false
+		}
+	}
+	return strings.Split(line, ","), true
+}
+
+// nmeaTime parses an NMEA time of day (hhmmss or hhmmss.sss).
+func nmeaTime(s string) (tod time.Duration, ok bool) {
+	if len(s) < 6 {
+		return 0, false
+	}
+	hh, err1 := strconv.Atoi(s[0:2])
+	mm, err2 := strconv.Atoi(s[2:4])
+	ss, err3 := strconv.ParseFloat(s[4:], 64)
+	if err1 != nil || err2 != nil || err3 != nil || hh > 23 || mm > 59 || ss >= 61 {
+		return 0, false
+	}
+	return time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute + time.Duration(ss*float64(time.Second)), true
+}
+
+// nmeaCoords parses an NMEA latitude (ddmm.mmmm), hemisphere, longitude
+// (dddmm.mmmm), and hemisphere into signed degrees.
+func nmeaCoords(f []string) (lat, long float64, ok bool) {
+	var err1, err2 error
+
+	lat, err1 = strconv.ParseFloat(f[0], 64)
+	long, err2 = strconv.ParseFloat(f[2], 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	lat = math.Floor(lat/100) + math.Mod(lat, 100)/60
+	long = math.Floor(long/100) + math.Mod(long, 100)/60
+	switch f[1] {
+	case "N":
+	case "S":
+		lat = -lat
+	default:
+		return 0, 0, false
+	}
+	switch f[3] {
+	case "E":
+	case "W":
+		long = -long
+	default:
+		return 0, 0, false
+	}
+	return lat, long, validCoords(lat, long)
+}
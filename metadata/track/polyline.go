@@ -0,0 +1,51 @@
+package track
+
+import (
+	"errors"
+)
+
+// ErrPolyline is the error returned when an encoded polyline is invalid.
+var ErrPolyline = errors.New("invalid encoded polyline")
+
+// DecodePolyline decodes a polyline in the encoding used by the Google Maps
+// APIs:  a sequence of latitude and longitude pairs, each after the first
+// being a delta from the one before, in units of 1e-5 degrees, encoded as
+// variable-length groups of 5 bits in printable characters.  The resulting
+// points have no times or altitudes.
+func DecodePolyline(s string) (points []Point, err error) {
+	var (
+		lat, long int32
+		value     int32
+		shift     uint
+		islong    bool
+	)
+	for i := 0; i < len(s); i++ {
+		b := int32(s[i]) - 63
+		if b < 0 || b >= 64 || shift > 30 {
+			return nil, ErrPolyline
+		}
+		value |= (b & 0x1F) << shift
+		shift += 5
+		if b&0x20 != 0 {
+			continue // more groups follow
+		}
+		// The value is zigzag encoded:  the low bit is the sign.
+		if value&1 != 0 {
+			value = ^(value >> 1)
+		} else {
+			value >>= 1
+		}
+		if islong {
+			long += value
+			points = append(points, Point{Latitude: float64(lat) / 1e5, Longitude: float64(long) / 1e5})
+		} else {
+			lat += value
+		}
+		islong = !islong
+		value, shift = 0, 0
+	}
+	if shift != 0 || islong {
+		return nil, ErrPolyline
+	}
+	return points, nil
+}
@@ -0,0 +1,250 @@
+package track
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// A Visit is a stay at a known place, from the semantic location history in a
+// Google Takeout export.
+type Visit struct {
+	Start   time.Time
+	End     time.Time
+	Name    string
+	Address string
+}
+
+// Visits is a list of visits, in chronological order.
+type Visits []Visit
+
+// At returns the visit in progress at the specified time, if any.
+func (vs Visits) At(at time.Time) (v Visit, ok bool) {
+	// Find the last visit that starts at or before the specified time.
+	i := sort.Search(len(vs), func(i int) bool { return vs[i].Start.After(at) }) - 1
+	if i < 0 || vs[i].End.Before(at) {
+		return Visit{}, false
+	}
+	return vs[i], true
+}
+
+// takeoutLocation is the structure of a location in a Google Takeout location
+// history.  The field names vary depending on where it appears.
+type takeoutLocation struct {
+	LatitudeE7     *int64   `json:"latitudeE7"`
+	LongitudeE7    *int64   `json:"longitudeE7"`
+	LatE7          *int64   `json:"latE7"`
+	LngE7          *int64   `json:"lngE7"`
+	Accuracy       float64  `json:"accuracy"`
+	AccuracyMeters float64  `json:"accuracyMeters"`
+	Altitude       *float64 `json:"altitude"`
+	Timestamp      string   `json:"timestamp"`
+	TimestampMs    string   `json:"timestampMs"`
+	Name           string   `json:"name"`
+	Address        string   `json:"address"`
+}
+
+// takeoutDuration is the structure of the time span of a timeline object in a
+// Google Takeout semantic location history.
+type takeoutDuration struct {
+	StartTimestamp   string `json:"startTimestamp"`
+	EndTimestamp     string `json:"endTimestamp"`
+	StartTimestampMs string `json:"startTimestampMs"`
+	EndTimestampMs   string `json:"endTimestampMs"`
+}
+
+// takeoutObject is the structure of a timeline object in a Google Takeout
+// semantic location history.
+type takeoutObject struct {
+	ActivitySegment *struct {
+		StartLocation     takeoutLocation `json:"startLocation"`
+		EndLocation       takeoutLocation `json:"endLocation"`
+		Duration          takeoutDuration `json:"duration"`
+		SimplifiedRawPath struct {
+			Points []takeoutLocation `json:"points"`
+		} `json:"simplifiedRawPath"`
+	} `json:"activitySegment"`
+	PlaceVisit *struct {
+		Location    takeoutLocation `json:"location"`
+		Duration    takeoutDuration `json:"duration"`
+		CenterLatE7 *int64          `json:"centerLatE7"`
+		CenterLngE7 *int64          `json:"centerLngE7"`
+	} `json:"placeVisit"`
+}
+
+// ReadTakeout reads a location history from a Google Takeout export:  either
+// the raw location history (Records.json, or Location History.json in older
+// exports), or one of the monthly files of the semantic location history.
+// It returns the recorded positions, with their accuracies, and the place
+// visits (semantic location history only).
+func ReadTakeout(r io.Reader) (t Track, visits Visits, err error) {
+	var (
+		dec = json.NewDecoder(r)
+		tok json.Token
+	)
+	if tok, err = dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, errors.New("Takeout: not a location history")
+	}
+	for dec.More() {
+		if tok, err = dec.Token(); err != nil {
+			return nil, nil, fmt.Errorf("Takeout: %s", err)
+		}
+		switch tok {
+		case "locations":
+			err = takeoutArray(dec, func() error {
+				var loc takeoutLocation
+
+				if err := dec.Decode(&loc); err != nil {
+					return err
+				}
+				if p, ok := loc.point(""); ok {
+					t = append(t, p)
+				}
+				return nil
+			})
+		case "timelineObjects":
+			err = takeoutArray(dec, func() error {
+				var obj takeoutObject
+
+				if err := dec.Decode(&obj); err != nil {
+					return err
+				}
+				t, visits = obj.add(t, visits)
+				return nil
+			})
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("Takeout: %s", err)
+		}
+	}
+	t.sort()
+	sort.SliceStable(visits, func(i, j int) bool { return visits[i].Start.Before(visits[j].Start) })
+	return t, visits, nil
+}
+
+// takeoutArray calls the element function for each element of a JSON array.
+func takeoutArray(dec *json.Decoder, element func() error) (err error) {
+	var tok json.Token
+
+	if tok, err = dec.Token(); err != nil {
+		return err
+	}
+	if tok != json.Delim('[') {
+		return errors.New("expected array")
+	}
+	for dec.More() {
+		if err = element(); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token() // closing bracket
+	return err
+}
+
+// add adds the positions recorded in a timeline object to the track, and the
+// place visit (if it is one) to the visits.
+func (obj *takeoutObject) add(t Track, visits Visits) (Track, Visits) {
+	if as := obj.ActivitySegment; as != nil {
+		if p, ok := as.StartLocation.point(as.Duration.start()); ok {
+			t = append(t, p)
+		}
+		if p, ok := as.EndLocation.point(as.Duration.end()); ok {
+			t = append(t, p)
+		}
+		for _, loc := range as.SimplifiedRawPath.Points {
+			if p, ok := loc.point(""); ok {
+				t = append(t, p)
+			}
+		}
+	}
+	if pv := obj.PlaceVisit; pv != nil {
+		var v Visit
+
+		loc := pv.Location
+		if pv.CenterLatE7 != nil && pv.CenterLngE7 != nil {
+			loc.LatitudeE7, loc.LongitudeE7 = pv.CenterLatE7, pv.CenterLngE7
+		}
+		if p, ok := loc.point(pv.Duration.start()); ok {
+			t = append(t, p)
+			v.Start = p.Time
+		}
+		if p, ok := loc.point(pv.Duration.end()); ok {
+			t = append(t, p)
+			v.End = p.Time
+		}
+		if !v.Start.IsZero() && !v.End.IsZero() && (pv.Location.Name != "" || pv.Location.Address != "") {
+			v.Name, v.Address = pv.Location.Name, pv.Location.Address
+			visits = append(visits, v)
+		}
+	}
+	return t, visits
+}
+
+// point returns the track point for the location.  If the location has no
+// timestamp of its own, the supplied one is used.  It returns false if the
+// location has no valid coordinates or timestamp.
+func (loc *takeoutLocation) point(timestamp string) (p Point, ok bool) {
+	var err error
+
+	lat, long := loc.LatitudeE7, loc.LongitudeE7
+	if lat == nil || long == nil {
+		lat, long = loc.LatE7, loc.LngE7
+	}
+	if lat == nil || long == nil {
+		return Point{}, false
+	}
+	p.Latitude, p.Longitude = float64(*lat)/1e7, float64(*long)/1e7
+	if !validCoords(p.Latitude, p.Longitude) {
+		return Point{}, false
+	}
+	switch {
+	case loc.Timestamp != "":
+		timestamp = loc.Timestamp
+	case loc.TimestampMs != "":
+		timestamp = loc.TimestampMs
+	}
+	if p.Time, err = takeoutTime(timestamp); err != nil {
+		return Point{}, false
+	}
+	if loc.Altitude != nil {
+		p.Altitude, p.HasAltitude = *loc.Altitude, true
+	}
+	p.Accuracy = loc.Accuracy
+	if p.Accuracy == 0 {
+		p.Accuracy = loc.AccuracyMeters
+	}
+	return p, true
+}
+
+// start returns the starting timestamp of the duration.
+func (d takeoutDuration) start() string {
+	if d.StartTimestamp != "" {
+		return d.StartTimestamp
+	}
+	return d.StartTimestampMs
+}
+
+// end returns the ending timestamp of the duration.
+func (d takeoutDuration) end() string {
+	if d.EndTimestamp != "" {
+		return d.EndTimestamp
+	}
+	return d.EndTimestampMs
+}
+
+// takeoutTime parses a timestamp in a Google Takeout location history:  either
+// an RFC 3339 time or (in older exports) a count of milliseconds since the
+// epoch.
+func takeoutTime(s string) (time.Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,185 @@
+// Package track reads GPS track logs in GPX, KML, and NMEA formats, and
+// location histories from Google Takeout, and computes the position at a given
+// time by interpolating between their points.  It also decodes Google encoded
+// polylines.
+package track
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// A Point is a single point on a track.  Points decoded from polylines have a
+// zero Time.  Accuracy is the radius of uncertainty of the position, in
+// meters, or zero if it is unknown.
+type Point struct {
+	Time        time.Time
+	Latitude    float64
+	Longitude   float64
+	Altitude    float64 // meters
+	HasAltitude bool
+	Accuracy    float64
+}
+
+// A Track is a list of track points, in chronological order.
+type Track []Point
+
+// ErrUnknownFormat is the error returned when a track log is not in any of
+// the supported formats.
+var ErrUnknownFormat = errors.New("not a GPX, KML, NMEA, or Google Takeout location log")
+
+// ReadFile reads the track log in the named file.
+func ReadFile(name string) (t Track, err error) {
+	var by []byte
+
+	if by, err = os.ReadFile(name); err != nil {
+		return nil, err
+	}
+	return Read(bytes.NewReader(by))
+}
+
+// Read reads a track log, determining its format from its contents.  It
+// returns ErrUnknownFormat if the format isn't recognized.  Place visits in a
+// Google Takeout location history are ignored; use ReadTakeout to get them.
+func Read(r io.Reader) (t Track, err error) {
+	var by []byte
+
+	if by, err = io.ReadAll(r); err != nil {
+		return nil, err
+	}
+	switch start := bytes.TrimSpace(by); {
+	case len(start) != 0 && start[0] == '$':
+		t, err = ReadNMEA(bytes.NewReader(by))
+	case bytes.Contains(start, []byte("<gpx")):
+		t, err = ReadGPX(bytes.NewReader(by))
+	case bytes.Contains(start, []byte("<kml")):
+		t, err = ReadKML(bytes.NewReader(by))
+	case len(start) != 0 && start[0] == '{':
+		t, _, err = ReadTakeout(bytes.NewReader(by))
+	default:
+		return nil, ErrUnknownFormat
+	}
+	return t, err
+}
+
+// Merge returns a track containing the points of all of the supplied tracks,
+// in chronological order.
+func Merge(tracks ...Track) (t Track) {
+	for _, tr := range tracks {
+		t = append(t, tr...)
+	}
+	t.sort()
+	return t
+}
+
+// sort puts the points of the track in chronological order, preserving the
+// order of points with the same time.
+func (t Track) sort() {
+	sort.SliceStable(t, func(i, j int) bool { return t[i].Time.Before(t[j].Time) })
+}
+
+// FilterAccuracy returns a track containing only those points of t whose
+// accuracy is known to be no worse than maxAccuracy meters, or is unknown.
+func (t Track) FilterAccuracy(maxAccuracy float64) (ft Track) {
+	ft = make(Track, 0, len(t))
+	for _, p := range t {
+		if p.Accuracy <= maxAccuracy {
+			ft = append(ft, p)
+		}
+	}
+	return ft
+}
+
+// Nearest returns the point on the track nearest in time to the specified
+// time, if it is no more than maxGap away.  Unlike Position, it never
+// interpolates; it is suited to sparse location histories, whose points are
+// too far apart for linear motion between them to be a reasonable guess.
+func (t Track) Nearest(at time.Time, maxGap time.Duration) (p Point, ok bool) {
+	// Find the first point at or after the requested time.
+	i := sort.Search(len(t), func(i int) bool { return !t[i].Time.Before(at) })
+	switch {
+	case len(t) == 0:
+		return Point{}, false
+	case i == 0:
+		return nearest(t[0], at, maxGap)
+	case i == len(t):
+		return nearest(t[i-1], at, maxGap)
+	case at.Sub(t[i-1].Time) < t[i].Time.Sub(at):
+		return nearest(t[i-1], at, maxGap)
+	default:
+		return nearest(t[i], at, maxGap)
+	}
+}
+
+// Position returns the position on the track at the specified time.  If the
+// time falls between two points no more than maxGap apart, the position is
+// interpolated between them.  Otherwise, the nearest point is used, if it is
+// no more than maxGap away.  Position returns false if there is no such point.
+func (t Track) Position(at time.Time, maxGap time.Duration) (p Point, ok bool) {
+	// Find the first point at or after the requested time.
+	i := sort.Search(len(t), func(i int) bool { return !t[i].Time.Before(at) })
+	switch {
+	case len(t) == 0:
+		return Point{}, false
+	case i < len(t) && t[i].Time.Equal(at):
+		return t[i], true
+	case i == 0:
+		return nearest(t[0], at, maxGap)
+	case i == len(t):
+		return nearest(t[i-1], at, maxGap)
+	}
+	before, after := t[i-1], t[i]
+	if span := after.Time.Sub(before.Time); span > maxGap {
+		if at.Sub(before.Time) <= after.Time.Sub(at) {
+			return nearest(before, at, maxGap)
+		}
+		return nearest(after, at, maxGap)
+	}
+	return interpolate(before, after, at), true
+}
+
+// nearest returns the specified point, if it is no more than maxGap away from
+// the specified time.
+func nearest(p Point, at time.Time, maxGap time.Duration) (Point, bool) {
+	if gap := p.Time.Sub(at); gap > maxGap || gap < -maxGap {
+		return Point{}, false
+	}
+	p.Time = at
+	return p, true
+}
+
+// interpolate returns the position at the specified time, assuming linear
+// motion between the two points.
+func interpolate(a, b Point, at time.Time) (p Point) {
+	frac := float64(at.Sub(a.Time)) / float64(b.Time.Sub(a.Time))
+	dlong := b.Longitude - a.Longitude
+	// Don't go the long way around when crossing the antimeridian.
+	if dlong > 180 {
+		dlong -= 360
+	} else if dlong < -180 {
+		dlong += 360
+	}
+	p.Time = at
+	p.Latitude = a.Latitude + frac*(b.Latitude-a.Latitude)
+	p.Longitude = a.Longitude + frac*dlong
+	if p.Longitude > 180 {
+		p.Longitude -= 360
+	} else if p.Longitude < -180 {
+		p.Longitude += 360
+	}
+	if a.HasAltitude && b.HasAltitude {
+		p.Altitude = a.Altitude + frac*(b.Altitude-a.Altitude)
+		p.HasAltitude = true
+	}
+	return p
+}
+
+// validCoords returns whether the latitude and longitude are in range.
+func validCoords(lat, long float64) bool {
+	return !math.IsNaN(lat) && !math.IsNaN(long) && lat >= -90 && lat <= 90 && long >= -180 && long <= 180
+}
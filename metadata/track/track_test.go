@@ -0,0 +1,289 @@
+package track
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <wpt lat="10.0" lon="20.0"><name>no time</name></wpt>
+  <trk><trkseg>
+    <trkpt lat="37.5" lon="-122.25"><ele>12.5</ele><time>2022-06-01T10:00:10Z</time></trkpt>
+    <trkpt lat="37.0" lon="-122.0"><time>2022-06-01T10:00:00Z</time></trkpt>
+  </trkseg></trk>
+</gpx>`
+
+const testKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+<Document>
+  <Placemark>
+    <gx:Track>
+      <when>2022-06-01T10:00:00Z</when>
+      <when>2022-06-01T10:00:10-07:00</when>
+      <gx:coord>-122.0 37.0 100</gx:coord>
+      <gx:coord>-122.25 37.5 110</gx:coord>
+    </gx:Track>
+  </Placemark>
+  <Placemark>
+    <TimeStamp><when>2022-06-01T09:00:00Z</when></TimeStamp>
+    <Point><coordinates>-121.5,36.5</coordinates></Point>
+  </Placemark>
+</Document>
+</kml>`
+
+const testNMEA = `$GPGSV,3,1,11,03,03,111,00,04,15,270,00,06,01,010,00,13,06,292,00*74
+$GPRMC,123519,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*6A
+$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47
+$GPRMC,123520,V,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W
+$GPRMC,123521,A,4807.038,N,01131.000,E,022.4,084.4,230394,003.1,W*00
+$GNRMC,123522.50,A,3330.000,S,07030.000,W,0,0,230394,,
+`
+
+func TestReadGPX(t *testing.T) {
+	tr, err := Read(strings.NewReader(testGPX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr) != 2 {
+		t.Fatalf("got %d points, expected 2", len(tr))
+	}
+	if tr[0].Latitude != 37.0 || tr[0].Longitude != -122.0 || tr[0].HasAltitude {
+		t.Errorf("point 0: got %+v", tr[0])
+	}
+	if tr[1].Altitude != 12.5 || !tr[1].HasAltitude || !tr[1].Time.Equal(time.Date(2022, 6, 1, 10, 0, 10, 0, time.UTC)) {
+		t.Errorf("point 1: got %+v", tr[1])
+	}
+}
+
+func TestReadKML(t *testing.T) {
+	tr, err := Read(strings.NewReader(testKML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr) != 3 {
+		t.Fatalf("got %d points, expected 3", len(tr))
+	}
+	if tr[0].Latitude != 36.5 || tr[0].Longitude != -121.5 || tr[0].HasAltitude {
+		t.Errorf("point 0: got %+v", tr[0])
+	}
+	if tr[1].Latitude != 37.0 || tr[1].Altitude != 100 || !tr[1].HasAltitude {
+		t.Errorf("point 1: got %+v", tr[1])
+	}
+	if !tr[2].Time.Equal(time.Date(2022, 6, 1, 17, 0, 10, 0, time.UTC)) {
+		t.Errorf("point 2: got %+v", tr[2])
+	}
+}
+
+func TestReadNMEA(t *testing.T) {
+	tr, err := Read(strings.NewReader(testNMEA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr) != 2 {
+		t.Fatalf("got %d points, expected 2: %+v", len(tr), tr)
+	}
+	if !tr[0].Time.Equal(time.Date(1994, 3, 23, 12, 35, 19, 0, time.UTC)) {
+		t.Errorf("point 0 time: got %s", tr[0].Time)
+	}
+	if math.Abs(tr[0].Latitude-48.1173) > 1e-6 || math.Abs(tr[0].Longitude-11.516667) > 1e-6 {
+		t.Errorf("point 0 coords: got %f, %f", tr[0].Latitude, tr[0].Longitude)
+	}
+	if !tr[0].HasAltitude || tr[0].Altitude != 545.4 {
+		t.Errorf("point 0 altitude: got %+v", tr[0])
+	}
+	if !tr[1].Time.Equal(time.Date(1994, 3, 23, 12, 35, 22, 5e8, time.UTC)) || tr[1].HasAltitude {
+		t.Errorf("point 1: got %+v", tr[1])
+	}
+	if tr[1].Latitude != -33.5 || tr[1].Longitude != -70.5 {
+		t.Errorf("point 1 coords: got %f, %f", tr[1].Latitude, tr[1].Longitude)
+	}
+}
+
+func TestReadUnknown(t *testing.T) {
+	if _, err := Read(strings.NewReader("lat,long\n1,2\n")); err != ErrUnknownFormat {
+		t.Errorf("got %v, expected ErrUnknownFormat", err)
+	}
+}
+
+func TestPosition(t *testing.T) {
+	var base = time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	var tr = Track{
+		{Time: base, Latitude: 10, Longitude: 179, Altitude: 100, HasAltitude: true},
+		{Time: base.Add(time.Minute), Latitude: 11, Longitude: -179, Altitude: 200, HasAltitude: true},
+		{Time: base.Add(time.Hour), Latitude: 20, Longitude: -170},
+	}
+	tests := []struct {
+		at       time.Duration
+		ok       bool
+		lat, lng float64
+		alt      float64
+	}{
+		{0, true, 10, 179, 100},
+		{15 * time.Second, true, 10.25, 179.5, 125},
+		{45 * time.Second, true, 10.75, -179.5, 175},
+		{-4 * time.Minute, true, 10, 179, 100},         // before start, within maxGap
+		{-6 * time.Minute, false, 0, 0, 0},             // before start, too far
+		{5 * time.Minute, true, 11, -179, 200},         // in a long gap, near its start
+		{30 * time.Minute, false, 0, 0, 0},             // in the middle of a long gap
+		{57 * time.Minute, true, 20, -170, 0},          // in a long gap, near its end
+		{time.Hour + 5*time.Minute, true, 20, -170, 0}, // after end, within maxGap
+	}
+	for _, test := range tests {
+		p, ok := tr.Position(base.Add(test.at), 5*time.Minute)
+		if ok != test.ok {
+			t.Errorf("%s: ok = %v", test.at, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if math.Abs(p.Latitude-test.lat) > 1e-9 || math.Abs(p.Longitude-test.lng) > 1e-9 || math.Abs(p.Altitude-test.alt) > 1e-9 {
+			t.Errorf("%s: got %f, %f, %f", test.at, p.Latitude, p.Longitude, p.Altitude)
+		}
+		if !p.Time.Equal(base.Add(test.at)) {
+			t.Errorf("%s: time = %s", test.at, p.Time)
+		}
+	}
+	if _, ok := Track(nil).Position(base, time.Hour); ok {
+		t.Error("empty track: ok = true")
+	}
+}
+
+func TestDecodePolyline(t *testing.T) {
+	// The example from the Google Maps API documentation.
+	points, err := DecodePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][2]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+	if len(points) != len(expected) {
+		t.Fatalf("got %d points, expected %d", len(points), len(expected))
+	}
+	for i, e := range expected {
+		if math.Abs(points[i].Latitude-e[0]) > 1e-9 || math.Abs(points[i].Longitude-e[1]) > 1e-9 {
+			t.Errorf("point %d: got %f, %f", i, points[i].Latitude, points[i].Longitude)
+		}
+	}
+	for _, bad := range []string{"_p~iF", "_p~iF~ps|", "_p~iF~ps|U\x01"} {
+		if _, err := DecodePolyline(bad); err != ErrPolyline {
+			t.Errorf("%q: got %v, expected ErrPolyline", bad, err)
+		}
+	}
+}
+
+const testRecords = `{
+  "locations": [{
+    "latitudeE7": 374000000, "longitudeE7": -1221000000, "accuracy": 20,
+    "altitude": 15, "source": "WIFI", "timestamp": "2022-06-01T10:05:00.123Z"
+  }, {
+    "latitudeE7": 373000000, "longitudeE7": -1220000000, "accuracy": 1500,
+    "timestampMs": "1654077600000"
+  }, {
+    "latitudeE7": 3730000000, "longitudeE7": -1220000000, "timestampMs": "1654077660000"
+  }]
+}`
+
+const testSemantic = `{
+  "timelineObjects": [{
+    "placeVisit": {
+      "location": {
+        "latitudeE7": 374200000, "longitudeE7": -1221200000,
+        "placeId": "X", "address": "1 Main St, Springfield", "name": "Town Hall"
+      },
+      "duration": {
+        "startTimestamp": "2022-06-01T11:00:00Z", "endTimestamp": "2022-06-01T12:00:00Z"
+      },
+      "centerLatE7": 374210000, "centerLngE7": -1221210000
+    }
+  }, {
+    "activitySegment": {
+      "startLocation": { "latitudeE7": 374000000, "longitudeE7": -1221000000 },
+      "endLocation": { "latitudeE7": 374200000, "longitudeE7": -1221200000 },
+      "duration": {
+        "startTimestampMs": "1654077600000", "endTimestampMs": "1654081200000"
+      },
+      "simplifiedRawPath": { "points": [
+        { "latE7": 374100000, "lngE7": -1221100000, "accuracyMeters": 10, "timestamp": "2022-06-01T10:30:00Z" }
+      ] }
+    }
+  }]
+}`
+
+func TestReadTakeoutRecords(t *testing.T) {
+	tr, err := Read(strings.NewReader(testRecords))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr) != 2 {
+		t.Fatalf("got %d points, expected 2: %+v", len(tr), tr)
+	}
+	if !tr[0].Time.Equal(time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)) || tr[0].Latitude != 37.3 || tr[0].Accuracy != 1500 {
+		t.Errorf("point 0: got %+v", tr[0])
+	}
+	if !tr[1].Time.Equal(time.Date(2022, 6, 1, 10, 5, 0, 123e6, time.UTC)) || tr[1].Longitude != -122.1 || tr[1].Altitude != 15 || !tr[1].HasAltitude || tr[1].Accuracy != 20 {
+		t.Errorf("point 1: got %+v", tr[1])
+	}
+	if ft := tr.FilterAccuracy(100); len(ft) != 1 || ft[0].Accuracy != 20 {
+		t.Errorf("FilterAccuracy: got %+v", ft)
+	}
+}
+
+func TestReadTakeoutSemantic(t *testing.T) {
+	tr, visits, err := ReadTakeout(strings.NewReader(testSemantic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr) != 5 {
+		t.Fatalf("got %d points, expected 5: %+v", len(tr), tr)
+	}
+	if !tr[0].Time.Equal(time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)) || tr[0].Latitude != 37.4 {
+		t.Errorf("point 0: got %+v", tr[0])
+	}
+	if tr[1].Latitude != 37.41 || tr[1].Accuracy != 10 {
+		t.Errorf("point 1: got %+v", tr[1])
+	}
+	if tr[2].Latitude != 37.421 || tr[2].Longitude != -122.121 {
+		t.Errorf("point 2: got %+v", tr[2])
+	}
+	if len(visits) != 1 || visits[0].Name != "Town Hall" || visits[0].Address != "1 Main St, Springfield" {
+		t.Fatalf("visits: got %+v", visits)
+	}
+	if v, ok := visits.At(time.Date(2022, 6, 1, 11, 30, 0, 0, time.UTC)); !ok || v.Name != "Town Hall" {
+		t.Errorf("At during visit: got %+v, %v", v, ok)
+	}
+	if _, ok := visits.At(time.Date(2022, 6, 1, 12, 30, 0, 0, time.UTC)); ok {
+		t.Error("At after visit: ok = true")
+	}
+	if _, ok := visits.At(time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)); ok {
+		t.Error("At before visit: ok = true")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	var base = time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	var tr = Track{
+		{Time: base, Latitude: 10, Longitude: 20},
+		{Time: base.Add(time.Hour), Latitude: 11, Longitude: 21},
+	}
+	tests := []struct {
+		at  time.Duration
+		ok  bool
+		lat float64
+	}{
+		{-10 * time.Minute, true, 10},
+		{20 * time.Minute, true, 10},
+		{40 * time.Minute, true, 11},
+		{70 * time.Minute, true, 11},
+		{-30 * time.Minute, false, 0},
+		{90 * time.Minute, false, 0},
+	}
+	for _, test := range tests {
+		p, ok := tr.Nearest(base.Add(test.at), 25*time.Minute)
+		if ok != test.ok || p.Latitude != test.lat {
+			t.Errorf("%s: got %+v, %v", test.at, p, ok)
+		}
+	}
+}
@@ -106,18 +106,27 @@ func handleHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 type imgmd struct {
-	Filename string
-	Artist   string
-	Caption  string
-	DateTime string
-	GPS      string
-	Groups   []string
-	Keywords []string
-	Location string
-	People   []string
-	Places   []string
-	Title    string
-	Topics   []string
+	Filename    string
+	Artist      string
+	Caption     string
+	CaptionAlts []altValue
+	DateTime    string
+	GPS         string
+	Groups      []string
+	Keywords    []string
+	Location    string
+	People      []string
+	Places      []string
+	Title       string
+	TitleAlts   []altValue
+	Topics      []string
+}
+
+// altValue is a language alternative of a caption or title, other than the
+// default one.  These are displayed but not editable.
+type altValue struct {
+	Lang  string
+	Value string
 }
 
 type hier struct {
@@ -263,6 +272,7 @@ func metadataForImage(filename string, provider metadata.Provider) (md *imgmd) {
 	md.Filename = filename
 	md.Artist = provider.Creator()
 	md.Caption = provider.Caption()
+	md.CaptionAlts = altValues(provider.CaptionAlt())
 	md.DateTime = provider.DateTime().String()
 	if len(md.DateTime) > 10 {
 		md.DateTime = md.DateTime[:10] + " " + md.DateTime[11:]
@@ -308,6 +318,7 @@ func metadataForImage(filename string, provider metadata.Provider) (md *imgmd) {
 		md.Places = make([]string, 0)
 	}
 	md.Title = provider.Title()
+	md.TitleAlts = altValues(provider.TitleAlt())
 	for _, t := range provider.Topics() {
 		md.Topics = append(md.Topics, t.String())
 	}
@@ -317,6 +328,16 @@ func metadataForImage(filename string, provider metadata.Provider) (md *imgmd) {
 	return md
 }
 
+func altValues(as metadata.AltString) (avs []altValue) {
+	avs = make([]altValue, 0)
+	for i, alt := range as {
+		if i != 0 && alt.Lang != "x-default" && alt.Value != as.Default() {
+			avs = append(avs, altValue{alt.Lang, alt.Value})
+		}
+	}
+	return avs
+}
+
 type rdsk struct {
 	fs.File
 }
@@ -4,6 +4,7 @@ export interface Metadata {
   Filename: string
   Artist: string
   Caption: string
+  CaptionAlts: AltValue[]
   DateTime: string
   GPS: string
   Groups: string[]
@@ -12,9 +13,15 @@ export interface Metadata {
   People: string[]
   Places: string[]
   Title: string
+  TitleAlts: AltValue[]
   Topics: string[]
 }
 
+export interface AltValue {
+  Lang: string
+  Value: string
+}
+
 export interface Hier {
   Name: string
   Open?: boolean